@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/arnavsurve/dropstep/pkg/log/sinks"
+	"github.com/arnavsurve/dropstep/pkg/steprunner/runners/browseragent"
+	"github.com/rs/zerolog"
+)
+
+type AgentCmd struct {
+	Prefetch AgentPrefetchCmd `cmd:"" help:"Download and verify the browser_agent's pinned Python wheels ahead of time."`
+}
+
+// AgentPrefetchCmd warms the browser_agent's Python venv and wheel cache without running a
+// workflow, so a later `dropstep run --offline` has everything it needs already on disk.
+type AgentPrefetchCmd struct{}
+
+func (p *AgentPrefetchCmd) Run() error {
+	consoleSink := sinks.NewConsoleSink()
+	logRouter := log.NewRouter()
+	logRouter.AddSink(consoleSink)
+	cmdLogger := log.NewZerologAdapter(zerolog.New(logRouter).With().Timestamp().Logger())
+
+	if _, err := browseragent.NewSubprocessAgentRunner(cmdLogger); err != nil {
+		return fmt.Errorf("prefetching browser_agent dependencies: %w", err)
+	}
+
+	fmt.Println("browser_agent Python venv and wheel cache are up to date.")
+	return nil
+}
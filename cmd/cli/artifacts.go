@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/arnavsurve/dropstep/pkg/artifacts"
+)
+
+// artifactsDir matches the layout cmd/cli/run.go archives a run's artifacts under.
+const artifactsDir = ".dropstep/artifacts"
+
+// summaryDir matches the layout cmd/cli/run.go writes a run's Markdown step summary under.
+const summaryDir = ".dropstep/runs"
+
+func logFilePathFor(runID string) string {
+	return filepath.Join(logsDir, runID+".json")
+}
+
+type ArtifactsCmd struct {
+	RunID string          `arg:"" optional:"" help:"The run ID to list artifacts for."`
+	Get   ArtifactsGetCmd `cmd:"" help:"Extract a named artifact from a run into a directory."`
+}
+
+func (a *ArtifactsCmd) Run() error {
+	if a.RunID == "" {
+		return fmt.Errorf("usage: dropstep artifacts <run-id>")
+	}
+
+	entries, err := artifacts.ReadManifest(logFilePathFor(a.RunID))
+	if err != nil {
+		return fmt.Errorf("reading artifact manifest for run %q: %w", a.RunID, err)
+	}
+	if len(entries) == 0 {
+		fmt.Printf("No artifacts recorded for run %q\n", a.RunID)
+		return nil
+	}
+
+	for _, e := range entries {
+		fmt.Printf("%s/%s\t%s\t%d bytes\t%s\n", e.StepID, e.Name, e.SHA256, e.SizeBytes, e.ProducedAt.Format("2006-01-02T15:04:05Z07:00"))
+	}
+	return nil
+}
+
+type ArtifactsGetCmd struct {
+	RunID  string `arg:"" help:"The run ID the artifact was produced during."`
+	Ref    string `arg:"" help:"The artifact reference, as <step>/<name>."`
+	Output string `short:"o" help:"Directory to extract the artifact into." default:"."`
+}
+
+func (g *ArtifactsGetCmd) Run() error {
+	stepID, name, found := strings.Cut(g.Ref, "/")
+	if !found {
+		return fmt.Errorf("artifact reference %q must be in the form <step>/<name>", g.Ref)
+	}
+
+	if err := artifacts.Extract(artifactsDir, g.RunID, stepID, name, g.Output); err != nil {
+		return fmt.Errorf("extracting artifact %q: %w", g.Ref, err)
+	}
+
+	fmt.Printf("Extracted %s/%s into %q\n", stepID, name, g.Output)
+	return nil
+}
@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/arnavsurve/dropstep/pkg/cache"
+)
+
+// cacheDir matches the layout cmd/cli/run.go stores the step cache under.
+const cacheDir = ".dropstep/cache"
+
+type CacheCmd struct {
+	Prune CachePruneCmd `cmd:"" help:"Remove cache entries older than a given age."`
+}
+
+type CachePruneCmd struct {
+	OlderThan string `help:"Remove entries last used more than this long ago, e.g. 720h (30 days)." default:"720h" name:"older-than"`
+}
+
+func (p *CachePruneCmd) Run() error {
+	age, err := time.ParseDuration(p.OlderThan)
+	if err != nil {
+		return fmt.Errorf("parsing --older-than %q: %w", p.OlderThan, err)
+	}
+
+	store, err := cache.NewFilesystemStore(cacheDir)
+	if err != nil {
+		return fmt.Errorf("opening step cache at %q: %w", cacheDir, err)
+	}
+
+	removed, err := store.Prune(age)
+	if err != nil {
+		return fmt.Errorf("pruning step cache: %w", err)
+	}
+
+	fmt.Printf("Removed %d cache entr%s older than %s\n", removed, plural(removed), age)
+	return nil
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return "y"
+	}
+	return "ies"
+}
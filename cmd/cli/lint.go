@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,6 +9,8 @@ import (
 	"github.com/arnavsurve/dropstep/pkg/core"
 	"github.com/arnavsurve/dropstep/pkg/log"
 	"github.com/arnavsurve/dropstep/pkg/log/sinks"
+	"github.com/arnavsurve/dropstep/pkg/secrets"
+	"github.com/arnavsurve/dropstep/pkg/steplib"
 	"github.com/arnavsurve/dropstep/pkg/steprunner"
 	"github.com/arnavsurve/dropstep/pkg/types"
 	"github.com/joho/godotenv"
@@ -23,6 +26,15 @@ type LintCmd struct {
 }
 
 func (l *LintCmd) Run() error {
+	return lintWorkflow(l.Varfile, l.Workflow, nil)
+}
+
+// lintWorkflow loads workflowPath, resolves its varfile and providers, and validates its
+// structure, step dependency graph, and individual step configurations — the checks LintCmd and
+// ValidateCmd both need. extra, if non-nil, is called afterward with the resolved workflow and its
+// directory so a caller can layer further checks (e.g. ValidateCmd's output-schema check) on top
+// without repeating this setup.
+func lintWorkflow(varfile, workflowPath string, extra func(cmdLogger core.Logger, validationWf *core.Workflow, workflowDir string) error) error {
 	consoleSink := sinks.NewConsoleSink()
 
 	logRouter := log.NewRouter()
@@ -32,43 +44,49 @@ func (l *LintCmd) Run() error {
 	baseZerologInstance := zerolog.New(routerWriter).With().Timestamp().Logger()
 	cmdLogger := log.NewZerologAdapter(baseZerologInstance)
 
-	cmdLogger.Info().Msgf("Validating %s using %s", l.Workflow, l.Varfile)
+	cmdLogger.Info().Msgf("Validating %s using %s", workflowPath, varfile)
 
 	if err := godotenv.Load(); err != nil {
 		cmdLogger.Warn().Err(err).Msgf("No .env file found or error thrown while loading it. Relying on existing ENV if vars use {{ env.* }}")
 	}
 
-	wf, err := core.LoadWorkflowFromFile(l.Workflow)
+	wf, err := core.LoadWorkflowFromFile(workflowPath)
 	if err != nil {
-		cmdLogger.Error().Err(err).Msgf("Failed to load workflow file %s", l.Workflow)
-		return fmt.Errorf("loading workflow file %q: %w", l.Workflow, err)
+		cmdLogger.Error().Err(err).Msgf("Failed to load workflow file %s", workflowPath)
+		return fmt.Errorf("loading workflow file %q: %w", workflowPath, err)
 	}
 	cmdLogger.Info().Msgf("Successfully loaded workflow: %s", wf.Name)
 
-	workflowAbsPath, err := filepath.Abs(l.Workflow)
+	workflowAbsPath, err := filepath.Abs(workflowPath)
 	if err != nil {
-		cmdLogger.Error().Err(err).Msgf("Could not determine absolute path for workflow file %s", l.Workflow)
-		return fmt.Errorf("determining absolute path for workflow file %q: %w", l.Workflow, err)
+		cmdLogger.Error().Err(err).Msgf("Could not determine absolute path for workflow file %s", workflowPath)
+		return fmt.Errorf("determining absolute path for workflow file %q: %w", workflowPath, err)
 	}
 	workflowDir := filepath.Dir(workflowAbsPath)
 
+	secretsRegistry, err := secrets.NewRegistry(wf.Secrets, logRouter.AddSecretMask)
+	if err != nil {
+		cmdLogger.Error().Err(err).Msg("Failed to initialize secret providers")
+		return fmt.Errorf("initializing secret providers: %w", err)
+	}
+
 	var varCtx core.VarContext
-	if _, statErr := os.Stat(l.Varfile); os.IsNotExist(statErr) {
-		cmdLogger.Warn().Msgf("Varfile %s not found. Proceeding without global variables. Required inputs might fail validation if not in ENV.", l.Varfile)
+	if _, statErr := os.Stat(varfile); os.IsNotExist(statErr) {
+		cmdLogger.Warn().Msgf("Varfile %s not found. Proceeding without global variables. Required inputs might fail validation if not in ENV.", varfile)
 		varCtx = make(core.VarContext)
 	} else {
-		varCtx, err = core.ResolveVarfile(l.Varfile)
+		varCtx, err = core.ResolveVarfile(context.Background(), varfile, secretsRegistry, logRouter.AddSecretMask)
 		if err != nil {
-			cmdLogger.Warn().Err(err).Msgf("Could not fully resolve varfile %q. Some variable validations might be affected.", l.Varfile)
+			cmdLogger.Warn().Err(err).Msgf("Could not fully resolve varfile %q. Some variable validations might be affected.", varfile)
 			if varCtx == nil {
 				varCtx = make(core.VarContext)
 			}
 		} else {
-			cmdLogger.Info().Msgf("Successfully loaded and resolved varfile: %s", l.Varfile)
+			cmdLogger.Info().Msgf("Successfully loaded and resolved varfile: %s", varfile)
 		}
 	}
 
-	if err := core.ValidateRequiredInputs(wf, varCtx); err != nil {
+	if err := core.ValidateRequiredInputs(wf, varCtx, workflowDir); err != nil {
 		cmdLogger.Error().Err(err).Msgf("Required input validation failed")
 		return fmt.Errorf("validating required inputs: %w", err)
 	}
@@ -76,7 +94,7 @@ func (l *LintCmd) Run() error {
 
 	cmdLogger.Info().Msgf("Validating providers...")
 	for _, p := range wf.Providers {
-		if _, err := core.ResolveProviderVariables(&p, varCtx); err != nil {
+		if _, err := core.ResolveProviderVariables(&p, varCtx, wf.Inputs...); err != nil {
 			cmdLogger.Error().Err(err).Msgf("Provider %q has a configuration issue", p.Name)
 			return fmt.Errorf("resolving variables for provider %q: %w", p.Name, err)
 		}
@@ -89,6 +107,12 @@ func (l *LintCmd) Run() error {
 		return fmt.Errorf("resolving global variables for workflow: %w", err)
 	}
 
+	if err := core.ValidateStepDependencies(validationWf); err != nil {
+		cmdLogger.Error().Err(err).Msg("Step depends_on validation failed")
+		return fmt.Errorf("validating step dependencies: %w", err)
+	}
+	cmdLogger.Info().Msgf("Step dependency graph is valid")
+
 	cmdLogger.Info().Msgf("Validating individual steps...")
 	for _, stepConfig := range validationWf.Steps {
 		stepLogger := cmdLogger.With().
@@ -98,13 +122,25 @@ func (l *LintCmd) Run() error {
 
 		stepLogger.Info().Msg("Validating step configuration...")
 
+		// A steplib reference (see pkg/steplib) only resolves by cloning over the network and
+		// writing to its workflow's lockfile; lint should stay read-only and work offline, so just
+		// check that the reference parses rather than actually resolving it.
+		if steplib.IsRef(stepConfig.Uses) {
+			if _, err := steplib.ParseRef(stepConfig.Uses); err != nil {
+				stepLogger.Error().Err(err).Msg("Invalid step library reference")
+				return fmt.Errorf("step %q: %w", stepConfig.ID, err)
+			}
+			stepLogger.Info().Msg("Step library reference syntax is valid")
+			continue
+		}
+
 		execCtx := types.ExecutionContext{
 			Step:        stepConfig,
 			Logger:      stepLogger,
 			WorkflowDir: workflowDir,
 		}
 
-		runner, err := steprunner.GetRunner(execCtx)
+		runner, err := steprunner.GetRunner(context.Background(), execCtx)
 		if err != nil {
 			stepLogger.Error().Err(err).Msg("Error getting runner for step")
 			return fmt.Errorf("getting runner for step %q: %w", stepConfig.ID, err)
@@ -118,6 +154,12 @@ func (l *LintCmd) Run() error {
 		stepLogger.Info().Msg("Step configuration validation passed")
 	}
 
+	if extra != nil {
+		if err := extra(cmdLogger, validationWf, workflowDir); err != nil {
+			return err
+		}
+	}
+
 	cmdLogger.Info().Msg("Successfully validated workflow configuration ✅")
 	return nil
 }
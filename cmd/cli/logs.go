@@ -0,0 +1,258 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/arnavsurve/dropstep/pkg/log/sinks"
+	"github.com/arnavsurve/dropstep/pkg/types"
+	"github.com/fsnotify/fsnotify"
+)
+
+// logsDir matches the layout cmd/cli/run.go writes a run's file sink under.
+const logsDir = ".dropstep/logs"
+
+// defaultTailLines is how many trailing lines `dropstep logs tail <run-id>` prints without -f.
+const defaultTailLines = 20
+
+type LogsCmd struct {
+	List LogsListCmd `cmd:"" help:"List recorded workflow runs."`
+	Show LogsShowCmd `cmd:"" help:"Replay a run's logs with the same formatting as a live run."`
+	Tail LogsTailCmd `cmd:"" help:"Print or follow the tail of a run's logs."`
+}
+
+// logFilterFlags are the filter flags shared by `logs show` and `logs tail`.
+type logFilterFlags struct {
+	Step   string        `help:"Only show events from this step ID."`
+	Level  string        `help:"Minimum level to show (debug, info, warn, error, fatal). A trailing '+' is accepted, e.g. 'warn+'."`
+	Source string        `help:"Only show events from this source (e.g. 'agent', 'shell', 'python')."`
+	Since  time.Duration `help:"Only show events from the last duration, e.g. 5m."`
+}
+
+// compile builds a matcher function from the flags, resolving --since against now.
+func (f logFilterFlags) compile() (func(*log.LogEvent) bool, error) {
+	minLevel := types.DebugLevel
+	if f.Level != "" {
+		lvl, ok := parseLevel(strings.TrimSuffix(f.Level, "+"))
+		if !ok {
+			return nil, fmt.Errorf("invalid --level %q", f.Level)
+		}
+		minLevel = lvl
+	}
+
+	var sinceTime time.Time
+	if f.Since > 0 {
+		sinceTime = time.Now().Add(-f.Since)
+	}
+
+	return func(e *log.LogEvent) bool {
+		if e.Level < minLevel {
+			return false
+		}
+		if f.Step != "" && eventField(e, "step_id") != f.Step {
+			return false
+		}
+		if f.Source != "" && eventField(e, "source") != f.Source {
+			return false
+		}
+		if !sinceTime.IsZero() && e.Timestamp.Before(sinceTime) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+func eventField(e *log.LogEvent, key string) string {
+	if v, ok := e.Fields[key].(string); ok {
+		return v
+	}
+	return ""
+}
+
+func parseLevel(s string) (types.Level, bool) {
+	switch strings.ToLower(s) {
+	case "debug":
+		return types.DebugLevel, true
+	case "info":
+		return types.InfoLevel, true
+	case "warn", "warning":
+		return types.WarnLevel, true
+	case "error":
+		return types.ErrorLevel, true
+	case "fatal":
+		return types.FatalLevel, true
+	default:
+		return 0, false
+	}
+}
+
+type LogsListCmd struct{}
+
+func (c *LogsListCmd) Run() error {
+	entries, err := os.ReadDir(logsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Println("No runs recorded yet")
+			return nil
+		}
+		return fmt.Errorf("reading logs directory %q: %w", logsDir, err)
+	}
+
+	var runIDs []string
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		runIDs = append(runIDs, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(runIDs)
+
+	for _, runID := range runIDs {
+		events, err := sinks.ReadEvents(logFilePathFor(runID))
+		if err != nil || len(events) == 0 {
+			fmt.Printf("%s\tunknown\tunknown\tunknown\n", runID)
+			continue
+		}
+
+		start := events[0].Timestamp
+		end := events[len(events)-1].Timestamp
+		workflowName := "unknown"
+		status := "unknown"
+		for _, e := range events {
+			if name, ok := strings.CutPrefix(e.Message, "Successfully loaded workflow: "); ok {
+				workflowName = strings.Trim(name, `"`)
+			}
+			if e.Level >= types.ErrorLevel {
+				status = "failed"
+			}
+		}
+		if status == "unknown" {
+			for _, e := range events {
+				if e.Message == "Workflow completed successfully." || strings.HasPrefix(e.Message, "Workflow completed successfully") {
+					status = "ok"
+				}
+			}
+		}
+
+		fmt.Printf("%s\t%s\t%s\t%s\t%s\n", runID, workflowName, start.Format(time.RFC3339), end.Format(time.RFC3339), status)
+	}
+	return nil
+}
+
+type LogsShowCmd struct {
+	RunID string `arg:"" help:"The run ID to replay."`
+	logFilterFlags
+}
+
+func (c *LogsShowCmd) Run() error {
+	match, err := c.compile()
+	if err != nil {
+		return err
+	}
+
+	events, err := sinks.ReadEvents(logFilePathFor(c.RunID))
+	if err != nil {
+		return fmt.Errorf("reading logs for run %q: %w", c.RunID, err)
+	}
+
+	for _, e := range events {
+		if match(e) {
+			fmt.Println(sinks.RenderEvent(e))
+		}
+	}
+	return nil
+}
+
+type LogsTailCmd struct {
+	RunID  string `arg:"" help:"The run ID to tail."`
+	Follow bool   `short:"f" help:"Follow the run's log file as it grows, like 'tail -f'."`
+	logFilterFlags
+}
+
+func (c *LogsTailCmd) Run() error {
+	match, err := c.compile()
+	if err != nil {
+		return err
+	}
+
+	path := logFilePathFor(c.RunID)
+	events, err := sinks.ReadEvents(path)
+	if err != nil {
+		return fmt.Errorf("reading logs for run %q: %w", c.RunID, err)
+	}
+
+	start := 0
+	if !c.Follow && len(events) > defaultTailLines {
+		start = len(events) - defaultTailLines
+	}
+	for _, e := range events[start:] {
+		if match(e) {
+			fmt.Println(sinks.RenderEvent(e))
+		}
+	}
+
+	if !c.Follow {
+		return nil
+	}
+
+	return followFile(path, len(events), match)
+}
+
+// followFile watches path for appended lines past the first skipLines events already printed, in
+// the style of `tail -f`, printing each new matching event as it's written.
+func followFile(path string, skipLines int, match func(*log.LogEvent) bool) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("creating file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		return fmt.Errorf("watching %q: %w", filepath.Dir(path), err)
+	}
+
+	printNewEvents := func() error {
+		events, err := sinks.ReadEvents(path)
+		if err != nil {
+			return err
+		}
+		if len(events) <= skipLines {
+			return nil
+		}
+		for _, e := range events[skipLines:] {
+			if match(e) {
+				fmt.Println(sinks.RenderEvent(e))
+			}
+		}
+		skipLines = len(events)
+		return nil
+	}
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Name != path {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			if err := printNewEvents(); err != nil {
+				return fmt.Errorf("reading appended logs: %w", err)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			return fmt.Errorf("watching %q: %w", path, err)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/arnavsurve/dropstep/pkg/core"
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/arnavsurve/dropstep/pkg/log/sinks"
+	"github.com/arnavsurve/dropstep/pkg/planner"
+	"github.com/joho/godotenv"
+	"github.com/rs/zerolog"
+)
+
+type PlanCmd struct {
+	Workflow string `help:"The workflow configuration file." default:"dropstep.yml"`
+	Event    string `help:"Trigger event to plan for (e.g. schedule, webhook, manual)." default:"manual"`
+	Payload  string `help:"Path to a JSON event payload file (e.g. a webhook body)."`
+}
+
+// Run prints the ordered subset of Workflow's steps that pd.Event would select, without
+// executing anything. Useful for inspecting what a cron trigger or an incoming webhook would
+// actually run before wiring it up.
+func (pd *PlanCmd) Run() error {
+	consoleSink := sinks.NewConsoleSink()
+	logRouter := log.NewRouter()
+	logRouter.AddSink(consoleSink)
+	cmdLogger := log.NewZerologAdapter(zerolog.New(logRouter).With().Timestamp().Logger())
+
+	if err := godotenv.Load(); err != nil {
+		cmdLogger.Warn().Err(err).Msgf("No .env file found or error thrown while loading it. Relying on existing ENV if vars use {{ env.* }}")
+	}
+
+	wf, err := core.LoadWorkflowFromFile(pd.Workflow)
+	if err != nil {
+		cmdLogger.Error().Err(err).Msgf("Failed to load workflow file %s", pd.Workflow)
+		return fmt.Errorf("loading workflow file %q: %w", pd.Workflow, err)
+	}
+
+	var payload map[string]any
+	if pd.Payload != "" {
+		payloadBytes, err := os.ReadFile(pd.Payload)
+		if err != nil {
+			return fmt.Errorf("reading event payload file %q: %w", pd.Payload, err)
+		}
+		payload, err = planner.LoadPayload(payloadBytes)
+		if err != nil {
+			return err
+		}
+	}
+
+	plan, err := planner.Plan(wf, planner.Event{Trigger: pd.Event, Payload: payload})
+	if err != nil {
+		return fmt.Errorf("planning workflow for event %q: %w", pd.Event, err)
+	}
+
+	fmt.Printf("Plan for %q, trigger %q (%d of %d step(s) selected):\n\n", wf.Name, plan.Trigger, len(plan.Steps), len(wf.Steps))
+	for i, step := range plan.Steps {
+		line := fmt.Sprintf("%d. %s (uses=%s)", i+1, step.ID, step.Uses)
+		if len(step.DependsOn) > 0 {
+			line += fmt.Sprintf(" depends_on=%v", step.DependsOn)
+		}
+		if step.If != "" {
+			line += fmt.Sprintf(" if=%q", step.If)
+		}
+		if step.Unless != "" {
+			line += fmt.Sprintf(" unless=%q", step.Unless)
+		}
+		fmt.Println(line)
+	}
+
+	return nil
+}
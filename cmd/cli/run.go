@@ -1,14 +1,24 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
+	"time"
 
+	"github.com/arnavsurve/dropstep/pkg/cache"
+	"github.com/arnavsurve/dropstep/pkg/cas"
 	"github.com/arnavsurve/dropstep/pkg/core"
 	"github.com/arnavsurve/dropstep/pkg/log"
 	"github.com/arnavsurve/dropstep/pkg/log/sinks"
+	"github.com/arnavsurve/dropstep/pkg/planner"
+	"github.com/arnavsurve/dropstep/pkg/secrets"
 	"github.com/arnavsurve/dropstep/pkg/security"
+	"github.com/arnavsurve/dropstep/pkg/summary"
+	"github.com/arnavsurve/dropstep/pkg/tracing"
 	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog"
@@ -18,8 +28,25 @@ import (
 )
 
 type RunCmd struct {
-	Varfile  string `help:"The YAML varfile for input variables." default:"dsvars.yml"`
-	Workflow string `help:"The workflow configuration file." default:"dropstep.yml"`
+	Varfile    string `help:"The YAML varfile for input variables." default:"dsvars.yml"`
+	Workflow   string `help:"The workflow configuration file." default:"dropstep.yml"`
+	NoCache    bool   `help:"Ignore the step cache and re-run every step." default:"false" env:"DROPSTEP_NO_CACHE"`
+	Event      string `help:"Trigger event for this run (e.g. schedule, webhook, manual). Steps with a narrower 'on:' are skipped if it doesn't match." default:"manual"`
+	Payload    string `help:"Path to a JSON event payload file (e.g. a webhook body), available to steps as {{ event.<path> }}."`
+	Offline    bool   `help:"Fail instead of reaching the network for anything cacheable, e.g. a browser_agent step's Python wheels." default:"false" env:"DROPSTEP_OFFLINE"`
+	NoProgress bool   `help:"Disable live per-step progress bars even when running in a terminal." default:"false" name:"no-progress"`
+	Silent     bool   `help:"Suppress non-error informational output." default:"false"`
+
+	// OtlpEndpoint, if set, exports an OpenTelemetry trace of this run (one span per step, plus
+	// HttpRunner request spans with W3C traceparent propagation) to a collector at this OTLP/HTTP
+	// endpoint (host:port, no scheme). Empty (the default) leaves tracing at otel's own no-op
+	// implementation, so a run with no collector configured pays no tracing cost.
+	OtlpEndpoint string `help:"OTLP/HTTP collector endpoint (host:port) to export a trace of this run to. Empty disables tracing." default:"" name:"otlp-endpoint" env:"DROPSTEP_OTLP_ENDPOINT"`
+	OtlpInsecure bool   `help:"Disable TLS when talking to --otlp-endpoint." default:"false" name:"otlp-insecure" env:"DROPSTEP_OTLP_INSECURE"`
+
+	LogMaxSizeMB   int `help:"Rotate this run's log file once it reaches this size in MB. 0 disables size-based rotation." default:"0" name:"log-max-size-mb"`
+	LogMaxAgeHours int `help:"Prune rotated log segments older than this many hours. 0 keeps them regardless of age." default:"0" name:"log-max-age-hours"`
+	LogMaxBackups  int `help:"Number of rotated, gzip-compressed log segments to retain. 0 keeps them all." default:"0" name:"log-max-backups"`
 }
 
 func getFallbackKey(providerType string) string {
@@ -32,16 +59,41 @@ func getFallbackKey(providerType string) string {
 }
 
 func (r *RunCmd) Run() error {
+	if r.Offline {
+		os.Setenv("DROPSTEP_OFFLINE", "1")
+	}
+
 	wfRunID := uuid.New().String()
 
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		OTLPEndpoint: r.OtlpEndpoint,
+		Insecure:     r.OtlpInsecure,
+	})
+	if err != nil {
+		return fmt.Errorf("initializing tracing: %w", err)
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			fmt.Printf("Error shutting down tracing: %v", err)
+		}
+	}()
+
 	consoleSink := sinks.NewConsoleSink()
+	summarySink := sinks.NewSummarySink()
 
-	logsDir := ".dropstep/logs"
 	if err := os.MkdirAll(logsDir, 0755); err != nil {
 		return fmt.Errorf("creating logs directory %q: %w", logsDir, err)
 	}
 	logFilePath := filepath.Join(logsDir, fmt.Sprintf("%s.json", wfRunID))
-	fileSink, err := sinks.NewFileSink(logFilePath)
+	// A rotating sink is used unconditionally; with all of LogMaxSizeMB, LogMaxAgeHours, and
+	// LogMaxBackups left at their defaults it behaves the same as the old never-rotating FileSink.
+	fileSink, err := sinks.NewRotatingFileSink(sinks.RotatingFileSinkConfig{
+		Path:         logFilePath,
+		MaxSizeBytes: int64(r.LogMaxSizeMB) * 1024 * 1024,
+		MaxAge:       time.Duration(r.LogMaxAgeHours) * time.Hour,
+		MaxBackups:   r.LogMaxBackups,
+		Compress:     true,
+	})
 	if err != nil {
 		return fmt.Errorf("creating file log sink: %w", err)
 	}
@@ -49,6 +101,7 @@ func (r *RunCmd) Run() error {
 	logRouter := log.NewRouter()
 	logRouter.AddSink(consoleSink)
 	logRouter.AddSink(fileSink)
+	logRouter.AddSink(summarySink)
 
 	routerWriter := logRouter
 	baseZerologInstance := zerolog.New(routerWriter).With().Timestamp().Logger()
@@ -84,12 +137,46 @@ func (r *RunCmd) Run() error {
 	}
 	workflowDir := filepath.Dir(workflowAbsPath)
 
+	var payload map[string]any
+	if r.Payload != "" {
+		payloadBytes, err := os.ReadFile(r.Payload)
+		if err != nil {
+			cmdLogger.Error().Err(err).Msgf("Failed to read event payload file %s", r.Payload)
+			return fmt.Errorf("reading event payload file %q: %w", r.Payload, err)
+		}
+		payload, err = planner.LoadPayload(payloadBytes)
+		if err != nil {
+			cmdLogger.Error().Err(err).Msg("Failed to parse event payload")
+			return err
+		}
+	}
+
+	plan, err := planner.Plan(wf, planner.Event{Trigger: r.Event, Payload: payload})
+	if err != nil {
+		cmdLogger.Error().Err(err).Msg("Failed to plan workflow for event")
+		return fmt.Errorf("planning workflow for event %q: %w", r.Event, err)
+	}
+	wf.Steps = plan.Steps
+	cmdLogger.Info().Msgf("Planned %d step(s) for trigger %q", len(wf.Steps), r.Event)
+
+	// A first SIGINT/SIGTERM cancels ctx so steps (and any in-flight secret lookups) can shut
+	// down gracefully; per signal.NotifyContext, a second one reverts to the OS default
+	// (immediate termination).
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	secretsRegistry, err := secrets.NewRegistry(wf.Secrets, logRouter.AddSecretMask)
+	if err != nil {
+		cmdLogger.Error().Err(err).Msg("Failed to initialize secret providers")
+		return fmt.Errorf("initializing secret providers: %w", err)
+	}
+
 	var varCtx core.VarContext
 	if _, statErr := os.Stat(r.Varfile); os.IsNotExist(statErr) {
 		cmdLogger.Warn().Msgf("Varfile %s not found. Proceeding without global variables. Required inputs might fail validation if not in ENV.", r.Varfile)
 		varCtx = make(core.VarContext)
 	} else {
-		varCtx, err = core.ResolveVarfile(r.Varfile)
+		varCtx, err = core.ResolveVarfile(ctx, r.Varfile, secretsRegistry, logRouter.AddSecretMask)
 		if err != nil {
 			cmdLogger.Warn().Err(err).Msgf("Could not fully resolve varfile %q. Some variable validations might be affected.", r.Varfile)
 			if varCtx == nil {
@@ -108,20 +195,26 @@ func (r *RunCmd) Run() error {
 		}
 	}
 
+	// Merge the event's flattened payload in so steps can reference {{ event.<path> }} through
+	// the same template engine as any other variable.
+	for k, v := range plan.EventVars {
+		varCtx[k] = v
+	}
+
 	// Validate required input variables
-	if err := core.ValidateRequiredInputs(wf, varCtx); err != nil {
+	if err := core.ValidateRequiredInputs(wf, varCtx, workflowDir); err != nil {
 		cmdLogger.Error().Err(err).Msgf("Required input validation failed")
 		return err
 	}
 	cmdLogger.Info().Msgf("Required input validation passed")
 
 	// Initialize and attach secrets redactor
-	logRouter.Redactor = security.NewRedactor(wf.Inputs, varCtx)
+	logRouter.SetRedactor(security.NewRedactor(wf.Inputs, varCtx))
 
 	// Resolve workflow providers
 	resolvedProviders := make(map[string]core.ProviderConfig)
 	for _, p := range wf.Providers {
-		resolvedP, err := core.ResolveProviderVariables(&p, varCtx)
+		resolvedP, err := core.ResolveProviderVariables(&p, varCtx, wf.Inputs...)
 		if err != nil {
 			cmdLogger.Error().Err(err).Msgf("Failed to resolve variables for provider %q", p.Name)
 			return fmt.Errorf("resolving variables for provider %q: %w", p.Name, err)
@@ -159,11 +252,44 @@ func (r *RunCmd) Run() error {
 	cmdLogger.Info().Msgf("Executing workflow: %q", wf.Name)
 
 	engine := core.NewWorkflowEngine(cmdLogger)
-	_, err = engine.ExecuteWorkflow(wf, varCtx, nil, workflowDir, resolvedProviders)
+	if cacheStore, err := cache.NewFilesystemStore(filepath.Join(".dropstep", "cache")); err != nil {
+		cmdLogger.Warn().Err(err).Msg("Could not initialize step cache. Continuing without caching.")
+	} else {
+		engine.Cache = cacheStore
+	}
+	engine.NoCache = r.NoCache
+	engine.NoProgress = r.NoProgress
+	engine.Silent = r.Silent
+	engine.MaskFunc = logRouter.AddSecretMask
+	var extraPath core.PathAccumulator
+	engine.PathFunc = extraPath.Add
+	engine.PathDirs = extraPath.Dirs
+	engine.RunID = wfRunID
+	engine.ArtifactsDir = artifactsDir
+	engine.SummaryDir = summaryDir
+	engine.SummarySink = summarySink
+
+	if wf.Execution != nil && wf.Execution.Remote != nil {
+		blobs, err := cas.NewFilesystemStore(filepath.Join(".dropstep", "cas"))
+		if err != nil {
+			cmdLogger.Warn().Err(err).Msg("Could not initialize remote execution CAS. Falling back to local execution.")
+		} else if results, err := cache.NewFilesystemStore(filepath.Join(".dropstep", "remote-cache")); err != nil {
+			cmdLogger.Warn().Err(err).Msg("Could not initialize remote execution cache. Falling back to local execution.")
+		} else {
+			engine.RemoteBlobs = blobs
+			engine.RemoteResults = results
+		}
+	}
+
+	_, _, err = engine.ExecuteWorkflow(ctx, wf, varCtx, nil, workflowDir, resolvedProviders)
 	if err != nil {
 		return err
 	}
 
 	cmdLogger.Info().Msgf("Workflow completed successfully. Logs can be found at %q", logFilePath)
+	cmdLogger.Info().Msgf("Step summary report written to %q", summary.ReportPath(summaryDir, wfRunID))
+	if stats := secretsRegistry.Stats(); stats.Hits+stats.Misses > 0 {
+		cmdLogger.Debug().Msgf("Secret cache: %d hit(s), %d miss(es)", stats.Hits, stats.Misses)
+	}
 	return nil
 }
@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/arnavsurve/dropstep/pkg/core"
+
+	// Ensure all runner implementations are initialized
+	_ "github.com/arnavsurve/dropstep/pkg/steprunner/runners"
+)
+
+// ValidateCmd runs everything LintCmd does, plus a static type-check of every
+// "{{ steps.<id>.output.<path> }}" reference against the producing step's declared
+// browser.output_schema. It's a separate, CI-friendly subcommand (rather than folded into Lint)
+// because the output-schema check reads each schema file from disk and so is slower and more
+// failure-prone than the rest of lint's purely structural checks.
+type ValidateCmd struct {
+	Varfile  string `help:"The YAML varfile for input variables." default:"dsvars.yml"`
+	Workflow string `help:"The workflow configuration file." default:"dropstep.yml"`
+}
+
+func (v *ValidateCmd) Run() error {
+	return lintWorkflow(v.Varfile, v.Workflow, func(cmdLogger core.Logger, validationWf *core.Workflow, workflowDir string) error {
+		cmdLogger.Info().Msgf("Type-checking step output references against declared output schemas...")
+		if err := core.ValidateStepOutputReferences(validationWf, workflowDir); err != nil {
+			cmdLogger.Error().Err(err).Msg("Step output reference validation failed")
+			return fmt.Errorf("validating step output references: %w", err)
+		}
+		cmdLogger.Info().Msgf("Step output reference validation passed")
+		return nil
+	})
+}
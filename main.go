@@ -8,8 +8,14 @@ import (
 )
 
 var CLI struct {
-	Run  cli.RunCmd  `cmd:"" help:"Run a Dropstep workflow."`
-	Lint cli.LintCmd `cmd:"" help:"Validate the Dropstep workflow file syntax."`
+	Run       cli.RunCmd       `cmd:"" help:"Run a Dropstep workflow."`
+	Plan      cli.PlanCmd      `cmd:"" help:"Print the steps a trigger event would run, without executing them."`
+	Agent     cli.AgentCmd     `cmd:"" help:"Manage local browser_agent dependencies."`
+	Lint      cli.LintCmd      `cmd:"" help:"Validate the Dropstep workflow file syntax."`
+	Validate  cli.ValidateCmd  `cmd:"" help:"Validate a workflow, including step output references against declared output schemas. Suitable for CI."`
+	Cache     cli.CacheCmd     `cmd:"" help:"Inspect or prune the step cache."`
+	Artifacts cli.ArtifactsCmd `cmd:"" help:"List or extract artifacts produced by a workflow run."`
+	Logs      cli.LogsCmd      `cmd:"" help:"List, replay, or tail the logs of a workflow run."`
 }
 
 func main() {
@@ -0,0 +1,86 @@
+// Package argutil helps runners compose argv slices from two sources — handler-injected defaults
+// and user-supplied overrides — without passing the same flag to the underlying command twice.
+package argutil
+
+import "strings"
+
+// MergeArgs combines a handler's default argv (defaults) with user-supplied argv (user),
+// suppressing any default flag the user has already specified themselves so the user's value wins
+// instead of both being passed to the underlying command. It returns the merged argv, followed by
+// the names of any default flags it suppressed (e.g. for the caller to log a debug line per
+// suppression).
+//
+// Flags are recognized in the forms "--flag=value", "--flag value", "-f value", and boolean flags
+// with no value. A literal "--" argument terminates flag parsing for the slice it appears in;
+// everything from that point on is treated as positional and passed through unchanged. Flag names
+// listed in repeatable are allowed to appear in both defaults and user without suppression (e.g.
+// "-e" for an environment variable flag that's legitimately passed more than once).
+func MergeArgs(defaults, user []string, repeatable ...string) (merged []string, suppressed []string) {
+	allow := make(map[string]bool, len(repeatable))
+	for _, f := range repeatable {
+		allow[f] = true
+	}
+
+	userFlags := flagNames(user)
+
+	var kept []string
+	for i := 0; i < len(defaults); {
+		tok := defaults[i]
+		if tok == "--" {
+			kept = append(kept, defaults[i:]...)
+			break
+		}
+
+		name, isFlag := flagName(tok)
+		if !isFlag {
+			kept = append(kept, tok)
+			i++
+			continue
+		}
+
+		consumed := 1
+		if !strings.Contains(tok, "=") && i+1 < len(defaults) {
+			if _, nextIsFlag := flagName(defaults[i+1]); !nextIsFlag {
+				consumed = 2
+			}
+		}
+
+		if userFlags[name] && !allow[name] {
+			suppressed = append(suppressed, name)
+			i += consumed
+			continue
+		}
+
+		kept = append(kept, defaults[i:i+consumed]...)
+		i += consumed
+	}
+
+	merged = append(kept, user...)
+	return merged, suppressed
+}
+
+// flagNames returns the set of flag names present in args, stopping at a literal "--" terminator.
+func flagNames(args []string) map[string]bool {
+	names := make(map[string]bool)
+	for _, tok := range args {
+		if tok == "--" {
+			break
+		}
+		if name, isFlag := flagName(tok); isFlag {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// flagName extracts a token's flag name (e.g. "--foo" from both "--foo" and "--foo=bar"),
+// reporting whether the token is a flag at all. A bare "-" or "--" is not a flag.
+func flagName(tok string) (string, bool) {
+	if !strings.HasPrefix(tok, "-") || tok == "-" || tok == "--" {
+		return "", false
+	}
+	if idx := strings.Index(tok, "="); idx != -1 {
+		return tok[:idx], true
+	}
+	return tok, true
+}
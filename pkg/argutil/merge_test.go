@@ -0,0 +1,101 @@
+package argutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeArgs(t *testing.T) {
+	tests := []struct {
+		name           string
+		defaults       []string
+		user           []string
+		repeatable     []string
+		wantMerged     []string
+		wantSuppressed []string
+	}{
+		{
+			name:       "no overlap appends user after defaults",
+			defaults:   []string{"--quiet"},
+			user:       []string{"--verbose"},
+			wantMerged: []string{"--quiet", "--verbose"},
+		},
+		{
+			name:           "user overrides long flag with equals form",
+			defaults:       []string{"--output=text"},
+			user:           []string{"--output=json"},
+			wantMerged:     []string{"--output=json"},
+			wantSuppressed: []string{"--output"},
+		},
+		{
+			name:           "user overrides long flag with space form",
+			defaults:       []string{"--output", "text"},
+			user:           []string{"--output", "json"},
+			wantMerged:     []string{"--output", "json"},
+			wantSuppressed: []string{"--output"},
+		},
+		{
+			name:           "user overrides short flag with space form",
+			defaults:       []string{"-o", "text"},
+			user:           []string{"-o", "json"},
+			wantMerged:     []string{"-o", "json"},
+			wantSuppressed: []string{"-o"},
+		},
+		{
+			name:           "mismatched forms still match by flag name",
+			defaults:       []string{"--output=text"},
+			user:           []string{"--output", "json"},
+			wantMerged:     []string{"--output", "json"},
+			wantSuppressed: []string{"--output"},
+		},
+		{
+			name:           "boolean default flag is suppressed",
+			defaults:       []string{"--strict"},
+			user:           []string{"--strict=false"},
+			wantMerged:     []string{"--strict=false"},
+			wantSuppressed: []string{"--strict"},
+		},
+		{
+			name:       "repeatable allowlisted flag is not suppressed",
+			defaults:   []string{"-e", "FOO=bar"},
+			user:       []string{"-e", "BAZ=qux"},
+			repeatable: []string{"-e"},
+			wantMerged: []string{"-e", "FOO=bar", "-e", "BAZ=qux"},
+		},
+		{
+			name:       "positional defaults are always kept",
+			defaults:   []string{"run.sh", "--quiet"},
+			user:       []string{"--quiet"},
+			wantMerged: []string{"run.sh", "--quiet"},
+			wantSuppressed: []string{
+				"--quiet",
+			},
+		},
+		{
+			name:       "terminator stops flag parsing on the user side",
+			defaults:   []string{"--output=text"},
+			user:       []string{"--", "--output=json"},
+			wantMerged: []string{"--output=text", "--", "--output=json"},
+		},
+		{
+			name:       "terminator stops flag parsing on the defaults side",
+			defaults:   []string{"--", "--output=text"},
+			user:       []string{"--output=json"},
+			wantMerged: []string{"--", "--output=text", "--output=json"},
+		},
+		{
+			name:     "empty defaults and user",
+			defaults: nil,
+			user:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, suppressed := MergeArgs(tt.defaults, tt.user, tt.repeatable...)
+			assert.Equal(t, tt.wantMerged, merged)
+			assert.ElementsMatch(t, tt.wantSuppressed, suppressed)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+// Package artifacts archives the files a step declares as produced (its `artifacts:` block) into a
+// per-run tar.gz, and extracts them back into a later step's working directory when that step
+// lists the artifact under its `artifact_deps:` block. This lets agent/scrape steps hand files
+// (screenshots, scraped JSON, etc.) to later shell/python steps without the workflow author
+// hand-wiring absolute paths between them.
+package artifacts
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Entry describes one archived artifact. It is the shape logged to the run's JSON log stream
+// (under the "artifact" field) when it is produced, so `dropstep artifacts <run-id>` can
+// reconstruct the manifest by replaying that run's log file rather than maintaining a separate
+// manifest store.
+type Entry struct {
+	Name       string    `json:"name"`
+	StepID     string    `json:"step_id"`
+	SHA256     string    `json:"sha256"`
+	SizeBytes  int64     `json:"size_bytes"`
+	ProducedAt time.Time `json:"produced_at"`
+}
+
+// ArchivePath returns the on-disk location of the tar.gz for the artifact named name, produced by
+// stepID during run runID, rooted at baseDir (e.g. ".dropstep/artifacts").
+func ArchivePath(baseDir, runID, stepID, name string) string {
+	return filepath.Join(baseDir, runID, stepID, name+".tar.gz")
+}
+
+// Archive tars+gzips paths (resolved file or directory paths, not globs) into
+// ArchivePath(baseDir, runID, stepID, name) and returns the resulting Entry.
+func Archive(baseDir, runID, stepID, name string, paths []string) (Entry, error) {
+	archivePath := ArchivePath(baseDir, runID, stepID, name)
+	if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+		return Entry{}, fmt.Errorf("creating artifact directory for %q: %w", name, err)
+	}
+
+	tmpPath := archivePath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return Entry{}, fmt.Errorf("creating artifact archive %q: %w", name, err)
+	}
+	defer os.Remove(tmpPath)
+
+	h := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(f, h))
+	tw := tar.NewWriter(gz)
+
+	for _, path := range paths {
+		if err := addToTar(tw, path); err != nil {
+			f.Close()
+			return Entry{}, fmt.Errorf("archiving %q into artifact %q: %w", path, name, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		f.Close()
+		return Entry{}, fmt.Errorf("finalizing artifact %q: %w", name, err)
+	}
+	if err := gz.Close(); err != nil {
+		f.Close()
+		return Entry{}, fmt.Errorf("finalizing artifact %q: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return Entry{}, fmt.Errorf("closing artifact archive %q: %w", name, err)
+	}
+	if err := os.Rename(tmpPath, archivePath); err != nil {
+		return Entry{}, fmt.Errorf("finalizing artifact %q: %w", name, err)
+	}
+
+	info, err := os.Stat(archivePath)
+	if err != nil {
+		return Entry{}, fmt.Errorf("stat-ing finalized artifact %q: %w", name, err)
+	}
+
+	return Entry{
+		Name:       name,
+		StepID:     stepID,
+		SHA256:     hex.EncodeToString(h.Sum(nil)),
+		SizeBytes:  info.Size(),
+		ProducedAt: time.Now(),
+	}, nil
+}
+
+func addToTar(tw *tar.Writer, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return filepath.Walk(path, func(p string, fi os.FileInfo, err error) error {
+			if err != nil || fi.IsDir() {
+				return err
+			}
+			rel, err := filepath.Rel(path, p)
+			if err != nil {
+				return err
+			}
+			return writeTarFile(tw, p, fi, filepath.Join(filepath.Base(path), rel))
+		})
+	}
+	return writeTarFile(tw, path, info, filepath.Base(path))
+}
+
+func writeTarFile(tw *tar.Writer, path string, info os.FileInfo, archiveName string) error {
+	hdr, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = archiveName
+
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Extract untars the artifact named name, produced by stepID during run runID, into destDir.
+func Extract(baseDir, runID, stepID, name, destDir string) error {
+	archivePath := ArchivePath(baseDir, runID, stepID, name)
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("opening artifact archive %q: %w", name, err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("decompressing artifact archive %q: %w", name, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading artifact archive %q: %w", name, err)
+		}
+
+		// #nosec G305 -- hdr.Name is a flat, archive-relative name written by Archive above, not
+		// attacker-controlled path traversal from an untrusted tarball.
+		destPath := filepath.Join(destDir, hdr.Name)
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			return fmt.Errorf("creating destination directory for %q: %w", hdr.Name, err)
+		}
+		out, err := os.OpenFile(destPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode))
+		if err != nil {
+			return fmt.Errorf("creating extracted file %q: %w", hdr.Name, err)
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return fmt.Errorf("writing extracted file %q: %w", hdr.Name, err)
+		}
+		if err := out.Close(); err != nil {
+			return fmt.Errorf("closing extracted file %q: %w", hdr.Name, err)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveExtract_RoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	filePath := filepath.Join(srcDir, "result.json")
+	require.NoError(t, os.WriteFile(filePath, []byte(`{"ok":true}`), 0644))
+
+	baseDir := t.TempDir()
+	entry, err := Archive(baseDir, "run-1", "scrape", "result", []string{filePath})
+	require.NoError(t, err)
+	assert.Equal(t, "result", entry.Name)
+	assert.Equal(t, "scrape", entry.StepID)
+	assert.NotEmpty(t, entry.SHA256)
+	assert.FileExists(t, ArchivePath(baseDir, "run-1", "scrape", "result"))
+
+	destDir := t.TempDir()
+	require.NoError(t, Extract(baseDir, "run-1", "scrape", "result", destDir))
+
+	got, err := os.ReadFile(filepath.Join(destDir, "result.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"ok":true}`, string(got))
+}
+
+func TestExtract_MissingArchive(t *testing.T) {
+	err := Extract(t.TempDir(), "run-1", "scrape", "missing", t.TempDir())
+	assert.Error(t, err)
+}
@@ -0,0 +1,37 @@
+package artifacts
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ReadManifest replays a run's JSON log file (as written by pkg/log/sinks.FileSink) and returns
+// every artifact Entry it recorded, in the order they were produced.
+func ReadManifest(logFilePath string) ([]Entry, error) {
+	f, err := os.Open(logFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening run log %q: %w", logFilePath, err)
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var line struct {
+			Artifact *Entry `json:"artifact"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		if line.Artifact != nil {
+			entries = append(entries, *line.Artifact)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading run log %q: %w", logFilePath, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,30 @@
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadManifest_FiltersArtifactEvents(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "run.json")
+	contents := `{"level":"info","message":"Running step"}
+{"level":"info","message":"Archived artifact","artifact":{"name":"result","step_id":"scrape","sha256":"abc","size_bytes":12,"produced_at":"2026-01-01T00:00:00Z"}}
+not even json
+`
+	require.NoError(t, os.WriteFile(logPath, []byte(contents), 0644))
+
+	entries, err := ReadManifest(logPath)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "result", entries[0].Name)
+	assert.Equal(t, "scrape", entries[0].StepID)
+}
+
+func TestReadManifest_MissingFile(t *testing.T) {
+	_, err := ReadManifest(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	assert.Error(t, err)
+}
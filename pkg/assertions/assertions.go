@@ -0,0 +1,373 @@
+// Package assertions evaluates a shell/python step's `assertions:` against its structured output,
+// stdout, stderr, and exit code, turning the step into a declarative check instead of requiring
+// bespoke `if`/grep glue in the script itself. Operator names and the "#" array-wildcard selector
+// are modeled on Venom's assertion engine and gjson's path syntax respectively; this package
+// implements just the subset of each those operator names need; it does not vendor either library.
+package assertions
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// Evaluate runs every check against output (a step's StepResult.Output), stdout, stderr, and
+// exitCode, returning one AssertionFailure per check that didn't hold. A nil/empty result means
+// every check passed.
+func Evaluate(checks []types.Assertion, output any, stdout, stderr string, exitCode int) []types.AssertionFailure {
+	var failures []types.AssertionFailure
+	for _, a := range checks {
+		actual, err := resolvePath(a.Path, output, stdout, stderr, exitCode)
+		if err != nil {
+			failures = append(failures, types.AssertionFailure{
+				Path: a.Path, Should: a.Should, Expected: a.Expected,
+				Reason: err.Error(),
+			})
+			continue
+		}
+
+		ok, reason, err := check(a.Should, actual, a.Expected)
+		if err != nil {
+			failures = append(failures, types.AssertionFailure{
+				Path: a.Path, Should: a.Should, Expected: a.Expected, Actual: actual,
+				Reason: err.Error(),
+			})
+			continue
+		}
+		if !ok {
+			if reason == "" {
+				reason = fmt.Sprintf("%s failed: got %v, want %v", a.Should, actual, a.Expected)
+			}
+			failures = append(failures, types.AssertionFailure{
+				Path: a.Path, Should: a.Should, Expected: a.Expected, Actual: actual, Reason: reason,
+			})
+		}
+	}
+	return failures
+}
+
+// resolvePath resolves an Assertion.Path against one of the step's four observable surfaces.
+func resolvePath(path string, output any, stdout, stderr string, exitCode int) (any, error) {
+	switch path {
+	case "exit_code":
+		return exitCode, nil
+	case "stdout":
+		return stdout, nil
+	case "stderr":
+		return stderr, nil
+	case "result":
+		return output, nil
+	}
+
+	rest := strings.TrimPrefix(path, "result.")
+	if rest == path {
+		return nil, fmt.Errorf(`assertion path %q must be "exit_code", "stdout", "stderr", or start with "result."`, path)
+	}
+	return navigate(output, strings.Split(rest, "."))
+}
+
+// navigate walks data by segments, treating a "#" segment as gjson's array-wildcard: it collects
+// the rest of the path from every element of the array at that point into a single slice.
+func navigate(data any, segments []string) (any, error) {
+	if len(segments) == 0 {
+		return data, nil
+	}
+
+	seg, rest := segments[0], segments[1:]
+
+	if seg == "#" {
+		arr, ok := data.([]any)
+		if !ok {
+			return nil, fmt.Errorf("path segment \"#\" expects an array, got %T", data)
+		}
+		collected := make([]any, 0, len(arr))
+		for _, elem := range arr {
+			v, err := navigate(elem, rest)
+			if err != nil {
+				return nil, err
+			}
+			collected = append(collected, v)
+		}
+		return collected, nil
+	}
+
+	m, ok := data.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot select field %q from %T", seg, data)
+	}
+	v, ok := m[seg]
+	if !ok {
+		return nil, fmt.Errorf("field %q not found", seg)
+	}
+	return navigate(v, rest)
+}
+
+// check runs should against actual and expected, returning whether it held and, if not, an
+// optional more specific reason than Evaluate's generic fallback message.
+func check(should string, actual, expected any) (bool, string, error) {
+	switch should {
+	case "ShouldEqual":
+		return valuesEqual(actual, expected), "", nil
+	case "ShouldNotEqual":
+		return !valuesEqual(actual, expected), "", nil
+	// ShouldContain is an alias of ShouldContainSubstring: both check that actual (a string or
+	// array) contains expected. The two names exist because this package grew the Venom-style
+	// operator name after ShouldContainSubstring already shipped and had workflows depending on it.
+	case "ShouldContain", "ShouldContainSubstring":
+		return shouldContainSubstring(actual, expected)
+	case "ShouldContainKey":
+		return shouldContainKey(actual, expected)
+	// ShouldMatch is an alias of ShouldMatchRegex for the same reason as ShouldContain above.
+	case "ShouldMatch", "ShouldMatchRegex":
+		return shouldMatchRegex(actual, expected)
+	case "ShouldBeGreaterThan":
+		return shouldBeGreaterThan(actual, expected)
+	case "ShouldBeLessThan":
+		return shouldBeLessThan(actual, expected)
+	case "ShouldHaveLength":
+		return shouldHaveLength(actual, expected)
+	case "ShouldBeEmpty":
+		return shouldBeEmpty(actual)
+	case "ShouldBeNil":
+		return actual == nil, "", nil
+	case "ShouldBeType":
+		return shouldBeType(actual, expected)
+	case "ShouldBeJSON":
+		return shouldBeJSON(actual)
+	default:
+		return false, "", fmt.Errorf("unknown assertion %q", should)
+	}
+}
+
+// EvaluateValue runs should against an already-resolved actual value, for a caller (e.g. the
+// assert step runner) that resolves its own path into a value instead of going through Evaluate's
+// exit_code/stdout/stderr/result surfaces. negate inverts the result (and, on a non-error false
+// result, clears the generic "failed: got X, want Y" reason, since it no longer describes what
+// happened once inverted).
+func EvaluateValue(should string, actual, expected any, negate bool) (bool, string, error) {
+	ok, reason, err := check(should, actual, expected)
+	if err != nil {
+		return false, "", err
+	}
+	if negate {
+		ok = !ok
+		if ok {
+			reason = ""
+		} else {
+			reason = fmt.Sprintf("negated %s failed: got %v, want not %v", should, actual, expected)
+		}
+	}
+	return ok, reason, nil
+}
+
+// IsKnownOperator reports whether should names an operator check recognizes, so a workflow
+// validator can reject an assertion's Should at parse time instead of only at run time.
+func IsKnownOperator(should string) bool {
+	switch should {
+	case "ShouldEqual", "ShouldNotEqual", "ShouldContain", "ShouldContainSubstring",
+		"ShouldContainKey", "ShouldMatch", "ShouldMatchRegex", "ShouldBeGreaterThan",
+		"ShouldBeLessThan", "ShouldHaveLength", "ShouldBeEmpty", "ShouldBeNil", "ShouldBeType",
+		"ShouldBeJSON":
+		return true
+	default:
+		return false
+	}
+}
+
+func shouldContainSubstring(actual, expected any) (bool, string, error) {
+	want := fmt.Sprintf("%v", expected)
+	switch a := actual.(type) {
+	case string:
+		return strings.Contains(a, want), "", nil
+	case []any:
+		for _, elem := range a {
+			if valuesEqual(elem, expected) {
+				return true, "", nil
+			}
+		}
+		return false, fmt.Sprintf("ShouldContainSubstring failed: %v not found in %v", expected, a), nil
+	default:
+		return false, "", fmt.Errorf("ShouldContainSubstring expects a string or array, got %T", actual)
+	}
+}
+
+func shouldMatchRegex(actual, expected any) (bool, string, error) {
+	s, ok := actual.(string)
+	if !ok {
+		return false, "", fmt.Errorf("ShouldMatchRegex expects a string, got %T", actual)
+	}
+	pattern, ok := expected.(string)
+	if !ok {
+		return false, "", fmt.Errorf("ShouldMatchRegex expects a string pattern, got %T", expected)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, "", fmt.Errorf("invalid regex %q: %w", pattern, err)
+	}
+	return re.MatchString(s), "", nil
+}
+
+func shouldBeGreaterThan(actual, expected any) (bool, string, error) {
+	a, err := toFloat(actual)
+	if err != nil {
+		return false, "", fmt.Errorf("ShouldBeGreaterThan: %w", err)
+	}
+	e, err := toFloat(expected)
+	if err != nil {
+		return false, "", fmt.Errorf("ShouldBeGreaterThan: %w", err)
+	}
+	return a > e, "", nil
+}
+
+func shouldBeLessThan(actual, expected any) (bool, string, error) {
+	a, err := toFloat(actual)
+	if err != nil {
+		return false, "", fmt.Errorf("ShouldBeLessThan: %w", err)
+	}
+	e, err := toFloat(expected)
+	if err != nil {
+		return false, "", fmt.Errorf("ShouldBeLessThan: %w", err)
+	}
+	return a < e, "", nil
+}
+
+func shouldHaveLength(actual, expected any) (bool, string, error) {
+	wantF, err := toFloat(expected)
+	if err != nil {
+		return false, "", fmt.Errorf("ShouldHaveLength expects a numeric length, got %T", expected)
+	}
+	want := int(wantF)
+
+	var got int
+	switch a := actual.(type) {
+	case string:
+		got = len(a)
+	case []any:
+		got = len(a)
+	case map[string]any:
+		got = len(a)
+	default:
+		return false, "", fmt.Errorf("ShouldHaveLength expects a string, array, or object, got %T", actual)
+	}
+	if got != want {
+		return false, fmt.Sprintf("ShouldHaveLength failed: got length %d, want %d", got, want), nil
+	}
+	return true, "", nil
+}
+
+func shouldContainKey(actual, expected any) (bool, string, error) {
+	m, ok := actual.(map[string]any)
+	if !ok {
+		return false, "", fmt.Errorf("ShouldContainKey expects an object, got %T", actual)
+	}
+	key, ok := expected.(string)
+	if !ok {
+		return false, "", fmt.Errorf("ShouldContainKey expects a string key, got %T", expected)
+	}
+	if _, ok := m[key]; !ok {
+		return false, fmt.Sprintf("ShouldContainKey failed: key %q not found in %v", key, m), nil
+	}
+	return true, "", nil
+}
+
+func shouldBeEmpty(actual any) (bool, string, error) {
+	var empty bool
+	switch a := actual.(type) {
+	case nil:
+		empty = true
+	case string:
+		empty = a == ""
+	case []any:
+		empty = len(a) == 0
+	case map[string]any:
+		empty = len(a) == 0
+	default:
+		return false, "", fmt.Errorf("ShouldBeEmpty expects a string, array, object, or null, got %T", actual)
+	}
+	if !empty {
+		return false, fmt.Sprintf("ShouldBeEmpty failed: got %v", actual), nil
+	}
+	return true, "", nil
+}
+
+// shouldBeType checks actual's dynamic type against expected, one of the JSON type names
+// "string", "number", "bool", "array", "object", or "null".
+func shouldBeType(actual, expected any) (bool, string, error) {
+	wantType, ok := expected.(string)
+	if !ok {
+		return false, "", fmt.Errorf("ShouldBeType expects a string type name, got %T", expected)
+	}
+
+	var gotType string
+	switch actual.(type) {
+	case nil:
+		gotType = "null"
+	case string:
+		gotType = "string"
+	case float64, float32, int, int64:
+		gotType = "number"
+	case bool:
+		gotType = "bool"
+	case []any:
+		gotType = "array"
+	case map[string]any:
+		gotType = "object"
+	default:
+		return false, "", fmt.Errorf("ShouldBeType: unsupported actual type %T", actual)
+	}
+
+	if gotType != wantType {
+		return false, fmt.Sprintf("ShouldBeType failed: got %s, want %s", gotType, wantType), nil
+	}
+	return true, "", nil
+}
+
+func shouldBeJSON(actual any) (bool, string, error) {
+	s, ok := actual.(string)
+	if !ok {
+		// Already-decoded structured output (a map or slice) is, definitionally, valid JSON.
+		return true, "", nil
+	}
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return false, fmt.Sprintf("ShouldBeJSON failed: %v", err), nil
+	}
+	return true, "", nil
+}
+
+// valuesEqual compares actual (a step's native JSON-decoded type, or a raw string for stdout/
+// stderr) against expected (a YAML-decoded literal), coercing numeric types so e.g. a YAML `3`
+// (int) matches a JSON-decoded `3.0` (float64).
+func valuesEqual(actual, expected any) bool {
+	if af, aerr := toFloat(actual); aerr == nil {
+		if ef, eerr := toFloat(expected); eerr == nil {
+			return af == ef
+		}
+	}
+	return fmt.Sprintf("%v", actual) == fmt.Sprintf("%v", expected)
+}
+
+func toFloat(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%q is not numeric", n)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("%v (%T) is not numeric", v, v)
+	}
+}
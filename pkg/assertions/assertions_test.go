@@ -0,0 +1,112 @@
+package assertions
+
+import (
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate_AllPass(t *testing.T) {
+	output := map[string]any{
+		"items": []any{
+			map[string]any{"id": "abc"},
+			map[string]any{"id": "def"},
+		},
+		"count": float64(2),
+	}
+
+	checks := []types.Assertion{
+		{Path: "result.items.#.id", Should: "ShouldContainSubstring", Expected: "abc"},
+		{Path: "result.count", Should: "ShouldEqual", Expected: 2},
+		{Path: "exit_code", Should: "ShouldEqual", Expected: 0},
+		{Path: "stdout", Should: "ShouldMatchRegex", Expected: "^ok"},
+		{Path: "result.items", Should: "ShouldHaveLength", Expected: 2},
+	}
+
+	failures := Evaluate(checks, output, "ok, done", "", 0)
+	assert.Empty(t, failures)
+}
+
+func TestEvaluate_ReportsFailureDetails(t *testing.T) {
+	output := map[string]any{"count": float64(1)}
+
+	failures := Evaluate([]types.Assertion{
+		{Path: "result.count", Should: "ShouldEqual", Expected: 2},
+	}, output, "", "", 0)
+
+	require.Len(t, failures, 1)
+	assert.Equal(t, "result.count", failures[0].Path)
+	assert.Equal(t, "ShouldEqual", failures[0].Should)
+	assert.Equal(t, float64(1), failures[0].Actual)
+	assert.NotEmpty(t, failures[0].Reason)
+}
+
+func TestEvaluate_UnknownPathIsAFailureNotAPanic(t *testing.T) {
+	failures := Evaluate([]types.Assertion{
+		{Path: "result.missing", Should: "ShouldEqual", Expected: "x"},
+	}, map[string]any{}, "", "", 0)
+
+	require.Len(t, failures, 1)
+	assert.Contains(t, failures[0].Reason, "not found")
+}
+
+func TestEvaluate_ShouldBeJSON(t *testing.T) {
+	ok := Evaluate([]types.Assertion{{Path: "stdout", Should: "ShouldBeJSON"}}, nil, `{"a":1}`, "", 0)
+	assert.Empty(t, ok)
+
+	bad := Evaluate([]types.Assertion{{Path: "stdout", Should: "ShouldBeJSON"}}, nil, "not json", "", 0)
+	require.Len(t, bad, 1)
+}
+
+func TestEvaluate_NewOperators(t *testing.T) {
+	output := map[string]any{
+		"token": "eyJabc",
+		"meta":  map[string]any{"region": "us"},
+		"tags":  []any{},
+	}
+
+	checks := []types.Assertion{
+		{Path: "result.token", Should: "ShouldNotEqual", Expected: "other"},
+		{Path: "result.token", Should: "ShouldContain", Expected: "abc"},
+		{Path: "result.token", Should: "ShouldMatch", Expected: "^ey"},
+		{Path: "result.meta", Should: "ShouldContainKey", Expected: "region"},
+		{Path: "result.tags", Should: "ShouldBeEmpty"},
+		{Path: "result.token", Should: "ShouldBeType", Expected: "string"},
+	}
+
+	failures := Evaluate(checks, output, "", "", 0)
+	assert.Empty(t, failures)
+}
+
+func TestEvaluate_NewOperators_Failures(t *testing.T) {
+	output := map[string]any{"meta": map[string]any{"region": "us"}}
+
+	failures := Evaluate([]types.Assertion{
+		{Path: "result.meta", Should: "ShouldContainKey", Expected: "zone"},
+	}, output, "", "", 0)
+	require.Len(t, failures, 1)
+	assert.Contains(t, failures[0].Reason, `key "zone" not found`)
+
+	failures = Evaluate([]types.Assertion{
+		{Path: "result.meta", Should: "ShouldBeType", Expected: "array"},
+	}, output, "", "", 0)
+	require.Len(t, failures, 1)
+	assert.Contains(t, failures[0].Reason, "got object, want array")
+}
+
+func TestEvaluate_ShouldBeLessThanAndShouldBeNil(t *testing.T) {
+	output := map[string]any{"count": float64(2), "error": nil}
+
+	failures := Evaluate([]types.Assertion{
+		{Path: "result.count", Should: "ShouldBeLessThan", Expected: 5},
+		{Path: "result.error", Should: "ShouldBeNil"},
+	}, output, "", "", 0)
+	assert.Empty(t, failures)
+
+	failures = Evaluate([]types.Assertion{
+		{Path: "result.count", Should: "ShouldBeLessThan", Expected: 1},
+	}, output, "", "", 0)
+	require.Len(t, failures, 1)
+}
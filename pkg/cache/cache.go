@@ -0,0 +1,82 @@
+// Package cache implements a content-addressed cache for step execution: a digest is computed
+// from a step's fully-resolved config, the contents of any files it references, and the digests
+// of its dependencies' results, and used to skip re-running a step whose inputs haven't changed
+// since the last run.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// Store persists digest -> StepResult mappings. FilesystemStore is the only implementation today;
+// the interface exists so S3/GCS-backed stores can be added later without touching callers.
+type Store interface {
+	// Get returns the cached result for digest, or found=false if there is no entry.
+	Get(digest string) (result types.StepResult, found bool, err error)
+	// Put stores result under digest, overwriting any existing entry.
+	Put(digest string, result types.StepResult) error
+}
+
+// Digest computes a stable content digest for a step execution from its fully-resolved config
+// (step), the SHA-256 of every file it references (fileRefs — absolute paths, empty strings
+// ignored), and the digests of every step listed in its depends_on (depDigests, in depends_on
+// order). encoding/json sorts map keys when marshaling, so digest is stable regardless of a
+// config map's original key insertion order.
+func Digest(step *types.Step, fileRefs []string, depDigests []string) (string, error) {
+	h := sha256.New()
+
+	stepJSON, err := json.Marshal(step)
+	if err != nil {
+		return "", fmt.Errorf("marshaling step for digest: %w", err)
+	}
+	h.Write(stepJSON)
+
+	for _, path := range fileRefs {
+		if path == "" {
+			continue
+		}
+		fileSum, err := fileDigest(path)
+		if err != nil {
+			return "", fmt.Errorf("digesting referenced file %q: %w", path, err)
+		}
+		fmt.Fprintf(h, "\x00file:%s:%s", path, fileSum)
+	}
+
+	for _, dep := range depDigests {
+		fmt.Fprintf(h, "\x00dep:%s", dep)
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ResultDigest computes a content digest for a StepResult's Output, for use as a dependency's
+// contribution to a downstream step's Digest.
+func ResultDigest(result types.StepResult) (string, error) {
+	data, err := json.Marshal(result.Output)
+	if err != nil {
+		return "", fmt.Errorf("marshaling step result for digest: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,123 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigest_StableAcrossMapKeyOrdering(t *testing.T) {
+	stepA := &types.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method: "POST",
+			Url:    "https://example.com",
+			Headers: map[string]string{
+				"Authorization": "token",
+				"Content-Type":  "application/json",
+			},
+		},
+	}
+	stepB := &types.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method: "POST",
+			Url:    "https://example.com",
+			Headers: map[string]string{
+				"Content-Type":  "application/json",
+				"Authorization": "token",
+			},
+		},
+	}
+
+	digestA, err := Digest(stepA, nil, nil)
+	require.NoError(t, err)
+	digestB, err := Digest(stepB, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, digestA, digestB)
+}
+
+func TestDigest_ChangesWithFileContents(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "upload.txt")
+	require.NoError(t, os.WriteFile(path, []byte("v1"), 0644))
+
+	step := &types.Step{ID: "upload", Uses: "browser_agent"}
+
+	digest1, err := Digest(step, []string{path}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("v2"), 0644))
+	digest2, err := Digest(step, []string{path}, nil)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, digest1, digest2)
+}
+
+func TestDigest_ChangesWithDependencyDigests(t *testing.T) {
+	step := &types.Step{ID: "downstream", Uses: "shell"}
+
+	digest1, err := Digest(step, nil, []string{"dep-digest-1"})
+	require.NoError(t, err)
+	digest2, err := Digest(step, nil, []string{"dep-digest-2"})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, digest1, digest2)
+}
+
+func TestResultDigest_ChangesWithOutput(t *testing.T) {
+	d1, err := ResultDigest(types.StepResult{Output: map[string]any{"status_code": 200}})
+	require.NoError(t, err)
+	d2, err := ResultDigest(types.StepResult{Output: map[string]any{"status_code": 500}})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, d1, d2)
+}
+
+func TestFilesystemStore_PutAndGet(t *testing.T) {
+	store, err := NewFilesystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	digest := "abcd1234"
+	result := types.StepResult{Output: map[string]any{"hello": "world"}}
+
+	_, found, err := store.Get(digest)
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	require.NoError(t, store.Put(digest, result))
+
+	got, found, err := store.Get(digest)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "world", got.Output.(map[string]any)["hello"])
+}
+
+func TestFilesystemStore_Shards(t *testing.T) {
+	baseDir := t.TempDir()
+	store, err := NewFilesystemStore(baseDir)
+	require.NoError(t, err)
+
+	digest := "abcdef0123456789"
+	require.NoError(t, store.Put(digest, types.StepResult{Output: "x"}))
+
+	expectedPath := filepath.Join(baseDir, "ab", "cd", digest)
+	_, err = os.Stat(expectedPath)
+	assert.NoError(t, err)
+}
+
+func TestFilesystemStore_Miss(t *testing.T) {
+	store, err := NewFilesystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, found, err := store.Get("does-not-exist")
+	require.NoError(t, err)
+	assert.False(t, found)
+}
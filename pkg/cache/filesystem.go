@@ -0,0 +1,136 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// FilesystemStore persists cache entries as JSON files on a local disk, sharded two levels deep by
+// the first four hex characters of the digest (digest[:2]/digest[2:4]/digest), matching the layout
+// convention used by Bazel/Goma's content-addressed storage.
+type FilesystemStore struct {
+	BaseDir string
+}
+
+// NewFilesystemStore returns a FilesystemStore rooted at baseDir (e.g. ".dropstep/cache"),
+// creating it if it doesn't already exist.
+func NewFilesystemStore(baseDir string) (*FilesystemStore, error) {
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating cache directory %q: %w", baseDir, err)
+	}
+	return &FilesystemStore{BaseDir: baseDir}, nil
+}
+
+func (s *FilesystemStore) entryPath(digest string) string {
+	if len(digest) < 4 {
+		return filepath.Join(s.BaseDir, digest)
+	}
+	return filepath.Join(s.BaseDir, digest[:2], digest[2:4], digest)
+}
+
+func (s *FilesystemStore) Get(digest string) (types.StepResult, bool, error) {
+	data, err := os.ReadFile(s.entryPath(digest))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return types.StepResult{}, false, nil
+		}
+		return types.StepResult{}, false, fmt.Errorf("reading cache entry %q: %w", digest, err)
+	}
+
+	var result types.StepResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return types.StepResult{}, false, fmt.Errorf("decoding cache entry %q: %w", digest, err)
+	}
+	return result, true, nil
+}
+
+func (s *FilesystemStore) Put(digest string, result types.StepResult) error {
+	path := s.entryPath(digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating cache shard directory for %q: %w", digest, err)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("encoding cache entry %q: %w", digest, err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing cache entry %q: %w", digest, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("finalizing cache entry %q: %w", digest, err)
+	}
+	return nil
+}
+
+// Prune deletes every cache entry last modified more than olderThan ago, returning the number of
+// entries removed. Empty shard directories left behind are cleaned up as well.
+func (s *FilesystemStore) Prune(olderThan time.Duration) (int, error) {
+	cutoff := time.Now().Add(-olderThan)
+	removed := 0
+
+	err := filepath.WalkDir(s.BaseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if filepath.Ext(path) == ".tmp" {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.ModTime().After(cutoff) {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("removing cache entry %q: %w", path, err)
+		}
+		removed++
+		return nil
+	})
+	if err != nil {
+		return removed, fmt.Errorf("pruning cache at %q: %w", s.BaseDir, err)
+	}
+
+	removeEmptyShardDirs(s.BaseDir)
+	return removed, nil
+}
+
+// removeEmptyShardDirs removes now-empty two-level shard directories left behind by Prune.
+// Failures are ignored: a non-empty or in-use directory simply stays, which is harmless.
+func removeEmptyShardDirs(baseDir string) {
+	entries, err := os.ReadDir(baseDir)
+	if err != nil {
+		return
+	}
+	for _, top := range entries {
+		if !top.IsDir() {
+			continue
+		}
+		topPath := filepath.Join(baseDir, top.Name())
+		subEntries, err := os.ReadDir(topPath)
+		if err != nil {
+			continue
+		}
+		for _, sub := range subEntries {
+			if sub.IsDir() {
+				_ = os.Remove(filepath.Join(topPath, sub.Name()))
+			}
+		}
+		_ = os.Remove(topPath)
+	}
+}
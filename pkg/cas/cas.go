@@ -0,0 +1,35 @@
+// Package cas implements a content-addressable blob store keyed by the SHA-256 digest of each
+// blob's contents. It is the storage primitive a remote-execution-style step runner (see
+// pkg/steprunner/remote) uses to avoid re-uploading files a worker already has cached, mirroring
+// the FindMissingBlobs/BatchUpdateBlobs half of Bazel's Remote Execution API v2.
+package cas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Digest identifies a blob by the SHA-256 hash of its contents plus its size, the same pairing
+// REv2 uses so a worker can preallocate storage before the blob itself arrives.
+type Digest struct {
+	Hash      string
+	SizeBytes int64
+}
+
+// DigestOf computes the Digest of data.
+func DigestOf(data []byte) Digest {
+	sum := sha256.Sum256(data)
+	return Digest{Hash: hex.EncodeToString(sum[:]), SizeBytes: int64(len(data))}
+}
+
+// Store persists blobs by digest. FilesystemStore is the only implementation today; a remote
+// worker pool's own CAS endpoint could satisfy this interface without touching callers.
+type Store interface {
+	// FindMissing returns the subset of digests not already present in the store, so callers only
+	// upload blobs the store doesn't already have.
+	FindMissing(digests []Digest) ([]Digest, error)
+	// Put stores data under its digest. Callers should pass a digest computed by DigestOf(data).
+	Put(digest Digest, data []byte) error
+	// Get returns the blob for digest, or found=false if there is no entry.
+	Get(digest Digest) (data []byte, found bool, err error)
+}
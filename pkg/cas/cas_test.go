@@ -0,0 +1,62 @@
+package cas
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestOf_Stable(t *testing.T) {
+	d1 := DigestOf([]byte("hello"))
+	d2 := DigestOf([]byte("hello"))
+	assert.Equal(t, d1, d2)
+
+	d3 := DigestOf([]byte("world"))
+	assert.NotEqual(t, d1, d3)
+}
+
+func TestFilesystemStore_PutGetFindMissing(t *testing.T) {
+	store, err := NewFilesystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	data := []byte("step input contents")
+	digest := DigestOf(data)
+
+	missing, err := store.FindMissing([]Digest{digest})
+	require.NoError(t, err)
+	assert.Equal(t, []Digest{digest}, missing)
+
+	require.NoError(t, store.Put(digest, data))
+
+	missing, err = store.FindMissing([]Digest{digest})
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+
+	got, found, err := store.Get(digest)
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, data, got)
+}
+
+func TestFilesystemStore_Shards(t *testing.T) {
+	baseDir := t.TempDir()
+	store, err := NewFilesystemStore(baseDir)
+	require.NoError(t, err)
+
+	digest := DigestOf([]byte("shard me"))
+	require.NoError(t, store.Put(digest, []byte("shard me")))
+
+	expectedPath := filepath.Join(baseDir, digest.Hash[:2], digest.Hash[2:4], digest.Hash)
+	assert.FileExists(t, expectedPath)
+}
+
+func TestFilesystemStore_GetMiss(t *testing.T) {
+	store, err := NewFilesystemStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, found, err := store.Get(DigestOf([]byte("never stored")))
+	require.NoError(t, err)
+	assert.False(t, found)
+}
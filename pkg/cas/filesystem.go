@@ -0,0 +1,71 @@
+package cas
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FilesystemStore persists blobs as plain files on local disk, sharded two levels deep by the
+// first four hex characters of the digest (digest[:2]/digest[2:4]/digest), the same layout
+// convention pkg/cache's FilesystemStore uses for cached step results.
+type FilesystemStore struct {
+	BaseDir string
+}
+
+// NewFilesystemStore returns a FilesystemStore rooted at baseDir (e.g. ".dropstep/cas"), creating
+// it if it doesn't already exist.
+func NewFilesystemStore(baseDir string) (*FilesystemStore, error) {
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating CAS directory %q: %w", baseDir, err)
+	}
+	return &FilesystemStore{BaseDir: baseDir}, nil
+}
+
+func (s *FilesystemStore) blobPath(digest Digest) string {
+	if len(digest.Hash) < 4 {
+		return filepath.Join(s.BaseDir, digest.Hash)
+	}
+	return filepath.Join(s.BaseDir, digest.Hash[:2], digest.Hash[2:4], digest.Hash)
+}
+
+func (s *FilesystemStore) FindMissing(digests []Digest) ([]Digest, error) {
+	var missing []Digest
+	for _, d := range digests {
+		if _, err := os.Stat(s.blobPath(d)); err != nil {
+			if os.IsNotExist(err) {
+				missing = append(missing, d)
+				continue
+			}
+			return nil, fmt.Errorf("checking blob %q: %w", d.Hash, err)
+		}
+	}
+	return missing, nil
+}
+
+func (s *FilesystemStore) Put(digest Digest, data []byte) error {
+	path := s.blobPath(digest)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating CAS shard directory for %q: %w", digest.Hash, err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("writing blob %q: %w", digest.Hash, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("finalizing blob %q: %w", digest.Hash, err)
+	}
+	return nil
+}
+
+func (s *FilesystemStore) Get(digest Digest) ([]byte, bool, error) {
+	data, err := os.ReadFile(s.blobPath(digest))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("reading blob %q: %w", digest.Hash, err)
+	}
+	return data, true, nil
+}
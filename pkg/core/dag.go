@@ -0,0 +1,204 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/arnavsurve/dropstep/pkg/summary"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// buildDependencyGraph validates each step's DependsOn against the set of step IDs in steps and
+// returns, for each step ID, the number of unresolved dependencies it starts with and the list of
+// steps waiting on it to complete.
+func buildDependencyGraph(steps []Step) (remaining map[string]int, dependents map[string][]string, err error) {
+	ids := make(map[string]bool, len(steps))
+	for _, s := range steps {
+		if ids[s.ID] {
+			return nil, nil, fmt.Errorf("duplicate step id %q", s.ID)
+		}
+		ids[s.ID] = true
+	}
+
+	remaining = make(map[string]int, len(steps))
+	dependents = make(map[string][]string, len(steps))
+	for _, s := range steps {
+		for _, dep := range s.DependsOn {
+			if !ids[dep] {
+				return nil, nil, fmt.Errorf("step %q: depends_on references unknown step %q", s.ID, dep)
+			}
+		}
+		remaining[s.ID] = len(s.DependsOn)
+		for _, dep := range s.DependsOn {
+			dependents[dep] = append(dependents[dep], s.ID)
+		}
+	}
+
+	if cycle := findCycle(steps); len(cycle) > 0 {
+		return nil, nil, fmt.Errorf("depends_on forms a cycle: %s", strings.Join(cycle, " -> "))
+	}
+
+	return remaining, dependents, nil
+}
+
+// findCycle returns the full chain of step IDs forming a depends_on cycle (e.g. ["a", "b", "c",
+// "a"]), or nil if steps form a DAG.
+func findCycle(steps []Step) []string {
+	byID := make(map[string]Step, len(steps))
+	for _, s := range steps {
+		byID[s.ID] = s
+	}
+
+	const (
+		unvisited = 0
+		visiting  = 1
+		done      = 2
+	)
+	state := make(map[string]int, len(steps))
+	var stack []string
+
+	var cycle []string
+	var visit func(id string) bool
+	visit = func(id string) bool {
+		switch state[id] {
+		case done:
+			return false
+		case visiting:
+			// Found the back edge that closes the cycle; stack holds the path from the cycle's
+			// start down to id, so it already reads in traversal order.
+			for i, s := range stack {
+				if s == id {
+					cycle = append(append([]string{}, stack[i:]...), id)
+					break
+				}
+			}
+			return true
+		}
+		state[id] = visiting
+		stack = append(stack, id)
+		for _, dep := range byID[id].DependsOn {
+			if visit(dep) {
+				return true
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[id] = done
+		return false
+	}
+
+	for _, s := range steps {
+		if state[s.ID] == unvisited && visit(s.ID) {
+			break
+		}
+	}
+	return cycle
+}
+
+// executeStepsConcurrently runs wf.Steps through a worker pool bounded to wf.Parallel, dispatching
+// each step as soon as every ID in its depends_on has completed. stepResults and report are shared
+// across every in-flight step, so every access to them goes through mu (see executeStep).
+//
+// The first step to fail cancels ctx for every step still running or not yet dispatched; steps
+// already in flight are allowed to finish (or abort on their own via ctx) rather than being killed
+// outright, and ExecuteWorkflow reports that first error once every goroutine has returned.
+func (e *WorkflowEngine) executeStepsConcurrently(
+	ctx context.Context,
+	wf *Workflow,
+	varCtx VarContext,
+	stepResults StepResultsContext,
+	workflowDir string,
+	resolvedProviders map[string]ProviderConfig,
+	report *summary.Report,
+) error {
+	steps := wf.Steps
+	byID := make(map[string]Step, len(steps))
+	for _, s := range steps {
+		byID[s.ID] = s
+	}
+
+	remaining, dependents, err := buildDependencyGraph(steps)
+	if err != nil {
+		return fmt.Errorf("building step dependency graph: %w", err)
+	}
+
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, wf.Parallel)
+	var firstErr error
+	attempted := make(map[string]bool, len(steps))
+
+	var dispatch func(id string)
+	dispatch = func(id string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-runCtx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			if runCtx.Err() != nil {
+				return
+			}
+
+			mu.Lock()
+			attempted[id] = true
+			mu.Unlock()
+
+			stepErr := e.executeStep(runCtx, wf, byID[id], varCtx, stepResults, workflowDir, resolvedProviders, report, &mu)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if stepErr != nil {
+				if firstErr == nil {
+					firstErr = stepErr
+					cancelRun()
+				}
+				return
+			}
+
+			for _, next := range dependents[id] {
+				remaining[next]--
+				if remaining[next] == 0 {
+					dispatch(next)
+				}
+			}
+		}()
+	}
+
+	for _, s := range steps {
+		if remaining[s.ID] == 0 {
+			dispatch(s.ID)
+		}
+	}
+
+	wg.Wait()
+
+	// A step whose depends_on chain includes the failed step is never dispatched (attempted stays
+	// false for it), so it's still missing from stepResults here; record it the same way a false
+	// `if` does (Output: "skipped") rather than leaving it absent, so the summary report and any
+	// later lookup of its result see an explicit status instead of "step never ran". The step that
+	// actually failed is excluded via attempted: executeStep already recorded its "failed" summary
+	// entry and deliberately leaves it out of stepResults.
+	if firstErr != nil {
+		mu.Lock()
+		for _, s := range steps {
+			if _, ran := stepResults[s.ID]; !ran && !attempted[s.ID] {
+				stepResults[s.ID] = types.StepResult{Output: "skipped"}
+				e.recordStepSummary(report, &s, "", "skipped", 0)
+			}
+		}
+		mu.Unlock()
+	}
+
+	return firstErr
+}
@@ -0,0 +1,49 @@
+package core_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/core"
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStepDependencies_CycleListsFullChain(t *testing.T) {
+	wf := &core.Workflow{
+		Steps: []core.Step{
+			{ID: "a", Uses: "shell", Command: &core.CommandBlock{Inline: "echo a"}, DependsOn: []string{"c"}},
+			{ID: "b", Uses: "shell", Command: &core.CommandBlock{Inline: "echo b"}, DependsOn: []string{"a"}},
+			{ID: "c", Uses: "shell", Command: &core.CommandBlock{Inline: "echo c"}, DependsOn: []string{"b"}},
+		},
+	}
+
+	err := core.ValidateStepDependencies(wf)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a -> c -> b -> a")
+}
+
+func TestExecuteWorkflow_ParallelSkipsDependentsOnFailure(t *testing.T) {
+	wf := &core.Workflow{
+		Parallel: 2,
+		Steps: []core.Step{
+			{ID: "fails", Uses: "shell", Command: &core.CommandBlock{Inline: "exit 1"}},
+			{ID: "depends_on_failure", Uses: "shell", Command: &core.CommandBlock{Inline: "echo hi"}, DependsOn: []string{"fails"}},
+			{ID: "independent", Uses: "shell", Command: &core.CommandBlock{Inline: "echo hi"}},
+		},
+	}
+
+	engine := core.NewWorkflowEngine(log.NewZerologAdapter(zerolog.New(io.Discard)))
+
+	stepResults, _, err := engine.ExecuteWorkflow(context.Background(), wf, core.VarContext{}, nil, t.TempDir(), nil)
+	require.Error(t, err)
+
+	require.Contains(t, stepResults, "depends_on_failure")
+	assert.Equal(t, "skipped", stepResults["depends_on_failure"].Output)
+
+	require.Contains(t, stepResults, "independent")
+	assert.NotEqual(t, "skipped", stepResults["independent"].Output)
+}
@@ -1,14 +1,108 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/arnavsurve/dropstep/pkg/artifacts"
+	"github.com/arnavsurve/dropstep/pkg/cache"
+	"github.com/arnavsurve/dropstep/pkg/cas"
+	"github.com/arnavsurve/dropstep/pkg/expr"
+	"github.com/arnavsurve/dropstep/pkg/fileutil"
+	"github.com/arnavsurve/dropstep/pkg/secretmount"
 	"github.com/arnavsurve/dropstep/pkg/steprunner"
+	"github.com/arnavsurve/dropstep/pkg/steprunner/remote"
+	"github.com/arnavsurve/dropstep/pkg/summary"
+	"github.com/arnavsurve/dropstep/pkg/tracing"
 	"github.com/arnavsurve/dropstep/pkg/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// summaryIssues is the subset of *sinks.SummarySink's API WorkflowEngine needs. Declared locally
+// instead of importing pkg/log/sinks, which would create an import cycle back through pkg/log to
+// this package (see WorkflowEngine.SummarySink).
+type summaryIssues interface {
+	Issues(stepID string) []string
+}
+
 type WorkflowEngine struct {
 	Logger Logger
+
+	// Cache, if set, is consulted before running each step and written to after a successful run.
+	// A nil Cache disables caching entirely, regardless of a step's own 'cache' field.
+	Cache cache.Store
+	// NoCache forces every step to run even if Cache is set, e.g. for a --no-cache CLI flag.
+	NoCache bool
+
+	// NoProgress and Silent are threaded onto every step's ExecutionContext; see
+	// types.ExecutionContext for their meaning.
+	NoProgress bool
+	Silent     bool
+
+	// MaskFunc, if set, is handed to each step as ExecutionContext.AddMask so the step can
+	// register a secret discovered at runtime (e.g. via "::add-mask::") with the log router.
+	MaskFunc func(secret string)
+
+	// PathFunc and PathDirs, if set, are handed to each step as ExecutionContext.AddPath and
+	// ExecutionContext.PathDirs so a step can extend the PATH every later step's subprocess sees
+	// (e.g. via "::add-path::") and so each step's runner can read what's been added so far. Both
+	// nil (the default) leaves add-path a no-op, the same as a nil MaskFunc leaves add-mask one.
+	PathFunc func(dir string)
+	PathDirs func() []string
+
+	// RemoteBlobs and RemoteResults back pkg/steprunner/remote's content-addressed dispatch for
+	// workflows with an `execution.remote` block. Both nil (the default) means every step runs
+	// locally regardless of the workflow's execution config.
+	RemoteBlobs   cas.Store
+	RemoteResults cache.Store
+
+	// RunID identifies the current workflow run (e.g. the CLI's wfRunID), used to namespace
+	// archived step artifacts on disk and in their log entries.
+	RunID string
+	// ArtifactsDir is the root directory archived step artifacts are stored under (e.g.
+	// ".dropstep/artifacts"). An empty ArtifactsDir disables artifact archiving/restoring entirely.
+	ArtifactsDir string
+
+	// SummaryDir is the root directory the per-run Markdown step summary (pkg/summary) is written
+	// under (e.g. ".dropstep/runs"). An empty SummaryDir disables step summaries entirely.
+	SummaryDir string
+	// SummarySink, if set, supplies the WARN/ERROR/FATAL events raised while each step ran, for
+	// inclusion in its summary section. A nil SummarySink just omits the "Issues" section. Typed
+	// as the local summaryIssues interface, not the concrete *sinks.SummarySink, so pkg/core
+	// doesn't depend on pkg/log/sinks (which depends on pkg/log, which depends back on pkg/core
+	// for the Logger/Event/Context interfaces ZerologAdapter implements).
+	SummarySink summaryIssues
+
+	// outputSchemaCache memoizes a step's parsed browser.output_schema by resolved file path, so a
+	// matrix step's many cells don't each re-read and re-parse the same schema file. Safe for the
+	// concurrent cell execution executeMatrixStep does; zero value is ready to use.
+	outputSchemaCache sync.Map
+}
+
+// loadOutputSchema returns path's parsed output_schema, relative to workflowDir, caching the
+// result by resolved path across the run (see outputSchemaCache).
+func (e *WorkflowEngine) loadOutputSchema(workflowDir, path string) (*outputSchemaNode, error) {
+	resolvedPath, err := fileutil.ResolvePathFromWorkflow(workflowDir, path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path %q: %w", path, err)
+	}
+	if cached, ok := e.outputSchemaCache.Load(resolvedPath); ok {
+		return cached.(*outputSchemaNode), nil
+	}
+	schema, err := loadOutputSchemaFile(workflowDir, path)
+	if err != nil {
+		return nil, err
+	}
+	e.outputSchemaCache.Store(resolvedPath, schema)
+	return schema, nil
 }
 
 func NewWorkflowEngine(logger Logger) *WorkflowEngine {
@@ -17,62 +111,884 @@ func NewWorkflowEngine(logger Logger) *WorkflowEngine {
 	}
 }
 
+// stepWantsCache reports whether step opts into caching (the default, unless step.Cache is an
+// explicit false).
+func stepWantsCache(step *types.Step) bool {
+	return step.Cache == nil || *step.Cache
+}
+
+// applyDefaultAssertions gives step wf.Assertions when step doesn't declare its own `assertions:`
+// block. A step's assertions replace the workflow-level default entirely; there's no per-check
+// merge between the two.
+func applyDefaultAssertions(step *types.Step, wf *Workflow) {
+	if len(step.Assertions) == 0 {
+		step.Assertions = wf.Assertions
+	}
+}
+
+// referencedFiles returns the paths of every file a step's resolved config points at, for
+// inclusion in its cache digest.
+func referencedFiles(step *types.Step) []string {
+	var files []string
+	if step.Command != nil && step.Command.Path != "" {
+		files = append(files, step.Command.Path)
+	}
+	for _, f := range step.BrowserConfig.UploadFiles {
+		if f.Path != "" {
+			files = append(files, f.Path)
+		}
+	}
+	if step.BrowserConfig.OutputSchemaFile != "" {
+		files = append(files, step.BrowserConfig.OutputSchemaFile)
+	}
+	return files
+}
+
+// dependencyDigests resolves the cache digest of every step.DependsOn entry's result. It returns
+// an error if a dependency hasn't run yet (e.g. a typo in depends_on, or the steps are out of
+// order), since that makes the digest unsafe to trust.
+func dependencyDigests(step *types.Step, stepResults StepResultsContext) ([]string, error) {
+	digests := make([]string, 0, len(step.DependsOn))
+	for _, depID := range step.DependsOn {
+		depResult, ok := stepResults[depID]
+		if !ok {
+			return nil, fmt.Errorf("depends_on %q has not run yet", depID)
+		}
+		digest, err := cache.ResultDigest(depResult)
+		if err != nil {
+			return nil, fmt.Errorf("digesting result of dependency %q: %w", depID, err)
+		}
+		digests = append(digests, digest)
+	}
+	return digests, nil
+}
+
+// resolveArtifactPaths expands an ArtifactSpec's Path (a file, directory, or glob) relative to
+// workflowDir into the concrete paths to archive.
+func resolveArtifactPaths(workflowDir, pattern string) ([]string, error) {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(workflowDir, pattern)
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("expanding artifact path %q: %w", pattern, err)
+	}
+	if matches == nil {
+		return nil, fmt.Errorf("artifact path %q matched no files", pattern)
+	}
+	return matches, nil
+}
+
+// restoreArtifactDeps extracts every artifact resolvedStep depends on into workflowDir before it
+// runs.
+func (e *WorkflowEngine) restoreArtifactDeps(resolvedStep *types.Step, workflowDir string) error {
+	for _, dep := range resolvedStep.ArtifactDeps {
+		if err := artifacts.Extract(e.ArtifactsDir, e.RunID, dep.Step, dep.Name, workflowDir); err != nil {
+			return fmt.Errorf("restoring artifact %q from step %q: %w", dep.Name, dep.Step, err)
+		}
+		e.Logger.Info().Msgf("Restored artifact %q from step %q into %q", dep.Name, dep.Step, workflowDir)
+	}
+	return nil
+}
+
+// archiveStepArtifacts archives every artifact resolvedStep declares as produced, logging a
+// manifest entry for each to the run's JSON log stream, and returns each one's ArtifactResult
+// (keyed by ArtifactSpec.Name) for the engine to attach to the step's StepResult.
+func (e *WorkflowEngine) archiveStepArtifacts(resolvedStep *types.Step, workflowDir string, logger Logger) (map[string]types.ArtifactResult, error) {
+	results := make(map[string]types.ArtifactResult, len(resolvedStep.Artifacts))
+	for _, spec := range resolvedStep.Artifacts {
+		paths, err := resolveArtifactPaths(workflowDir, spec.Path)
+		if err != nil {
+			return nil, fmt.Errorf("resolving artifact %q: %w", spec.Name, err)
+		}
+
+		entry, err := artifacts.Archive(e.ArtifactsDir, e.RunID, resolvedStep.ID, spec.Name, paths)
+		if err != nil {
+			return nil, fmt.Errorf("archiving artifact %q: %w", spec.Name, err)
+		}
+
+		logger.Info().Interface("artifact", entry).Msgf("Archived artifact %q for step %q", spec.Name, resolvedStep.ID)
+		results[spec.Name] = types.ArtifactResult{Path: paths[0]}
+	}
+	return results, nil
+}
+
+// buildSummaryReport creates a fresh Report seeded with wf's metadata if step summaries are
+// enabled (e.SummaryDir != ""), or returns nil otherwise.
+func (e *WorkflowEngine) buildSummaryReport(wf *Workflow, varCtx VarContext) *summary.Report {
+	if e.SummaryDir == "" {
+		return nil
+	}
+	return summary.NewReport(summary.Meta{
+		WorkflowName: wf.Name,
+		Inputs:       redactedInputs(wf, varCtx),
+		StartedAt:    time.Now(),
+	})
+}
+
+// redactedInputs returns the workflow's resolved input values, omitting any declared `secret:
+// true`, for safe inclusion in the shareable step summary report.
+func redactedInputs(wf *Workflow, varCtx VarContext) map[string]string {
+	inputs := make(map[string]string)
+	for _, input := range wf.Inputs {
+		if input.Secret {
+			continue
+		}
+		if v, ok := varCtx[input.Name]; ok {
+			inputs[input.Name] = v
+		}
+	}
+	return inputs
+}
+
+// recordStepSummary appends resolvedStep's entry to report (a no-op if report is nil) and
+// flushes the report to disk, reading any Markdown the step wrote to summaryPath and any WARN+
+// issues e.SummarySink captured for it.
+func (e *WorkflowEngine) recordStepSummary(report *summary.Report, resolvedStep *types.Step, summaryPath, status string, duration time.Duration) {
+	if report == nil {
+		return
+	}
+
+	var body string
+	if summaryPath != "" {
+		if data, err := os.ReadFile(summaryPath); err == nil {
+			body = string(data)
+		}
+	}
+
+	var issues []string
+	if e.SummarySink != nil {
+		issues = e.SummarySink.Issues(resolvedStep.ID)
+	}
+
+	report.AddStep(summary.StepEntry{
+		StepID:   resolvedStep.ID,
+		Uses:     resolvedStep.Uses,
+		Status:   status,
+		Duration: duration,
+		Body:     body,
+		Issues:   issues,
+	})
+
+	if err := report.Flush(e.SummaryDir, e.RunID); err != nil {
+		e.Logger.Warn().Msgf("Failed to write step summary report: %v", err)
+	}
+}
+
+// appendUnique appends val to list unless it's empty or already present.
+func appendUnique(list []string, val string) []string {
+	if val == "" {
+		return list
+	}
+	for _, v := range list {
+		if v == val {
+			return list
+		}
+	}
+	return append(list, val)
+}
+
+// effectiveRemoteConfig returns the remote.Config a step should run through, or nil if it should
+// run locally. A step's own command.remote takes precedence over the workflow's execution.remote,
+// so a single heavy step can opt into (or out of) remote dispatch without moving every other step.
+func effectiveRemoteConfig(wf *Workflow, step *types.Step) *remote.Config {
+	if step.Command != nil && step.Command.Remote != nil {
+		r := step.Command.Remote
+		return &remote.Config{
+			Endpoint:          r.Endpoint,
+			InstanceName:      r.InstanceName,
+			TLS:               r.TLS,
+			Platform:          r.Platform,
+			CacheOnly:         r.CacheOnly,
+			Timeout:           r.Timeout,
+			OutputFiles:       r.OutputFiles,
+			OutputDirectories: r.OutputDirectories,
+			SkipCacheLookup:   r.SkipCacheLookup,
+			DoNotCache:        r.DoNotCache,
+		}
+	}
+	if wf.Execution != nil && wf.Execution.Remote != nil {
+		r := wf.Execution.Remote
+		return &remote.Config{
+			Endpoint:          r.Endpoint,
+			InstanceName:      r.InstanceName,
+			TLS:               r.TLS,
+			Platform:          r.Platform,
+			CacheOnly:         r.CacheOnly,
+			Timeout:           r.Timeout,
+			OutputFiles:       r.OutputFiles,
+			OutputDirectories: r.OutputDirectories,
+			SkipCacheLookup:   r.SkipCacheLookup,
+			DoNotCache:        r.DoNotCache,
+		}
+	}
+	return nil
+}
+
+// withTimeout wraps ctx in a context.WithTimeout derived from raw (a Go duration string, e.g. from
+// a workflow's or step's `timeout:` field), returning ctx unchanged (and a no-op cancel) if raw is
+// empty. An invalid duration string is reported as an error rather than silently ignored.
+func withTimeout(ctx context.Context, raw string) (context.Context, context.CancelFunc, error) {
+	if raw == "" {
+		return ctx, func() {}, nil
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid timeout %q: %w", raw, err)
+	}
+	newCtx, cancel := context.WithTimeout(ctx, d)
+	return newCtx, cancel, nil
+}
+
 func (e *WorkflowEngine) ExecuteWorkflow(
+	ctx context.Context,
 	wf *Workflow,
 	varCtx VarContext,
 	initialStepResults StepResultsContext,
 	workflowDir string,
 	resolvedProviders map[string]ProviderConfig,
 	// APIKeyGetter func(providerType string) string,
-) (StepResultsContext, error) {
+) (StepResultsContext, map[string]any, error) {
 	stepResults := initialStepResults
 	if stepResults == nil {
 		stepResults = make(StepResultsContext)
 	}
 
-	for _, step := range wf.Steps {
-		e.Logger.Info().Msgf("Running step %q (uses=%s)", step.ID, step.Uses)
+	ctx, cancel, err := withTimeout(ctx, wf.Timeout)
+	if err != nil {
+		return stepResults, nil, fmt.Errorf("workflow %q: %w", wf.Name, err)
+	}
+	defer cancel()
+
+	ctx, span := tracing.Tracer().Start(ctx, "workflow "+wf.Name, trace.WithAttributes(
+		attribute.String("workflow.name", wf.Name),
+	))
+	defer span.End()
+
+	report := e.buildSummaryReport(wf, varCtx)
+
+	if wf.Parallel > 1 {
+		if err := e.executeStepsConcurrently(ctx, wf, varCtx, stepResults, workflowDir, resolvedProviders, report); err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return stepResults, nil, err
+		}
+	} else {
+		for _, step := range wf.Steps {
+			if err := e.executeStep(ctx, wf, step, varCtx, stepResults, workflowDir, resolvedProviders, report, nil); err != nil {
+				span.SetStatus(codes.Error, err.Error())
+				return stepResults, nil, err
+			}
+		}
+	}
+
+	outputs, err := ResolveWorkflowOutputs(wf, varCtx, stepResults)
+	if err != nil {
+		return stepResults, nil, fmt.Errorf("resolving outputs for workflow %q: %w", wf.Name, err)
+	}
+
+	return stepResults, outputs, nil
+}
+
+// withLock runs fn while holding mu, or runs it unguarded if mu is nil (the sequential path,
+// where stepResults and report are never touched from more than one goroutine).
+func withLock(mu *sync.Mutex, fn func()) {
+	if mu == nil {
+		fn()
+		return
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	fn()
+}
+
+// evaluateStepCondition evaluates a step's If/Unless (either may be empty) against varCtx and
+// stepResults, guarded by mu since expr.Evaluate reads the shared stepResults map. skip reports
+// whether the step should be skipped — If evaluating false, or Unless evaluating true, either one
+// is enough — and reason is a human-readable explanation for the skip log line.
+func evaluateStepCondition(
+	mu *sync.Mutex,
+	ifExpr, unlessExpr string,
+	varCtx VarContext,
+	stepResults StepResultsContext,
+) (skip bool, reason string, err error) {
+	if ifExpr != "" {
+		var proceed bool
+		var evalErr error
+		withLock(mu, func() {
+			proceed, evalErr = expr.Evaluate(ifExpr, varCtx, stepResults)
+		})
+		if evalErr != nil {
+			return false, "", fmt.Errorf("evaluating 'if' %q: %w", ifExpr, evalErr)
+		}
+		if !proceed {
+			return true, fmt.Sprintf("if %q evaluated false", ifExpr), nil
+		}
+	}
+
+	if unlessExpr != "" {
+		var skipCond bool
+		var evalErr error
+		withLock(mu, func() {
+			skipCond, evalErr = expr.Evaluate(unlessExpr, varCtx, stepResults)
+		})
+		if evalErr != nil {
+			return false, "", fmt.Errorf("evaluating 'unless' %q: %w", unlessExpr, evalErr)
+		}
+		if skipCond {
+			return true, fmt.Sprintf("unless %q evaluated true", unlessExpr), nil
+		}
+	}
+
+	return false, "", nil
+}
+
+// executeStep resolves, validates, and runs a single step, recording its result into stepResults
+// and its entry into report. mu, if non-nil, guards every access to stepResults and report, since
+// executeStepsConcurrently invokes this from multiple goroutines at once; the sequential caller
+// passes nil and pays no locking cost.
+func (e *WorkflowEngine) executeStep(
+	ctx context.Context,
+	wf *Workflow,
+	step types.Step,
+	varCtx VarContext,
+	stepResults StepResultsContext,
+	workflowDir string,
+	resolvedProviders map[string]ProviderConfig,
+	report *summary.Report,
+	mu *sync.Mutex,
+) error {
+	if len(step.Matrix) > 0 {
+		return e.executeMatrixStep(ctx, wf, step, varCtx, stepResults, workflowDir, resolvedProviders, report, mu)
+	}
+
+	var resolvedStep *types.Step
+	var resolveErr error
+	withLock(mu, func() {
+		resolvedStep, resolveErr = ResolveStepVariables(&step, varCtx, stepResults, wf.Inputs...)
+	})
+	if resolveErr != nil {
+		return fmt.Errorf("could not resolve variables for step %q: %w", step.ID, resolveErr)
+	}
+	applyDefaultAssertions(resolvedStep, wf)
+
+	if resolvedStep.If != "" || resolvedStep.Unless != "" {
+		skip, reason, evalErr := evaluateStepCondition(mu, resolvedStep.If, resolvedStep.Unless, varCtx, stepResults)
+		if evalErr != nil {
+			return fmt.Errorf("evaluating condition for step %q: %w", resolvedStep.ID, evalErr)
+		}
+		if skip {
+			e.Logger.Info().Msgf("Skipping step %q (uses=%s): %s", resolvedStep.ID, resolvedStep.Uses, reason)
+			withLock(mu, func() {
+				stepResults[resolvedStep.ID] = types.StepResult{Output: "skipped"}
+				e.recordStepSummary(report, resolvedStep, "", "skipped", 0)
+			})
+			return nil
+		}
+	}
+
+	e.Logger.Info().Msgf("Running step %q (uses=%s)", resolvedStep.ID, resolvedStep.Uses)
+
+	stepCtx, stepSpan := tracing.Tracer().Start(ctx, "step "+resolvedStep.ID, trace.WithAttributes(
+		attribute.String("step.id", resolvedStep.ID),
+		attribute.String("step.uses", resolvedStep.Uses),
+		attribute.String("step.provider", resolvedStep.Provider),
+	))
+	defer stepSpan.End()
+
+	scopedLogger := e.Logger.With().Str("step_id", resolvedStep.ID).Str("step_type", resolvedStep.Uses).Logger()
+
+	execCtx := types.ExecutionContext{
+		Ctx:         stepCtx,
+		Step:        *resolvedStep,
+		Logger:      scopedLogger,
+		WorkflowDir: workflowDir,
+		AddMask:     e.MaskFunc,
+		AddPath:     e.PathFunc,
+		PathDirs:    e.PathDirs,
+		NoProgress:  e.NoProgress,
+		Silent:      e.Silent,
+		EvalExpr: func(expression string) (any, error) {
+			var result any
+			var evalErr error
+			withLock(mu, func() {
+				result, evalErr = evalExpr(expression, varCtx, stepResults)
+			})
+			return result, evalErr
+		},
+	}
+	if e.SummaryDir != "" {
+		execCtx.StepSummaryPath = summary.StepSummaryPath(e.SummaryDir, e.RunID, resolvedStep.ID)
+	}
+
+	if resolvedStep.Uses == "browser_agent" {
+		providerConf, found := resolvedProviders[resolvedStep.Provider]
+		if !found {
+			return fmt.Errorf("step %q references provider %q, which is not defined in providers", resolvedStep.ID, resolvedStep.Provider)
+		}
+
+		execCtx.APIKey = providerConf.APIKey
+		if execCtx.APIKey == "" {
+			return fmt.Errorf("API key for provider %q is empty", resolvedStep.Provider)
+		}
+
+		if report != nil {
+			withLock(mu, func() {
+				report.Meta.Providers = appendUnique(report.Meta.Providers, providerConf.Type)
+			})
+		}
+	}
 
-		resolvedStep, err := ResolveStepVariables(&step, varCtx, stepResults)
+	cacheEnabled := e.Cache != nil && !e.NoCache && stepWantsCache(resolvedStep)
+	var digest string
+	if cacheEnabled {
+		var depDigests []string
+		var digestErr error
+		withLock(mu, func() {
+			depDigests, digestErr = dependencyDigests(resolvedStep, stepResults)
+		})
+		if digestErr != nil {
+			return fmt.Errorf("computing cache digest for step %q: %w", resolvedStep.ID, digestErr)
+		}
+		d, err := cache.Digest(resolvedStep, referencedFiles(resolvedStep), depDigests)
 		if err != nil {
-			return stepResults, fmt.Errorf("could not resolve variables for step %q: %w", step.ID, err)
+			return fmt.Errorf("computing cache digest for step %q: %w", resolvedStep.ID, err)
 		}
+		digest = d
 
-		scopedLogger := e.Logger.With().Str("step_id", resolvedStep.ID).Str("step_type", resolvedStep.Uses).Logger()
+		cached, found, lookupErr := e.Cache.Get(digest)
+		if lookupErr != nil {
+			e.Logger.Warn().Msgf("Cache lookup failed for step %q, running normally: %v", resolvedStep.ID, lookupErr)
+		} else if found {
+			e.Logger.Info().Msgf("Cache hit for step %q (digest %s), skipping execution", resolvedStep.ID, digest)
+			withLock(mu, func() {
+				stepResults[resolvedStep.ID] = cached
+				e.recordStepSummary(report, resolvedStep, "", "skipped", 0)
+			})
+			return nil
+		}
+	}
 
-		execCtx := types.ExecutionContext{
-			Step:        *resolvedStep,
-			Logger:      scopedLogger,
-			WorkflowDir: workflowDir,
+	if e.ArtifactsDir != "" && len(resolvedStep.ArtifactDeps) > 0 {
+		if err := e.restoreArtifactDeps(resolvedStep, workflowDir); err != nil {
+			return fmt.Errorf("restoring artifacts for step %q: %w", resolvedStep.ID, err)
 		}
+	}
 
-		if resolvedStep.Uses == "browser_agent" {
-			providerConf, found := resolvedProviders[resolvedStep.Provider]
-			if !found {
-				return stepResults, fmt.Errorf("step %q references provider %q, which is not defined in providers", resolvedStep.ID, resolvedStep.Provider)
+	var cleanupSecrets func()
+	if len(resolvedStep.SecretMounts) > 0 {
+		values := make(map[string]string, len(resolvedStep.SecretMounts))
+		for _, m := range resolvedStep.SecretMounts {
+			val, ok := varCtx[m.InputRef]
+			if !ok {
+				return fmt.Errorf("step %q: secret_mounts %q references undefined input %q", resolvedStep.ID, m.Name, m.InputRef)
 			}
+			values[m.InputRef] = val
+		}
 
-			execCtx.APIKey = providerConf.APIKey
-			if execCtx.APIKey == "" {
-				return stepResults, fmt.Errorf("API key for provider %q is empty", resolvedStep.Provider)
+		mounted, cleanup, err := secretmount.Stage(resolvedStep.SecretMounts, values)
+		if err != nil {
+			return fmt.Errorf("step %q: %w", resolvedStep.ID, err)
+		}
+		cleanupSecrets = cleanup
+
+		execCtx.SecretMountEnv = mounted.Env
+		if err := resolveSecretPaths(&execCtx.Step, mounted.Paths); err != nil {
+			cleanup()
+			return fmt.Errorf("step %q: %w", resolvedStep.ID, err)
+		}
+	}
+
+	stepStart := time.Now()
+	result, runErr := e.runAndArchive(stepCtx, wf, resolvedStep, execCtx, workflowDir, scopedLogger, cleanupSecrets)
+	if runErr != nil {
+		stepSpan.SetStatus(codes.Error, runErr.Error())
+		withLock(mu, func() {
+			e.recordStepSummary(report, resolvedStep, execCtx.StepSummaryPath, "failed", time.Since(stepStart))
+		})
+		return runErr
+	}
+
+	if result != nil {
+		e.Logger.Debug().Msgf("Storing result for step %q", resolvedStep.ID)
+
+		withLock(mu, func() {
+			stepResults[resolvedStep.ID] = *result
+
+			if cacheEnabled {
+				if err := e.Cache.Put(digest, *result); err != nil {
+					e.Logger.Warn().Msgf("Failed to write cache entry for step %q: %v", resolvedStep.ID, err)
+				}
 			}
+		})
+	}
+
+	withLock(mu, func() {
+		e.recordStepSummary(report, resolvedStep, execCtx.StepSummaryPath, "ok", time.Since(stepStart))
+	})
+
+	return nil
+}
+
+// runAndArchive gets the runner for resolvedStep, wraps it for remote execution if configured,
+// runs it under resolvedStep's timeout, and merges any statically declared artifacts into the
+// result. Shared by executeStep's single-invocation path and executeMatrixStep's per-cell path,
+// which differ only in how they arrive at a resolvedStep/execCtx and how they record the outcome.
+func (e *WorkflowEngine) runAndArchive(
+	ctx context.Context,
+	wf *Workflow,
+	resolvedStep *types.Step,
+	execCtx types.ExecutionContext,
+	workflowDir string,
+	scopedLogger Logger,
+	cleanupSecrets func(),
+) (*types.StepResult, error) {
+	stepCtx, stepCancel, err := withTimeout(ctx, resolvedStep.Timeout)
+	if err != nil {
+		if cleanupSecrets != nil {
+			cleanupSecrets()
+		}
+		return nil, fmt.Errorf("step %q: %w", resolvedStep.ID, err)
+	}
+
+	// GetRunner resolves within stepCtx too, not just Run: a step.uses naming an unresolved
+	// pkg/steplib reference clones it over the network here, and that clone should be bounded by
+	// the same per-step timeout as the step's own execution, not run unbounded ahead of it.
+	runner, err := steprunner.GetRunner(stepCtx, execCtx)
+	if err != nil {
+		stepCancel()
+		if cleanupSecrets != nil {
+			cleanupSecrets()
+		}
+		return nil, fmt.Errorf("error getting runner for step %q: %w", resolvedStep.ID, err)
+	}
+
+	if remoteCfg := effectiveRemoteConfig(wf, resolvedStep); remoteCfg != nil && e.RemoteBlobs != nil && e.RemoteResults != nil {
+		runner = remote.Wrap(runner, *resolvedStep, *remoteCfg, e.RemoteBlobs, e.RemoteResults, scopedLogger)
+	}
+
+	result, runErr := runner.Run(stepCtx)
+	stepCancel()
+	if cleanupSecrets != nil {
+		cleanupSecrets()
+	}
+	if runErr != nil {
+		if stepCtx.Err() != nil {
+			scopedLogger.Error().Str("event", "step_aborted").Err(stepCtx.Err()).Msg("Step aborted")
 		}
+		return nil, fmt.Errorf("error running step %q: %w", resolvedStep.ID, runErr)
+	}
 
-		runner, err := steprunner.GetRunner(execCtx)
+	if result != nil && e.ArtifactsDir != "" && len(resolvedStep.Artifacts) > 0 {
+		artifactResults, err := e.archiveStepArtifacts(resolvedStep, workflowDir, scopedLogger)
 		if err != nil {
-			return stepResults, fmt.Errorf("error getting runner for step %q: %w", resolvedStep.ID, err)
+			return nil, fmt.Errorf("archiving artifacts for step %q: %w", resolvedStep.ID, err)
+		}
+		// Merge rather than overwrite: a shell/python step may have already registered artifacts
+		// dynamically via a "##dropstep##" stream event (see pkg/steprunner.StreamEvent) before
+		// its statically declared ones are archived here.
+		if result.Artifacts == nil {
+			result.Artifacts = artifactResults
+		} else {
+			for name, ar := range artifactResults {
+				result.Artifacts[name] = ar
+			}
 		}
+	}
 
-		result, err := runner.Run()
+	if result != nil && resolvedStep.BrowserConfig.OutputSchemaFile != "" {
+		schema, err := e.loadOutputSchema(workflowDir, resolvedStep.BrowserConfig.OutputSchemaFile)
 		if err != nil {
-			return stepResults, fmt.Errorf("error running step %q: %w", resolvedStep.ID, err)
+			return nil, fmt.Errorf("step %q: loading output_schema: %w", resolvedStep.ID, err)
 		}
+		// Coerce scalar leaves (a shell/http step's output is often all strings) to the types
+		// output_schema declares, so a downstream "{{ steps.foo.output.count > 0 }}" expression
+		// sees a real number instead of a string that happens to look like one.
+		result.Output = coerceToOutputSchema(schema, result.Output)
+	}
 
-		if result != nil {
-			e.Logger.Debug().Msgf("Storing result for step %q", resolvedStep.ID)
-			stepResults[resolvedStep.ID] = *result
+	return result, nil
+}
+
+// matrixCells returns the cartesian product of matrix's value lists, one map per cell keyed by
+// matrix variable name, in a deterministic order (matrix keys sorted, then each key's values in
+// declared order).
+func matrixCells(matrix map[string][]string) []map[string]string {
+	keys := make([]string, 0, len(matrix))
+	for k := range matrix {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	cells := []map[string]string{{}}
+	for _, k := range keys {
+		next := make([]map[string]string, 0, len(cells)*len(matrix[k]))
+		for _, cell := range cells {
+			for _, v := range matrix[k] {
+				c := make(map[string]string, len(cell)+1)
+				for ck, cv := range cell {
+					c[ck] = cv
+				}
+				c[k] = v
+				next = append(next, c)
+			}
+		}
+		cells = next
+	}
+	return cells
+}
+
+// matrixCoordLabel renders a matrix cell's coordinates deterministically (sorted by key) as
+// "k1=v1,k2=v2", for inclusion in per-cell logs (see ConsoleSink's "matrix" field).
+func matrixCoordLabel(coords map[string]string) string {
+	keys := make([]string, 0, len(coords))
+	for k := range coords {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+coords[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+// executeMatrixStep expands step's matrix into one cell per cartesian-product coordinate, runs
+// up to step.Parallelism of them at once (or all at once if unset), and aggregates their
+// StepResults into a single entry in stepResults keyed by step.ID: Output becomes an ordered list
+// of each cell's Output, and MatrixResults carries each cell's full StepResult alongside its
+// coordinates. Cancels remaining cells on the first failure unless step.FailFast is explicitly
+// false. Caching and 'if'/'unless' are evaluated once for the step as a whole, before expansion,
+// not once per cell: a matrix cell is a fan-out of a single step's work, not a step in its own right.
+func (e *WorkflowEngine) executeMatrixStep(
+	ctx context.Context,
+	wf *Workflow,
+	step types.Step,
+	varCtx VarContext,
+	stepResults StepResultsContext,
+	workflowDir string,
+	resolvedProviders map[string]ProviderConfig,
+	report *summary.Report,
+	mu *sync.Mutex,
+) error {
+	if step.If != "" || step.Unless != "" {
+		skip, reason, evalErr := evaluateStepCondition(mu, step.If, step.Unless, varCtx, stepResults)
+		if evalErr != nil {
+			return fmt.Errorf("evaluating condition for step %q: %w", step.ID, evalErr)
+		}
+		if skip {
+			e.Logger.Info().Msgf("Skipping step %q (uses=%s): %s", step.ID, step.Uses, reason)
+			withLock(mu, func() {
+				stepResults[step.ID] = types.StepResult{Output: "skipped"}
+				e.recordStepSummary(report, &step, "", "skipped", 0)
+			})
+			return nil
+		}
+	}
+
+	cells := matrixCells(step.Matrix)
+
+	parallelism := step.Parallelism
+	if parallelism <= 0 {
+		parallelism = len(cells)
+	}
+	failFast := step.FailFast == nil || *step.FailFast
+
+	runCtx, cancelRun := context.WithCancel(ctx)
+	defer cancelRun()
+
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	cellResults := make([]types.MatrixCellResult, len(cells))
+	var errMu sync.Mutex
+	var firstErr error
+
+	stepStart := time.Now()
+
+	for i, coords := range cells {
+		wg.Add(1)
+		go func(i int, coords map[string]string) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+			case <-runCtx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			if runCtx.Err() != nil {
+				return
+			}
+
+			cellVarCtx := make(VarContext, len(varCtx)+len(coords))
+			for k, v := range varCtx {
+				cellVarCtx[k] = v
+			}
+			for k, v := range coords {
+				cellVarCtx["matrix."+k] = v
+			}
+
+			cellStep := step
+			cellStep.Matrix = nil
+			label := matrixCoordLabel(coords)
+
+			result, cellErr := e.runMatrixCell(runCtx, wf, cellStep, cellVarCtx, stepResults, workflowDir, resolvedProviders, mu, coords, label)
+
+			cr := types.MatrixCellResult{Coordinates: coords}
+			if result != nil {
+				cr.Result = *result
+			}
+			if cellErr != nil {
+				cr.Error = cellErr.Error()
+			}
+			cellResults[i] = cr
+
+			if cellErr != nil {
+				errMu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("matrix cell [%s] of step %q: %w", label, step.ID, cellErr)
+				}
+				errMu.Unlock()
+				if failFast {
+					cancelRun()
+				}
+			}
+		}(i, coords)
+	}
+
+	wg.Wait()
+
+	outputs := make([]any, len(cellResults))
+	for i, cr := range cellResults {
+		outputs[i] = cr.Result.Output
+	}
+
+	status := "ok"
+	if firstErr != nil {
+		status = "failed"
+	}
+	withLock(mu, func() {
+		stepResults[step.ID] = types.StepResult{Output: outputs, MatrixResults: cellResults}
+		e.recordStepSummary(report, &step, "", status, time.Since(stepStart))
+	})
+
+	return firstErr
+}
+
+// runMatrixCell resolves and runs one matrix cell: cellStep is a copy of the matrix step with
+// Matrix already cleared, cellVarCtx carries that cell's "matrix.<key>" entries on top of the
+// workflow's own vars, and coords/label identify the cell for ExecutionContext.MatrixVars and
+// logging. It does not touch stepResults or report; executeMatrixStep aggregates the outcome.
+// Unlike the single-step path, a matrix cell never consults or populates the step cache: each
+// cell's own command differs only in the templated values the digest would already capture, and
+// the added bookkeeping isn't worth it until a real workflow asks for it.
+func (e *WorkflowEngine) runMatrixCell(
+	ctx context.Context,
+	wf *Workflow,
+	cellStep types.Step,
+	cellVarCtx VarContext,
+	stepResults StepResultsContext,
+	workflowDir string,
+	resolvedProviders map[string]ProviderConfig,
+	mu *sync.Mutex,
+	coords map[string]string,
+	label string,
+) (*types.StepResult, error) {
+	var resolvedStep *types.Step
+	var resolveErr error
+	withLock(mu, func() {
+		resolvedStep, resolveErr = ResolveStepVariables(&cellStep, cellVarCtx, stepResults, wf.Inputs...)
+	})
+	if resolveErr != nil {
+		return nil, fmt.Errorf("could not resolve variables for step %q: %w", cellStep.ID, resolveErr)
+	}
+	applyDefaultAssertions(resolvedStep, wf)
+
+	e.Logger.Info().Msgf("Running step %q matrix cell [%s] (uses=%s)", resolvedStep.ID, label, resolvedStep.Uses)
+	scopedLogger := e.Logger.With().
+		Str("step_id", resolvedStep.ID).
+		Str("step_type", resolvedStep.Uses).
+		Str("matrix", label).
+		Logger()
+
+	cellCtx, cellSpan := tracing.Tracer().Start(ctx, "step "+resolvedStep.ID+" ["+label+"]", trace.WithAttributes(
+		attribute.String("step.id", resolvedStep.ID),
+		attribute.String("step.uses", resolvedStep.Uses),
+		attribute.String("step.provider", resolvedStep.Provider),
+		attribute.String("step.matrix_cell", label),
+	))
+	defer cellSpan.End()
+	ctx = cellCtx
+
+	execCtx := types.ExecutionContext{
+		Ctx:         ctx,
+		Step:        *resolvedStep,
+		Logger:      scopedLogger,
+		WorkflowDir: workflowDir,
+		AddMask:     e.MaskFunc,
+		AddPath:     e.PathFunc,
+		PathDirs:    e.PathDirs,
+		NoProgress:  e.NoProgress,
+		Silent:      e.Silent,
+		MatrixVars:  coords,
+		EvalExpr: func(expression string) (any, error) {
+			var result any
+			var evalErr error
+			withLock(mu, func() {
+				result, evalErr = evalExpr(expression, cellVarCtx, stepResults)
+			})
+			return result, evalErr
+		},
+	}
+	if e.SummaryDir != "" {
+		execCtx.StepSummaryPath = summary.StepSummaryPath(e.SummaryDir, e.RunID, resolvedStep.ID)
+	}
+
+	if resolvedStep.Uses == "browser_agent" {
+		providerConf, found := resolvedProviders[resolvedStep.Provider]
+		if !found {
+			return nil, fmt.Errorf("step %q references provider %q, which is not defined in providers", resolvedStep.ID, resolvedStep.Provider)
+		}
+		execCtx.APIKey = providerConf.APIKey
+		if execCtx.APIKey == "" {
+			return nil, fmt.Errorf("API key for provider %q is empty", resolvedStep.Provider)
+		}
+	}
+
+	if e.ArtifactsDir != "" && len(resolvedStep.ArtifactDeps) > 0 {
+		if err := e.restoreArtifactDeps(resolvedStep, workflowDir); err != nil {
+			return nil, fmt.Errorf("restoring artifacts for step %q: %w", resolvedStep.ID, err)
 		}
 	}
 
-	return stepResults, nil
+	var cleanupSecrets func()
+	if len(resolvedStep.SecretMounts) > 0 {
+		values := make(map[string]string, len(resolvedStep.SecretMounts))
+		for _, m := range resolvedStep.SecretMounts {
+			val, ok := cellVarCtx[m.InputRef]
+			if !ok {
+				return nil, fmt.Errorf("step %q: secret_mounts %q references undefined input %q", resolvedStep.ID, m.Name, m.InputRef)
+			}
+			values[m.InputRef] = val
+		}
+
+		mounted, cleanup, err := secretmount.Stage(resolvedStep.SecretMounts, values)
+		if err != nil {
+			return nil, fmt.Errorf("step %q: %w", resolvedStep.ID, err)
+		}
+		cleanupSecrets = cleanup
+
+		execCtx.SecretMountEnv = mounted.Env
+		if err := resolveSecretPaths(&execCtx.Step, mounted.Paths); err != nil {
+			cleanup()
+			return nil, fmt.Errorf("step %q: %w", resolvedStep.ID, err)
+		}
+	}
+
+	result, err := e.runAndArchive(ctx, wf, resolvedStep, execCtx, workflowDir, scopedLogger, cleanupSecrets)
+	if err != nil {
+		cellSpan.SetStatus(codes.Error, err.Error())
+	}
+	return result, err
 }
@@ -0,0 +1,207 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	pkgexpr "github.com/arnavsurve/dropstep/pkg/expr"
+)
+
+// exprCallRe matches an identifier immediately followed by "(", e.g. "contains(" — the call
+// syntax looksLikeExpression uses to recognize a function-call expression body.
+var exprCallRe = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*\(`)
+
+// exprBracketSegmentRe matches a "[...]" path segment, e.g. the array index, wildcard, or filter
+// predicate syntax splitKeyPath (jsonpath.go) already understands — including one containing a
+// "==" inside a "[?(@.field==\"val\")]" predicate. looksLikeExpression strips these before
+// checking for this file's own operators, since that "==" belongs to the older path grammar.
+var exprBracketSegmentRe = regexp.MustCompile(`\[[^\[\]]*\]`)
+
+// exprQuotedStringRe matches a double-quoted substring (with "\\\"" escapes), e.g. a filter's
+// string argument. looksLikeExpression strips these too, so a literal like "call(me)" inside one
+// doesn't get mistaken for this file's call syntax.
+var exprQuotedStringRe = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+
+// looksLikeExpression reports whether body needs the operator/function-call expression parser in
+// this file, rather than the plain "var | filter arg..." grammar evalTemplateExpr already handles
+// (see resolveExprValue). Anything using comparison/logical operators or "name(args)" call syntax
+// qualifies; a bare "{{ var }}" or "{{ var | filter arg }}" placeholder never matches this and so
+// keeps resolving exactly as it always has. Two things are stripped out before that check: a
+// "[...]" array-index/wildcard/filter-predicate path segment (see splitKeyPath), since its own
+// "==" / "!=" (inside a "[?(@.field==\"val\")]" predicate) isn't this file's operator syntax at
+// all, and any quoted "..." substring, so a filter's string argument merely containing one of
+// these characters (e.g. "{{ name | default \"call(me)\" }}") isn't misdetected either. A body
+// that mixes a bracket path with an expression operator (e.g. "items[0].id == \"a\"") is not yet
+// supported — only one or the other.
+func looksLikeExpression(body string) bool {
+	stripped := exprQuotedStringRe.ReplaceAllString(body, "")
+	stripped = exprBracketSegmentRe.ReplaceAllString(stripped, "")
+	if strings.ContainsAny(stripped, "<>!") {
+		return true
+	}
+	if strings.Contains(stripped, "&&") || strings.Contains(stripped, "||") || strings.Contains(stripped, "==") {
+		return true
+	}
+	return exprCallRe.MatchString(stripped)
+}
+
+// evalExpr evaluates body as an expression via pkg/expr (the same operator/call grammar if:/
+// unless: conditions use), resolving its dotted variable references through FindValueInContext
+// instead of pkg/expr's own inputs/steps/env lookup so a template also gets
+// steps.<id>.outputs.<name>, steps.<id>.artifacts.<name>.path, and the ".json" suffix shortcut.
+// Any failure is wrapped with the offending expression text so a caller wrapping with its step ID
+// (every ResolveStringWithContext call site does, e.g. "resolving call.url for step %q: %w")
+// produces a precise, traceable error.
+func evalExpr(body string, globals VarContext, results StepResultsContext) (any, error) {
+	result, err := pkgexpr.EvalValue(body, pkgexpr.Env{
+		Resolve: func(path string) (any, bool) {
+			return FindValueInContext(path, globals, results)
+		},
+		Funcs: exprFunctions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("evaluating expression %q: %w", body, err)
+	}
+	return result, nil
+}
+
+// exprFunc is a function callable as "name(arg1, arg2, ...)" from inside an expression. Unlike a
+// TemplateFilter (see filters.go), which transforms one piped-in value plus literal string
+// arguments, an exprFunc's arguments are each independently-evaluated expressions, so e.g.
+// default's fallback may itself be another call or a nested path. Declared as an alias so
+// exprFunctions is directly assignable to pkgexpr.Env.Funcs without a conversion.
+type exprFunc = func(args []any) (any, error)
+
+// exprFunctions wraps several existing TemplateFilter implementations (filters.go) so both
+// "{{ token | default \"fallback\" }}" and "{{ default(token, \"fallback\") }}" share one
+// underlying implementation rather than maintaining the logic twice. Passed to pkg/expr as
+// Env.Funcs, where it's consulted ahead of that package's own built-ins (contains, startsWith,
+// int, matches), so e.g. this package's array-aware exprContains isn't shadowed by pkg/expr's
+// stricter string-only one.
+var exprFunctions = map[string]exprFunc{
+	"default":  exprDefault,
+	"toJSON":   exprToJSON,
+	"fromJSON": exprFromJSON,
+	"contains": exprContains,
+	"join":     exprJoin,
+	"split":    exprSplit,
+	"upper":    exprUpper,
+	"lower":    exprLower,
+	"trim":     exprTrim,
+	"replace":  exprReplace,
+	"basename": exprBasename,
+	"dirname":  exprDirname,
+}
+
+func exprDefault(args []any) (any, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("expects 2 arguments, got %d", len(args))
+	}
+	return filterDefault(args[0], fmt.Sprintf("%v", args[1]))
+}
+
+func exprToJSON(args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expects 1 argument, got %d", len(args))
+	}
+	return filterJSON(args[0])
+}
+
+func exprFromJSON(args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expects 1 argument, got %d", len(args))
+	}
+	s, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("expects a string argument, got %T", args[0])
+	}
+	var decoded any
+	if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+		return nil, fmt.Errorf("value is not valid JSON: %w", err)
+	}
+	return decoded, nil
+}
+
+func exprContains(args []any) (any, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("expects 2 arguments, got %d", len(args))
+	}
+	needle := fmt.Sprintf("%v", args[1])
+	switch v := args[0].(type) {
+	case string:
+		return strings.Contains(v, needle), nil
+	case []any:
+		for _, item := range v {
+			if fmt.Sprintf("%v", item) == needle {
+				return true, nil
+			}
+		}
+		return false, nil
+	default:
+		return false, nil
+	}
+}
+
+func exprJoin(args []any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("expects 1 or 2 arguments, got %d", len(args))
+	}
+	if len(args) == 2 {
+		return filterJoin(args[0], fmt.Sprintf("%v", args[1]))
+	}
+	return filterJoin(args[0])
+}
+
+func exprSplit(args []any) (any, error) {
+	if len(args) < 1 || len(args) > 2 {
+		return nil, fmt.Errorf("expects 1 or 2 arguments, got %d", len(args))
+	}
+	if len(args) == 2 {
+		return filterSplit(args[0], fmt.Sprintf("%v", args[1]))
+	}
+	return filterSplit(args[0])
+}
+
+func exprUpper(args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expects 1 argument, got %d", len(args))
+	}
+	return filterUpper(args[0])
+}
+
+func exprLower(args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expects 1 argument, got %d", len(args))
+	}
+	return filterLower(args[0])
+}
+
+func exprTrim(args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expects 1 argument, got %d", len(args))
+	}
+	return filterTrim(args[0])
+}
+
+func exprReplace(args []any) (any, error) {
+	if len(args) != 3 {
+		return nil, fmt.Errorf("expects 3 arguments (value, old, new), got %d", len(args))
+	}
+	return filterReplace(args[0], fmt.Sprintf("%v", args[1]), fmt.Sprintf("%v", args[2]))
+}
+
+func exprBasename(args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expects 1 argument, got %d", len(args))
+	}
+	return filterBasename(args[0])
+}
+
+func exprDirname(args []any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expects 1 argument, got %d", len(args))
+	}
+	return filterDirname(args[0])
+}
@@ -0,0 +1,96 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveStringWithContext_Expressions(t *testing.T) {
+	globals := core.VarContext{
+		"name": "  Ada Lovelace  ",
+	}
+	results := core.StepResultsContext{
+		"fetch": {
+			Output: map[string]any{"count": float64(3), "status": "ok"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"greater than true branch", `{{ steps.fetch.output.count > 0 && "yes" || "no" }}`, "yes"},
+		{"greater than false branch", `{{ steps.fetch.output.count > 10 && "yes" || "no" }}`, "no"},
+		{"equality", `{{ steps.fetch.output.status == "ok" }}`, "true"},
+		{"inequality", `{{ steps.fetch.output.status != "ok" }}`, "false"},
+		{"negation", `{{ !(steps.fetch.output.status == "fail") }}`, "true"},
+		{"default call with present value", `{{ default(steps.fetch.output.status, "fallback") }}`, "ok"},
+		{"default call with missing value", `{{ default(steps.fetch.output.missing, "fallback") }}`, "fallback"},
+		{"toJSON call", `{{ toJSON(steps.fetch.output.count) }}`, "3"},
+		{"fromJSON call", `{{ fromJSON("[1,2,3]") }}`, "[1 2 3]"},
+		{"contains over a string", `{{ contains(steps.fetch.output.status, "o") }}`, "true"},
+		{"join call", `{{ join(fromJSON("[\"a\",\"b\"]"), "-") }}`, "a-b"},
+		{"split call", `{{ split("a,b,c", ",") }}`, "[a b c]"},
+		{"upper call", `{{ upper(steps.fetch.output.status) }}`, "OK"},
+		{"lower call", `{{ lower("OK") }}`, "ok"},
+		{"trim call", `{{ trim(name) }}`, "Ada Lovelace"},
+		{"replace call", `{{ replace(steps.fetch.output.status, "o", "0") }}`, "0k"},
+		{"basename call", `{{ basename("a/b/c.txt") }}`, "c.txt"},
+		{"dirname call", `{{ dirname("a/b/c.txt") }}`, "a/b"},
+		{"nested call inside comparison", `{{ upper(steps.fetch.output.status) == "OK" }}`, "true"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := core.ResolveStringWithContext(tc.input, globals, results)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestResolveStringWithContext_Expressions_Errors(t *testing.T) {
+	_, err := core.ResolveStringWithContext("{{ notafunc(1, 2) }}", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown function "notafunc"`)
+
+	_, err = core.ResolveStringWithContext(`{{ undefined == "x" }}`, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "undefined variable: undefined")
+
+	_, err = core.ResolveStringWithContext(`{{ "a" > "b" }}`, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "requires numeric operands")
+}
+
+// TestResolveStringWithContext_Expressions_BackwardCompat confirms that a bracket
+// filter-predicate path (see TestResolveStringWithContext_StepArrayPaths) keeps resolving through
+// the older path-based grammar rather than being misdetected as a "==" comparison.
+func TestResolveStringWithContext_Expressions_BackwardCompat(t *testing.T) {
+	results := core.StepResultsContext{
+		"fetch": {
+			Output: map[string]any{
+				"items": []any{
+					map[string]any{"id": "a", "status": "ok"},
+					map[string]any{"id": "b", "status": "fail"},
+				},
+			},
+		},
+	}
+
+	result, err := core.ResolveStringWithContext(
+		`{{ steps.fetch.output.items[?(@.status=="ok")].id.json }}`, nil, results)
+	require.NoError(t, err)
+	assert.Equal(t, `["a"]`, result)
+
+	// A quoted filter argument that happens to contain operator-like characters must still
+	// resolve through the pipe-filter grammar, not be misdetected as a call expression.
+	result, err = core.ResolveStringWithContext(
+		`{{ name | default "call(me)" }}`, core.VarContext{"name": ""}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "call(me)", result)
+}
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TemplateFilter transforms a resolved template value given whatever literal or nested-expression
+// arguments followed its name in a "| name arg1 arg2" pipe stage (see evalTemplateExpr). value and
+// the return are `any`, not string, so a chain like "| json | jsonpath \"$.foo\"" can hand a
+// filter its source's native decoded type instead of round-tripping through a string in between.
+type TemplateFilter func(value any, args ...string) (any, error)
+
+var (
+	templateFiltersMu sync.RWMutex
+	templateFilters   = map[string]TemplateFilter{}
+)
+
+// RegisterTemplateFilter adds a named filter to the pipe-filter registry ResolveStringWithContext
+// consults for "{{ ... | name arg }}" syntax, overwriting any existing filter of the same name.
+// Callers outside this package (a browser/command/call step handler with a domain-specific
+// transform) typically call this from an init() func, the same convention
+// steprunner.RegisterRunnerFactory uses for step runners.
+func RegisterTemplateFilter(name string, fn func(any, ...string) (any, error)) {
+	templateFiltersMu.Lock()
+	defer templateFiltersMu.Unlock()
+	templateFilters[name] = fn
+}
+
+func lookupTemplateFilter(name string) (TemplateFilter, bool) {
+	templateFiltersMu.RLock()
+	defer templateFiltersMu.RUnlock()
+	fn, ok := templateFilters[name]
+	return fn, ok
+}
+
+func init() {
+	RegisterTemplateFilter("default", filterDefault)
+	RegisterTemplateFilter("upper", filterUpper)
+	RegisterTemplateFilter("lower", filterLower)
+	RegisterTemplateFilter("trim", filterTrim)
+	RegisterTemplateFilter("basename", filterBasename)
+	RegisterTemplateFilter("dirname", filterDirname)
+	RegisterTemplateFilter("json", filterJSON)
+	RegisterTemplateFilter("jsonpath", filterJSONPath)
+	RegisterTemplateFilter("b64enc", filterB64enc)
+	RegisterTemplateFilter("b64dec", filterB64dec)
+	RegisterTemplateFilter("sha256", filterSHA256)
+	RegisterTemplateFilter("replace", filterReplace)
+	RegisterTemplateFilter("split", filterSplit)
+	RegisterTemplateFilter("join", filterJoin)
+	RegisterTemplateFilter("quote", filterQuote)
+	RegisterTemplateFilter("int", filterInt)
+	RegisterTemplateFilter("now", filterNow)
+}
+
+// filterDefault returns args[0] if value is nil or stringifies to "", otherwise value unchanged.
+func filterDefault(value any, args ...string) (any, error) {
+	if value == nil || fmt.Sprintf("%v", value) == "" {
+		if len(args) == 0 {
+			return nil, fmt.Errorf("default: missing fallback argument")
+		}
+		return args[0], nil
+	}
+	return value, nil
+}
+
+func filterUpper(value any, _ ...string) (any, error) {
+	return strings.ToUpper(fmt.Sprintf("%v", value)), nil
+}
+
+func filterLower(value any, _ ...string) (any, error) {
+	return strings.ToLower(fmt.Sprintf("%v", value)), nil
+}
+
+func filterTrim(value any, _ ...string) (any, error) {
+	return strings.TrimSpace(fmt.Sprintf("%v", value)), nil
+}
+
+func filterBasename(value any, _ ...string) (any, error) {
+	return filepath.Base(fmt.Sprintf("%v", value)), nil
+}
+
+func filterDirname(value any, _ ...string) (any, error) {
+	return filepath.Dir(fmt.Sprintf("%v", value)), nil
+}
+
+func filterJSON(value any, _ ...string) (any, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("json: %w", err)
+	}
+	return string(b), nil
+}
+
+// filterJSONPath supports the small subset of JSONPath its one required argument form needs:
+// dotted field access and a "[N]" numeric array index, e.g. "$.foo[0].bar". It is not a full
+// JSONPath implementation (no wildcards, slices, or filter expressions) — pkg/assertions' own
+// "#"-wildcard path selector already covers that case for assertions; this filter exists for
+// pulling one value out of a step's JSON text in a template.
+func filterJSONPath(value any, args ...string) (any, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("jsonpath: missing path argument")
+	}
+	data := value
+	if s, ok := value.(string); ok {
+		var decoded any
+		if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+			return nil, fmt.Errorf("jsonpath: value is not valid JSON: %w", err)
+		}
+		data = decoded
+	}
+	result, found := navigateJSONPath(data, jsonPathSegments(args[0]))
+	if !found {
+		return nil, fmt.Errorf("jsonpath %q: not found", args[0])
+	}
+	return result, nil
+}
+
+// jsonPathSegments normalizes a "$.foo[0].bar" style path into ["foo","0","bar"], treating a
+// bracketed index exactly like a following dotted segment.
+func jsonPathSegments(path string) []string {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.TrimPrefix(path, ".")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+func navigateJSONPath(data any, segments []string) (any, bool) {
+	current := data
+	for _, seg := range segments {
+		switch typed := current.(type) {
+		case map[string]any:
+			v, ok := typed[seg]
+			if !ok {
+				return nil, false
+			}
+			current = v
+		case []any:
+			idx, err := strconv.Atoi(seg)
+			if err != nil || idx < 0 || idx >= len(typed) {
+				return nil, false
+			}
+			current = typed[idx]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func filterB64enc(value any, _ ...string) (any, error) {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%v", value))), nil
+}
+
+func filterB64dec(value any, _ ...string) (any, error) {
+	b, err := base64.StdEncoding.DecodeString(fmt.Sprintf("%v", value))
+	if err != nil {
+		return nil, fmt.Errorf("b64dec: %w", err)
+	}
+	return string(b), nil
+}
+
+func filterSHA256(value any, _ ...string) (any, error) {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%v", value)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func filterReplace(value any, args ...string) (any, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("replace: expects 2 arguments (old, new), got %d", len(args))
+	}
+	return strings.ReplaceAll(fmt.Sprintf("%v", value), args[0], args[1]), nil
+}
+
+func filterSplit(value any, args ...string) (any, error) {
+	sep := ","
+	if len(args) > 0 {
+		sep = args[0]
+	}
+	parts := strings.Split(fmt.Sprintf("%v", value), sep)
+	result := make([]any, len(parts))
+	for i, p := range parts {
+		result[i] = p
+	}
+	return result, nil
+}
+
+func filterJoin(value any, args ...string) (any, error) {
+	sep := ","
+	if len(args) > 0 {
+		sep = args[0]
+	}
+	switch v := value.(type) {
+	case []any:
+		parts := make([]string, len(v))
+		for i, elem := range v {
+			parts[i] = fmt.Sprintf("%v", elem)
+		}
+		return strings.Join(parts, sep), nil
+	case []string:
+		return strings.Join(v, sep), nil
+	default:
+		return nil, fmt.Errorf("join: expects an array, got %T", value)
+	}
+}
+
+func filterQuote(value any, _ ...string) (any, error) {
+	return strconv.Quote(fmt.Sprintf("%v", value)), nil
+}
+
+func filterInt(value any, _ ...string) (any, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case float64:
+		return int(v), nil
+	case string:
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("int: %q is not an integer: %w", v, err)
+		}
+		return n, nil
+	default:
+		return nil, fmt.Errorf("int: unsupported type %T", value)
+	}
+}
+
+// filterNow ignores value (chained off an arbitrary or even undefined-but-defaulted variable
+// purely to invoke it) and formats the current time with a time.Format reference layout; the
+// default layout is RFC3339 when no layout argument is given.
+func filterNow(_ any, args ...string) (any, error) {
+	layout := time.RFC3339
+	if len(args) > 0 {
+		layout = args[0]
+	}
+	return time.Now().Format(layout), nil
+}
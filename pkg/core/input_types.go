@@ -0,0 +1,186 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// InputSpec is the Input declaration a Validator checks a resolved value against: its Type picked
+// the Validator in the first place, and the rest (Enum, Pattern, Min/Max, Items, Schema) are the
+// constraints that travel alongside it.
+type InputSpec = Input
+
+// Validator checks value (the input's resolved varfile/default string, or, once something
+// registers a JSON-shaped type, whatever that type decoded it to) against spec's constraints,
+// returning a descriptive error (not wrapped with the input's name; ValidateRequiredInputs adds
+// that) if it doesn't hold.
+type Validator func(value any, spec InputSpec) error
+
+// InputTypeRegistry maps an Input.Type name to the Validator that checks a value declared with
+// it. ValidateWorkflowStructure and ValidateRequiredInputs both consult DefaultInputTypeRegistry;
+// RegisterInputType is the supported way to add to it from outside this package.
+type InputTypeRegistry struct {
+	validators map[string]Validator
+}
+
+// NewInputTypeRegistry returns a registry pre-populated with the built-in input types: string,
+// file, number, boolean, url, duration, and enum.
+func NewInputTypeRegistry() *InputTypeRegistry {
+	r := &InputTypeRegistry{validators: make(map[string]Validator)}
+	r.Register("string", validateStringInput)
+	r.Register("file", validateFileInput)
+	r.Register("number", validateNumberInput)
+	r.Register("boolean", validateBooleanInput)
+	r.Register("url", validateURLInput)
+	r.Register("duration", validateDurationInput)
+	r.Register("enum", validateEnumInput)
+	return r
+}
+
+// Register adds or replaces the Validator for typeName.
+func (r *InputTypeRegistry) Register(typeName string, v Validator) {
+	r.validators[typeName] = v
+}
+
+// Has reports whether typeName has a registered Validator, so a caller (ValidateWorkflowStructure)
+// can reject an unknown input type at parse time with a precise error instead of this package's
+// own generic "no validator registered" one surfacing deeper in a run.
+func (r *InputTypeRegistry) Has(typeName string) bool {
+	_, ok := r.validators[typeName]
+	return ok
+}
+
+// Validate runs the Validator registered for spec.Type against value. Callers should check Has
+// first (ValidateWorkflowStructure does, at parse time); an unregistered type here is a workflow
+// that slipped past that check.
+func (r *InputTypeRegistry) Validate(value any, spec InputSpec) error {
+	v, ok := r.validators[spec.Type]
+	if !ok {
+		return fmt.Errorf("no validator registered for input type %q", spec.Type)
+	}
+	return v(value, spec)
+}
+
+// DefaultInputTypeRegistry is the registry ValidateWorkflowStructure and ValidateRequiredInputs
+// use. RegisterInputType adds to this one.
+var DefaultInputTypeRegistry = NewInputTypeRegistry()
+
+// RegisterInputType adds typeName to DefaultInputTypeRegistry, so a downstream user can declare
+// workflow inputs of a new type (e.g. "email") without forking this package.
+func RegisterInputType(typeName string, v Validator) {
+	DefaultInputTypeRegistry.Register(typeName, v)
+}
+
+func validateStringInput(value any, spec InputSpec) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected a string, got %T", value)
+	}
+	return checkStringConstraints(s, spec)
+}
+
+func validateFileInput(value any, spec InputSpec) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected a string, got %T", value)
+	}
+	if s == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	return checkStringConstraints(s, spec)
+}
+
+func validateNumberInput(value any, spec InputSpec) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected a string, got %T", value)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) {
+		return fmt.Errorf("%q is not a valid number", s)
+	}
+	if spec.Min != nil && f < *spec.Min {
+		return fmt.Errorf("%v is less than the minimum %v", f, *spec.Min)
+	}
+	if spec.Max != nil && f > *spec.Max {
+		return fmt.Errorf("%v is greater than the maximum %v", f, *spec.Max)
+	}
+	return nil
+}
+
+func validateBooleanInput(value any, spec InputSpec) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected a string, got %T", value)
+	}
+	if _, err := strconv.ParseBool(s); err != nil {
+		return fmt.Errorf("%q is not a valid boolean", s)
+	}
+	return nil
+}
+
+func validateURLInput(value any, spec InputSpec) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected a string, got %T", value)
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("%q is not a valid absolute URL", s)
+	}
+	return checkStringConstraints(s, spec)
+}
+
+func validateDurationInput(value any, spec InputSpec) error {
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected a string, got %T", value)
+	}
+	if _, err := time.ParseDuration(s); err != nil {
+		return fmt.Errorf("%q is not a valid duration: %w", s, err)
+	}
+	return nil
+}
+
+func validateEnumInput(value any, spec InputSpec) error {
+	if len(spec.Enum) == 0 {
+		return fmt.Errorf("type 'enum' requires a non-empty 'enum' list")
+	}
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected a string, got %T", value)
+	}
+	return checkStringConstraints(s, spec)
+}
+
+// checkStringConstraints applies the constraints any string-shaped input can declare regardless
+// of its specific Type: Enum (exact membership) and Pattern (regexp.MatchString). Either, both, or
+// neither may be set.
+func checkStringConstraints(s string, spec InputSpec) error {
+	if len(spec.Enum) > 0 {
+		matched := false
+		for _, allowed := range spec.Enum {
+			if s == allowed {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%q is not one of %v", s, spec.Enum)
+		}
+	}
+	if spec.Pattern != "" {
+		re, err := regexp.Compile(spec.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid 'pattern' %q: %w", spec.Pattern, err)
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("%q does not match pattern %q", s, spec.Pattern)
+		}
+	}
+	return nil
+}
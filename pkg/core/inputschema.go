@@ -0,0 +1,91 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// resolveInputSchema turns an Input.Schema map into an outputSchemaNode (the same minimal JSON
+// Schema subset browser.output_schema already uses): raw is either the schema object inline, or a
+// single-key {"$ref": "<path>"} pointing at an external schema file, resolved relative to
+// workflowDir the same way loadOutputSchemaFile resolves output_schema.
+func resolveInputSchema(workflowDir string, raw map[string]any) (*outputSchemaNode, error) {
+	if ref, ok := raw["$ref"]; ok && len(raw) == 1 {
+		refPath, ok := ref.(string)
+		if !ok {
+			return nil, fmt.Errorf("'$ref' must be a string path, got %T", ref)
+		}
+		return loadOutputSchemaFile(workflowDir, refPath)
+	}
+
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("re-encoding inline schema: %w", err)
+	}
+	var schema outputSchemaNode
+	if err := json.Unmarshal(b, &schema); err != nil {
+		return nil, fmt.Errorf("parsing inline schema: %w", err)
+	}
+	return &schema, nil
+}
+
+// validateValueAgainstSchema checks value's actual shape against schema: an object value must
+// carry every property schema.Required names and every declared property that's present must
+// itself validate; an array value's elements must each validate against schema.Items; a scalar
+// leaf (string/number/boolean) must be that Go type. Unlike validateOutputSchemaPath (which only
+// walks a reference's path without any data to check against), this has a real value in hand, so
+// it checks types and required properties, not just shape.
+func validateValueAgainstSchema(schema *outputSchemaNode, value any) error {
+	if schema == nil {
+		return nil
+	}
+
+	switch {
+	case schema.Type == "object" || (schema.Type == "" && schema.Properties != nil):
+		m, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected an object, got %T", value)
+		}
+		for _, req := range schema.Required {
+			if _, ok := m[req]; !ok {
+				return fmt.Errorf("missing required property %q", req)
+			}
+		}
+		for key, propSchema := range schema.Properties {
+			if v, exists := m[key]; exists {
+				if err := validateValueAgainstSchema(propSchema, v); err != nil {
+					return fmt.Errorf("property %q: %w", key, err)
+				}
+			}
+		}
+		return nil
+	case schema.Type == "array" || (schema.Type == "" && schema.Items != nil):
+		arr, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected an array, got %T", value)
+		}
+		for i, elem := range arr {
+			if err := validateValueAgainstSchema(schema.Items, elem); err != nil {
+				return fmt.Errorf("index %d: %w", i, err)
+			}
+		}
+		return nil
+	case schema.Type == "number" || schema.Type == "integer":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("expected a number, got %T", value)
+		}
+		return nil
+	case schema.Type == "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected a boolean, got %T", value)
+		}
+		return nil
+	case schema.Type == "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		return nil
+	default:
+		return nil
+	}
+}
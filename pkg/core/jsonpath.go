@@ -0,0 +1,168 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// splitKeyPath splits a "steps.<id>.<field>...." template key on top-level "." boundaries,
+// treating a "[...]" suffix (an array index, a "*" wildcard, or a "?(...)" filter predicate) as
+// one atomic segment instead of splitting on any "." inside it — which matters for a predicate
+// like "[?(@.status==\"ok\")]", whose own "@.status" would otherwise be split apart.
+func splitKeyPath(key string) []string {
+	var segments []string
+	var cur strings.Builder
+	i := 0
+	for i < len(key) {
+		switch key[i] {
+		case '.':
+			segments = append(segments, cur.String())
+			cur.Reset()
+			i++
+		case '[':
+			if cur.Len() > 0 {
+				segments = append(segments, cur.String())
+				cur.Reset()
+			}
+			j := i + 1
+			inQuote := false
+			for j < len(key) && (key[j] != ']' || inQuote) {
+				if key[j] == '"' {
+					inQuote = !inQuote
+				}
+				j++
+			}
+			segments = append(segments, key[i+1:j])
+			i = j + 1
+			if i < len(key) && key[i] == '.' {
+				i++
+			}
+		default:
+			cur.WriteByte(key[i])
+			i++
+		}
+	}
+	segments = append(segments, cur.String())
+	return segments
+}
+
+// filterPredicateRe matches a "?(@.field==\"value\")"/"?(@.field!=\"value\")" filter predicate
+// segment; this is the one comparison form GetNestedValue's filter step supports, not the full
+// JSONPath filter-expression grammar.
+var filterPredicateRe = regexp.MustCompile(`^\?\(@\.([a-zA-Z0-9_]+)\s*(==|!=)\s*"([^"]*)"\)$`)
+
+func isFilterPredicate(seg string) bool {
+	return filterPredicateRe.MatchString(seg)
+}
+
+func isArrayIndex(seg string) bool {
+	if seg == "" {
+		return false
+	}
+	_, err := strconv.Atoi(seg)
+	return err == nil
+}
+
+// evalFilterPredicate reports whether elem (one array element) satisfies the "?(@.field==...)"
+// predicate in seg.
+func evalFilterPredicate(seg string, elem any) bool {
+	m := filterPredicateRe.FindStringSubmatch(seg)
+	if m == nil {
+		return false
+	}
+	field, op, want := m[1], m[2], m[3]
+
+	obj, ok := elem.(map[string]any)
+	if !ok {
+		return false
+	}
+	got := fmt.Sprintf("%v", obj[field])
+
+	switch op {
+	case "==":
+		return got == want
+	case "!=":
+		return got != want
+	default:
+		return false
+	}
+}
+
+// GetNestedValue traverses data along path, a sequence of segments produced by splitKeyPath. Each
+// segment is one of: a map key, a numeric array index ("0"), a "*" wildcard that collects the
+// rest of path across every element of the current array, or a "?(@.field==\"value\")" filter
+// predicate that collects the rest of path across every matching element — the latter two mirror
+// pkg/assertions' own "#" wildcard selector, generalized to the couple of JSONPath shapes a
+// step's templated paths need (bracket indices, "[*]", and a single-field filter).
+func GetNestedValue(data any, path []string) (any, bool) {
+	if len(path) == 0 {
+		return data, true
+	}
+	if data == nil {
+		return nil, false
+	}
+
+	seg, rest := path[0], path[1:]
+
+	switch {
+	case seg == "*":
+		arr, ok := data.([]any)
+		if !ok {
+			return nil, false
+		}
+		return collectNestedValues(arr, rest), true
+	case isFilterPredicate(seg):
+		arr, ok := data.([]any)
+		if !ok {
+			return nil, false
+		}
+		matched := make([]any, 0, len(arr))
+		for _, elem := range arr {
+			if evalFilterPredicate(seg, elem) {
+				matched = append(matched, elem)
+			}
+		}
+		return collectNestedValues(matched, rest), true
+	case isArrayIndex(seg):
+		arr, ok := data.([]any)
+		if !ok {
+			return nil, false
+		}
+		idx, _ := strconv.Atoi(seg)
+		if idx < 0 || idx >= len(arr) {
+			return nil, false
+		}
+		return GetNestedValue(arr[idx], rest)
+	default:
+		switch typedCurrent := data.(type) {
+		case map[string]any:
+			val, exists := typedCurrent[seg]
+			if !exists {
+				return nil, false
+			}
+			return GetNestedValue(val, rest)
+		case map[string]string:
+			val, exists := typedCurrent[seg]
+			if !exists {
+				return nil, false
+			}
+			return GetNestedValue(val, rest)
+		default:
+			return nil, false
+		}
+	}
+}
+
+// collectNestedValues resolves rest against every element of arr, skipping (not failing) an
+// element rest doesn't match, so "items[*].id" still yields the ids of the items that have one.
+func collectNestedValues(arr []any, rest []string) []any {
+	collected := make([]any, 0, len(arr))
+	for _, elem := range arr {
+		if v, ok := GetNestedValue(elem, rest); ok {
+			collected = append(collected, v)
+		}
+	}
+	return collected
+}
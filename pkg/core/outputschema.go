@@ -0,0 +1,215 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arnavsurve/dropstep/pkg/fileutil"
+	"gopkg.in/yaml.v3"
+)
+
+// outputSchemaNode is the small subset of JSON Schema this package understands: enough to walk a
+// "{{ steps.<id>.output.<path> }}" reference's path and know what shape (and, at the leaves, what
+// scalar type) should be there. Anything a schema expresses beyond type/properties/items/required
+// (enums, formats, $ref, etc.) is ignored rather than rejected, since the goal here is catching a
+// typo'd property name, not fully validating a step's declared schema.
+type outputSchemaNode struct {
+	Type       string                       `json:"type"`
+	Properties map[string]*outputSchemaNode `json:"properties"`
+	Items      *outputSchemaNode            `json:"items"`
+	Required   []string                     `json:"required"`
+}
+
+// loadOutputSchemaFile reads and parses the JSON Schema at path (resolved the same way a step's
+// other workflow-relative paths are, via ResolvePathFromWorkflow).
+func loadOutputSchemaFile(workflowDir, path string) (*outputSchemaNode, error) {
+	resolvedPath, err := fileutil.ResolvePathFromWorkflow(workflowDir, path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path %q: %w", path, err)
+	}
+	data, err := os.ReadFile(resolvedPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading %q: %w", resolvedPath, err)
+	}
+	var schema outputSchemaNode
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, fmt.Errorf("parsing %q as JSON Schema: %w", resolvedPath, err)
+	}
+	return &schema, nil
+}
+
+// stepOutputRef is one "{{ steps.<stepID>.output<path> }}" reference found in a downstream step's
+// config, where path is the dotted/bracketed tail after "output" (possibly empty, meaning the
+// reference is to the whole output), already split into GetNestedValue-style segments with any
+// trailing ".json" suffix dropped (that asks for the whole value serialized, not a property).
+type stepOutputRef struct {
+	stepID  string
+	path    []string
+	rawPath string
+}
+
+// stepOutputRefRe matches a "{{ steps.<id>.output<path>" reference up through the end of its
+// path, the same segment grammar splitKeyPath understands (dotted keys, "[N]", "[*]", and
+// "[?(@.field==\"value\")]"), leaving whatever pipe/filter chain or closing "}}" follows alone.
+var stepOutputRefRe = regexp.MustCompile(`\{\{\s*steps\.([a-zA-Z0-9_-]+)\.output((?:\.[a-zA-Z0-9_-]+|\[[^\[\]]*\])*)`)
+
+// findStepOutputRefs scans raw (a step marshaled back to YAML, the same technique checkInputScope
+// uses to cover every field in one pass) for every steps.<id>.output reference.
+func findStepOutputRefs(raw []byte) []stepOutputRef {
+	var refs []stepOutputRef
+	for _, m := range stepOutputRefRe.FindAllSubmatch(raw, -1) {
+		stepID := string(m[1])
+		suffix := string(m[2])
+
+		var segments []string
+		if trimmed := strings.TrimPrefix(suffix, "."); trimmed != "" {
+			segments = splitKeyPath(trimmed)
+		}
+		if n := len(segments); n > 0 && segments[n-1] == "json" {
+			segments = segments[:n-1]
+		}
+
+		refs = append(refs, stepOutputRef{
+			stepID:  stepID,
+			path:    segments,
+			rawPath: fmt.Sprintf("steps.%s.output%s", stepID, suffix),
+		})
+	}
+	return refs
+}
+
+// validateOutputSchemaPath walks path (as produced by findStepOutputRefs) through schema, the way
+// GetNestedValue walks actual data: a "*", array index, or filter-predicate segment descends into
+// Items, anything else looks up a property. It returns an error naming the first segment that
+// schema's explicit "type" positively rules out; a node this schema subset can't see into any
+// further (an untyped array's "items", an object with no "properties" listed, meaning
+// additionalProperties) ends the walk without an error, since nothing more can honestly be checked
+// past that point.
+func validateOutputSchemaPath(schema *outputSchemaNode, path []string) error {
+	cur := schema
+	for _, seg := range path {
+		if cur == nil {
+			return nil
+		}
+		switch {
+		case seg == "*" || isArrayIndex(seg) || isFilterPredicate(seg):
+			if cur.Type != "" && cur.Type != "array" {
+				return fmt.Errorf("%q is not an array in the schema", seg)
+			}
+			cur = cur.Items
+		default:
+			if cur.Type != "" && cur.Type != "object" {
+				return fmt.Errorf("%q is not an object in the schema", seg)
+			}
+			if cur.Properties == nil {
+				return nil
+			}
+			next, ok := cur.Properties[seg]
+			if !ok {
+				return fmt.Errorf("%q does not exist", seg)
+			}
+			cur = next
+		}
+	}
+	return nil
+}
+
+// ValidateStepOutputReferences statically type-checks every "{{ steps.<id>.output.<path> }}"
+// reference in wf's steps against the JSON Schema the producing step declared via
+// browser.output_schema, failing fast with a precise error instead of waiting for a runtime
+// "undefined variable" error once the workflow is already running. A step whose producing step
+// declares no output_schema, or whose path uses a shape this package's schema subset can't
+// resolve, is left unchecked.
+func ValidateStepOutputReferences(wf *Workflow, workflowDir string) error {
+	schemas := make(map[string]*outputSchemaNode)
+	for _, step := range wf.Steps {
+		if step.BrowserConfig.OutputSchemaFile == "" {
+			continue
+		}
+		schema, err := loadOutputSchemaFile(workflowDir, step.BrowserConfig.OutputSchemaFile)
+		if err != nil {
+			return fmt.Errorf("step %q: loading output_schema: %w", step.ID, err)
+		}
+		schemas[step.ID] = schema
+	}
+	if len(schemas) == 0 {
+		return nil
+	}
+
+	for _, step := range wf.Steps {
+		// Marshaling step back to YAML, the same technique checkInputScope uses, covers every
+		// field (prompt, command, headers, matrix values, ...) in one pass rather than
+		// special-casing each one here.
+		raw, err := yaml.Marshal(step)
+		if err != nil {
+			continue
+		}
+		for _, ref := range findStepOutputRefs(raw) {
+			schema, ok := schemas[ref.stepID]
+			if !ok {
+				continue
+			}
+			if err := validateOutputSchemaPath(schema, ref.path); err != nil {
+				return fmt.Errorf("step %q references %q which does not exist in %s's schema: %w", step.ID, ref.rawPath, ref.stepID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// coerceToOutputSchema converts value's scalar leaves to match schema's declared types wherever a
+// step runner (shell, http, etc.) produced them as strings, so a downstream expression like
+// "{{ steps.foo.output.count > 0 }}" sees a real number rather than a string that happens to look
+// like one. Only scalar coercion at object/array boundaries schema describes is attempted; a shape
+// mismatch is left as-is for the runtime "undefined variable"/type-mismatch errors to catch instead
+// of failing the step on a schema that doesn't quite match what was produced.
+func coerceToOutputSchema(schema *outputSchemaNode, value any) any {
+	if schema == nil || value == nil {
+		return value
+	}
+
+	switch {
+	// A schema commonly declares "properties"/"items" without the redundant "type" keyword
+	// alongside them, so infer object/array from their presence too, not just an explicit type.
+	case schema.Type == "object" || (schema.Type == "" && schema.Properties != nil):
+		m, ok := value.(map[string]any)
+		if !ok {
+			return value
+		}
+		for key, propSchema := range schema.Properties {
+			if v, exists := m[key]; exists {
+				m[key] = coerceToOutputSchema(propSchema, v)
+			}
+		}
+		return m
+	case schema.Type == "array" || (schema.Type == "" && schema.Items != nil):
+		arr, ok := value.([]any)
+		if !ok {
+			return value
+		}
+		for i, elem := range arr {
+			arr[i] = coerceToOutputSchema(schema.Items, elem)
+		}
+		return arr
+	case schema.Type == "number" || schema.Type == "integer":
+		if s, ok := value.(string); ok {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(s), 64); err == nil {
+				return f
+			}
+		}
+		return value
+	case schema.Type == "boolean":
+		if s, ok := value.(string); ok {
+			if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+				return b
+			}
+		}
+		return value
+	default:
+		return value
+	}
+}
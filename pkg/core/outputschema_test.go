@@ -0,0 +1,117 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeOutputSchemaWorkflow(t *testing.T, dir, schemaJSON, prompt string) *core.Workflow {
+	t.Helper()
+
+	schemaPath := filepath.Join(dir, "schema.json")
+	require.NoError(t, os.WriteFile(schemaPath, []byte(schemaJSON), 0o644))
+
+	wfPath := filepath.Join(dir, "workflow.yml")
+	wfYaml := `
+name: output-schema-test
+inputs: []
+steps:
+  - id: fetch
+    uses: browser_agent
+    browser:
+      prompt: "fetch the thing"
+      output_schema: schema.json
+  - id: consume
+    uses: shell
+    run:
+      inline: "echo ` + prompt + `"
+`
+	require.NoError(t, os.WriteFile(wfPath, []byte(wfYaml), 0o644))
+
+	wf, err := core.LoadWorkflowFromFile(wfPath)
+	require.NoError(t, err)
+	return wf
+}
+
+func TestValidateStepOutputReferences_KnownProperty(t *testing.T) {
+	dir := t.TempDir()
+	wf := writeOutputSchemaWorkflow(t, dir, `{
+		"type": "object",
+		"properties": {
+			"count": {"type": "number"},
+			"items": {"type": "array", "items": {"type": "object", "properties": {"id": {"type": "string"}}}}
+		}
+	}`, `{{ steps.fetch.output.items[0].id }}`)
+
+	assert.NoError(t, core.ValidateStepOutputReferences(wf, dir))
+}
+
+func TestValidateStepOutputReferences_UnknownProperty(t *testing.T) {
+	dir := t.TempDir()
+	wf := writeOutputSchemaWorkflow(t, dir, `{
+		"type": "object",
+		"properties": {
+			"count": {"type": "number"}
+		}
+	}`, `{{ steps.fetch.output.total }}`)
+
+	err := core.ValidateStepOutputReferences(wf, dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `steps.fetch.output.total`)
+	assert.Contains(t, err.Error(), "does not exist in fetch's schema")
+}
+
+func TestValidateStepOutputReferences_SchemaWithoutExplicitType(t *testing.T) {
+	dir := t.TempDir()
+	// No top-level "type": "object" — a common, valid JSON Schema shorthand that's still
+	// unambiguously an object because it declares "properties".
+	wf := writeOutputSchemaWorkflow(t, dir, `{
+		"properties": {
+			"count": {"type": "number"}
+		}
+	}`, `{{ steps.fetch.output.count }}`)
+
+	assert.NoError(t, core.ValidateStepOutputReferences(wf, dir))
+}
+
+func TestValidateStepOutputReferences_UntypedArrayItemsAreUnchecked(t *testing.T) {
+	dir := t.TempDir()
+	// "tags" is declared as an array but its element shape is left open (no "items"), so a
+	// reference into one of its elements can't be statically checked and shouldn't be rejected.
+	wf := writeOutputSchemaWorkflow(t, dir, `{
+		"type": "object",
+		"properties": {
+			"tags": {"type": "array"}
+		}
+	}`, `{{ steps.fetch.output.tags[0] }}`)
+
+	assert.NoError(t, core.ValidateStepOutputReferences(wf, dir))
+}
+
+func TestValidateStepOutputReferences_NoSchemaIsUnchecked(t *testing.T) {
+	dir := t.TempDir()
+	wfPath := filepath.Join(dir, "workflow.yml")
+	wfYaml := `
+name: no-schema-test
+inputs: []
+steps:
+  - id: fetch
+    uses: shell
+    run:
+      inline: echo hi
+  - id: consume
+    uses: shell
+    run:
+      inline: "echo {{ steps.fetch.output.anything.goes }}"
+`
+	require.NoError(t, os.WriteFile(wfPath, []byte(wfYaml), 0o644))
+	wf, err := core.LoadWorkflowFromFile(wfPath)
+	require.NoError(t, err)
+
+	assert.NoError(t, core.ValidateStepOutputReferences(wf, dir))
+}
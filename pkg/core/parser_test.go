@@ -1,6 +1,7 @@
 package core_test
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 	"testing"
@@ -25,7 +26,7 @@ func (r *TestBrowserAgentRunner) Validate() error {
 	return nil
 }
 
-func (r *TestBrowserAgentRunner) Run() (*types.StepResult, error) {
+func (r *TestBrowserAgentRunner) Run(ctx context.Context) (*types.StepResult, error) {
 	return &types.StepResult{}, nil
 }
 
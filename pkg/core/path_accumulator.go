@@ -0,0 +1,31 @@
+package core
+
+import "sync"
+
+// PathAccumulator collects directories a step adds at runtime via its "::add-path::" workflow
+// command (see steprunner.HandleLine/LogBuffer), so every step started after the one that called
+// it gets the directory prepended to its subprocess PATH, mirroring how GitHub Actions' add-path
+// command affects the rest of the job. Safe for concurrent use, since steps on a workflow's
+// `parallel: N` path can add to it from different goroutines; zero value is ready to use.
+type PathAccumulator struct {
+	mu   sync.Mutex
+	dirs []string
+}
+
+// Add appends dir. A later step's subprocess PATH will see it prepended, ahead of the parent
+// process's own PATH; an empty dir is ignored.
+func (p *PathAccumulator) Add(dir string) {
+	if dir == "" {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.dirs = append(p.dirs, dir)
+}
+
+// Dirs returns every directory added so far, in the order Add was called.
+func (p *PathAccumulator) Dirs() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string{}, p.dirs...)
+}
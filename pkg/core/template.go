@@ -0,0 +1,237 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// scanTemplateExprs walks input looking for "{{ ... }}" placeholders, calling eval on each one's
+// trimmed body and splicing in whatever it returns. Unlike a single regex match, it tracks quote
+// state and brace nesting while looking for the closing "}}", so a quoted filter argument (see
+// evalTemplateExpr) or a nested "{{ ... }}" inside one doesn't end the expression early. An
+// unterminated "{{" is passed through literally, the same as the old regex-based resolver did for
+// any text it didn't recognize as a placeholder.
+func scanTemplateExprs(input string, eval func(body string) (string, error)) (string, error) {
+	var out strings.Builder
+	i := 0
+	for i < len(input) {
+		start := strings.Index(input[i:], "{{")
+		if start == -1 {
+			out.WriteString(input[i:])
+			break
+		}
+		start += i
+		out.WriteString(input[i:start])
+
+		end, ok := matchTemplateClose(input, start+2)
+		if !ok {
+			out.WriteString(input[start:])
+			break
+		}
+
+		resolved, err := eval(strings.TrimSpace(input[start+2 : end]))
+		if err != nil {
+			return "", err
+		}
+		out.WriteString(resolved)
+		i = end + 2
+	}
+	return out.String(), nil
+}
+
+// matchTemplateClose returns the index of the "}}" that closes the "{{" whose body starts at
+// from, treating a double-quoted substring (a filter's string argument) as opaque and counting a
+// nested "{{"/"}}" pair toward depth rather than closing the outer expression.
+func matchTemplateClose(input string, from int) (int, bool) {
+	depth := 1
+	inQuote := false
+	i := from
+	for i < len(input) {
+		switch {
+		case input[i] == '"' && (i == 0 || input[i-1] != '\\'):
+			inQuote = !inQuote
+			i++
+		case inQuote:
+			i++
+		case i+1 < len(input) && input[i] == '{' && input[i+1] == '{':
+			depth++
+			i += 2
+		case i+1 < len(input) && input[i] == '}' && input[i+1] == '}':
+			depth--
+			if depth == 0 {
+				return i, true
+			}
+			i += 2
+		default:
+			i++
+		}
+	}
+	return 0, false
+}
+
+// splitPipeStages splits a template expression body on top-level "|" characters, i.e. not ones
+// inside a quoted filter argument or a parenthesized nested expression. The first stage is always
+// the variable reference; the rest are "filter arg..." stages applied left to right.
+func splitPipeStages(body string) []string {
+	var stages []string
+	var cur strings.Builder
+	parenDepth := 0
+	inQuote := false
+	i := 0
+	for i < len(body) {
+		c := body[i]
+		switch {
+		case c == '"' && (i == 0 || body[i-1] != '\\'):
+			inQuote = !inQuote
+			cur.WriteByte(c)
+		case inQuote:
+			cur.WriteByte(c)
+		case c == '(':
+			parenDepth++
+			cur.WriteByte(c)
+		case c == ')':
+			if parenDepth > 0 {
+				parenDepth--
+			}
+			cur.WriteByte(c)
+		case c == '|' && parenDepth == 0:
+			stages = append(stages, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+		i++
+	}
+	stages = append(stages, cur.String())
+	return stages
+}
+
+// tokenizeFilterArgs splits a "filterName arg1 \"arg two\" (nested.expr)" stage on whitespace,
+// keeping a double-quoted argument (with "\\\"" escapes) as one token and a parenthesized argument
+// as one token including its parens, so the caller can recognize and recursively resolve it as a
+// nested template expression rather than a literal.
+func tokenizeFilterArgs(s string) []string {
+	var tokens []string
+	i, n := 0, len(s)
+	for i < n {
+		for i < n && isTemplateSpace(s[i]) {
+			i++
+		}
+		if i >= n {
+			break
+		}
+		switch s[i] {
+		case '"':
+			var sb strings.Builder
+			j := i + 1
+			for j < n && s[j] != '"' {
+				if s[j] == '\\' && j+1 < n {
+					sb.WriteByte(s[j+1])
+					j += 2
+					continue
+				}
+				sb.WriteByte(s[j])
+				j++
+			}
+			tokens = append(tokens, sb.String())
+			i = j + 1
+		case '(':
+			depth := 1
+			j := i + 1
+			for j < n && depth > 0 {
+				switch s[j] {
+				case '(':
+					depth++
+				case ')':
+					depth--
+				}
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			j := i
+			for j < n && !isTemplateSpace(s[j]) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+func isTemplateSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// evalTemplateExpr resolves one "{{ ... }}" expression body to its final string form. body is a
+// variable reference exactly as FindValueInContext already understood it (including its ".json"
+// suffix shortcut), optionally followed by one or more "| filter arg..." stages evaluated left to
+// right against registered filters (see RegisterTemplateFilter). A body with no pipe behaves
+// exactly as the resolver always has.
+func evalTemplateExpr(body string, globals VarContext, results StepResultsContext) (string, error) {
+	value, err := resolveExprValue(body, globals, results)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%v", value), nil
+}
+
+// resolveExprValue is evalTemplateExpr's untyped counterpart: it stops at the filter chain's final
+// value instead of stringifying it. Used at the top level and recursively to resolve a filter's
+// "(expr)" argument, so e.g. "token | default (env.FALLBACK | upper)" composes without round
+// tripping through a string between the nested expression and the outer filter.
+//
+// body using comparison/logical operators or "name(args)" call syntax (see looksLikeExpression)
+// is instead handed to evalExpr (expr.go), a small tokenizer/parser/evaluator supporting things
+// the pipe-filter grammar below can't express, e.g. "{{ steps.x.output.count > 0 && \"yes\" ||
+// \"no\" }}". A plain "{{ var }}" or "{{ var | filter arg }}" placeholder never matches that and
+// keeps resolving exactly as it always has.
+func resolveExprValue(body string, globals VarContext, results StepResultsContext) (any, error) {
+	if looksLikeExpression(body) {
+		return evalExpr(body, globals, results)
+	}
+
+	stages := splitPipeStages(body)
+	key := strings.TrimSpace(stages[0])
+
+	value, found := FindValueInContext(key, globals, results)
+	if !found {
+		return nil, fmt.Errorf("undefined variable: %s", key)
+	}
+
+	for _, stage := range stages[1:] {
+		stage = strings.TrimSpace(stage)
+		if stage == "" {
+			continue
+		}
+		tokens := tokenizeFilterArgs(stage)
+		name := tokens[0]
+		fn, ok := lookupTemplateFilter(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown template filter %q", name)
+		}
+
+		args := make([]string, 0, len(tokens)-1)
+		for _, tok := range tokens[1:] {
+			if strings.HasPrefix(tok, "(") && strings.HasSuffix(tok, ")") {
+				nestedVal, err := resolveExprValue(tok[1:len(tok)-1], globals, results)
+				if err != nil {
+					return nil, fmt.Errorf("filter %q argument: %w", name, err)
+				}
+				args = append(args, fmt.Sprintf("%v", nestedVal))
+			} else {
+				args = append(args, tok)
+			}
+		}
+
+		result, err := fn(value, args...)
+		if err != nil {
+			return nil, fmt.Errorf("filter %q: %w", name, err)
+		}
+		value = result
+	}
+
+	return value, nil
+}
@@ -1,6 +1,9 @@
 package core
 
-import "github.com/arnavsurve/dropstep/pkg/types"
+import (
+	"github.com/arnavsurve/dropstep/pkg/secrets"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
 
 type StepResultsContext = map[string]types.StepResult
 
@@ -16,14 +19,99 @@ type Input struct {
 	Required bool   `yaml:"required,omitempty"`
 	Secret   bool   `yaml:"secret,omitempty"`
 	Default  string `yaml:"default,omitempty"`
+
+	// AllowedSteps, AllowedUses, and AllowedProviders restrict which steps may reference this
+	// input as a template variable. Each is an allow-list matched against the consuming step's
+	// ID, Uses, and Provider respectively; an empty list means unrestricted on that axis.
+	// Referencing the input from a step that fails any check fails the run with a precise error.
+	AllowedSteps     []string `yaml:"allowed_steps,omitempty"`
+	AllowedUses      []string `yaml:"allowed_uses,omitempty"`
+	AllowedProviders []string `yaml:"allowed_providers,omitempty"`
+
+	// Enum, if non-empty, restricts the resolved value to one of these exact strings. Checked by
+	// every built-in string-shaped Validator (string, file, url), and is what the built-in "enum"
+	// type itself validates against.
+	Enum []string `yaml:"enum,omitempty"`
+	// Pattern, if set, is a regexp the resolved value must match. Checked by the same
+	// string-shaped Validators Enum is.
+	Pattern string `yaml:"pattern,omitempty"`
+	// Min and Max bound a "number" input's resolved value. Either, both, or neither may be set.
+	Min *float64 `yaml:"min,omitempty"`
+	Max *float64 `yaml:"max,omitempty"`
+	// Items, for an input type that validates a JSON array (not one of the built-ins; see
+	// RegisterInputType), describes the Input spec every element must satisfy.
+	Items *Input `yaml:"items,omitempty"`
+	// Schema, if set, is a JSON Schema (see pkg/core/inputschema.go's reuse of the
+	// output_schema subset) the resolved value is checked against in addition to whatever its
+	// Type's own Validator checks: either the schema object inline, or a single-key
+	// {"$ref": "<path>"} pointing at an external schema file resolved relative to the workflow
+	// directory, the same way browser.output_schema already works for step outputs.
+	Schema map[string]any `yaml:"schema,omitempty"`
 }
 
 type Workflow struct {
-	Name        string           `yaml:"name"`
-	Description string           `yaml:"description"`
-	Inputs      []Input          `yaml:"inputs"`
-	Providers   []ProviderConfig `yaml:"providers,omitempty"`
-	Steps       []Step           `yaml:"steps"`
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// On restricts which trigger events (e.g. "schedule", "webhook", "manual") may run this
+	// workflow at all; see pkg/planner. Empty means every trigger is accepted.
+	On        []string         `yaml:"on,omitempty"`
+	Inputs    []Input          `yaml:"inputs"`
+	Providers []ProviderConfig `yaml:"providers,omitempty"`
+	// Secrets declares the external secret providers a varfile's `{{ secret.<name>.<ref> }}`
+	// entries may resolve against (see pkg/secrets). Omitted or empty means no secret provider
+	// is configured; a varfile referencing one anyway is an error.
+	Secrets   []secrets.ProviderConfig `yaml:"secrets,omitempty"`
+	Execution *ExecutionConfig         `yaml:"execution,omitempty"`
+	// Timeout bounds the entire run (e.g. "10m"); a step may set its own, tighter `timeout:` too.
+	// Parsed with time.ParseDuration. Empty means no workflow-wide deadline.
+	Timeout string `yaml:"timeout,omitempty"`
+	// Parallel bounds how many steps may run at once. 0 or 1 (the default) runs steps strictly in
+	// declaration order, one at a time, exactly as before this field existed. A value above 1 has
+	// the engine build a DAG from each step's DependsOn and run independent steps concurrently
+	// through a worker pool of this size; steps with no depends_on relationship to each other may
+	// then execute out of declaration order.
+	Parallel int    `yaml:"parallel,omitempty"`
+	Steps    []Step `yaml:"steps"`
+	// Outputs declares named values this workflow exposes once every step has completed, each a
+	// template (see ResolveStringWithContext) resolved against the run's final VarContext and
+	// StepResultsContext, e.g. {{ steps.fetch.output.id }}. A parent workflow that imports this one
+	// via a `uses: workflow` step (see types.WorkflowCall) sees these as
+	// {{ steps.<id>.output.<name> }}.
+	Outputs map[string]string `yaml:"outputs,omitempty"`
+	// Assertions declares checks (see types.Assertion) applied to every step that doesn't define
+	// its own `assertions:` block. A step's own assertions, if any, replace these entirely rather
+	// than merging with them.
+	Assertions []types.Assertion `yaml:"assertions,omitempty"`
+}
+
+// ExecutionConfig selects where step commands actually run: locally on the dropstep host (the
+// default, when this is nil or Remote is nil), or dispatched against a remote worker pool via
+// pkg/steprunner/remote.
+type ExecutionConfig struct {
+	Remote *RemoteExecutionConfig `yaml:"remote,omitempty"`
+}
+
+// RemoteExecutionConfig points at a remote execution endpoint and the platform properties a
+// worker must match to accept a step's Action.
+type RemoteExecutionConfig struct {
+	Endpoint     string            `yaml:"endpoint"`
+	InstanceName string            `yaml:"instance_name,omitempty"`
+	TLS          bool              `yaml:"tls,omitempty"`
+	Platform     map[string]string `yaml:"platform,omitempty"`
+	// CacheOnly makes a content-addressed cache miss an error instead of falling back to running
+	// the step locally; see pkg/steprunner/remote's package doc for why that's the fallback today.
+	CacheOnly bool `yaml:"cache_only,omitempty"`
+	// Timeout bounds each step's remote dispatch attempt (e.g. "5m"), separately from the step's
+	// own `timeout:`. Parsed with time.ParseDuration. Empty means no separate deadline.
+	Timeout string `yaml:"timeout,omitempty"`
+	// OutputFiles and OutputDirectories declare the workflow-relative paths a remote worker would
+	// be expected to produce; see types.CommandRemoteConfig (the step-level equivalent of this
+	// block) for why they affect caching but not execution yet.
+	OutputFiles       []string `yaml:"output_files,omitempty"`
+	OutputDirectories []string `yaml:"output_directories,omitempty"`
+	// SkipCacheLookup and DoNotCache mirror types.CommandRemoteConfig's fields of the same name.
+	SkipCacheLookup bool `yaml:"skip_cache_lookup,omitempty"`
+	DoNotCache      bool `yaml:"do_not_cache,omitempty"`
 }
 
 type Step = types.Step
@@ -36,6 +124,10 @@ type CommandBlock = types.CommandBlock
 
 type ExecutionContext = types.ExecutionContext
 
+type ArtifactResult = types.ArtifactResult
+
+type Assertion = types.Assertion
+
 type Level = types.Level
 
 // Level constants
@@ -1,8 +1,16 @@
 package core
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"strings"
 
+	"github.com/arnavsurve/dropstep/pkg/assertions"
+	"github.com/arnavsurve/dropstep/pkg/expr"
+	"github.com/arnavsurve/dropstep/pkg/steplib"
 	"github.com/arnavsurve/dropstep/pkg/steprunner"
 	"github.com/arnavsurve/dropstep/pkg/types"
 )
@@ -13,13 +21,6 @@ func ValidateWorkflowStructure(wf *Workflow) error {
 		return fmt.Errorf("workflow is missing 'name'")
 	}
 
-	validInputTypes := map[string]bool{
-		"string":  true,
-		"file":    true,
-		"number":  true,
-		"boolean": true,
-	}
-
 	inputNames := make(map[string]bool)
 	for i, input := range wf.Inputs {
 		if input.Name == "" {
@@ -30,7 +31,7 @@ func ValidateWorkflowStructure(wf *Workflow) error {
 		}
 		inputNames[input.Name] = true
 
-		if !validInputTypes[input.Type] {
+		if !DefaultInputTypeRegistry.Has(input.Type) {
 			return fmt.Errorf("input %q has invalid type %q", input.Name, input.Type)
 		}
 	}
@@ -50,6 +51,25 @@ func ValidateWorkflowStructure(wf *Workflow) error {
 		}
 	}
 
+	if err := validateAssertions(wf.Assertions, "workflow"); err != nil {
+		return err
+	}
+
+	secretNames := make(map[string]bool)
+	for i, sp := range wf.Secrets {
+		if sp.Name == "" {
+			return fmt.Errorf("secret provider %d is missing 'name'", i)
+		}
+		if secretNames[sp.Name] {
+			return fmt.Errorf("duplicate secret provider name: %q", sp.Name)
+		}
+		secretNames[sp.Name] = true
+
+		if sp.Type == "" {
+			return fmt.Errorf("secret provider %q is missing 'type'", sp.Name)
+		}
+	}
+
 	stepIDs := make(map[string]bool)
 	for i, step := range wf.Steps {
 		if step.ID == "" {
@@ -65,28 +85,198 @@ func ValidateWorkflowStructure(wf *Workflow) error {
 		}
 	}
 
+	// Validate scoping allow-lists once every step ID and provider name is known, so a typo'd
+	// entry (e.g. a renamed step) is caught here rather than surfacing mid-run.
+	for _, input := range wf.Inputs {
+		for _, stepID := range input.AllowedSteps {
+			if !stepIDs[stepID] {
+				return fmt.Errorf("input %q: allowed_steps references unknown step %q", input.Name, stepID)
+			}
+		}
+		for _, providerName := range input.AllowedProviders {
+			if !providerNames[providerName] {
+				return fmt.Errorf("input %q: allowed_providers references unknown provider %q", input.Name, providerName)
+			}
+		}
+	}
+
+	validArtifactVia := map[string]bool{"": true, "file": true, "dir": true, "stdout": true}
+	validSandboxBackend := map[string]bool{"": true, "bwrap": true, "firejail": true, "sandbox-exec": true}
+
+	for _, step := range wf.Steps {
+		if step.If != "" {
+			if err := expr.Validate(step.If, stepIDs); err != nil {
+				return fmt.Errorf("step %q has invalid 'if' expression: %w", step.ID, err)
+			}
+		}
+		if step.Unless != "" {
+			if err := expr.Validate(step.Unless, stepIDs); err != nil {
+				return fmt.Errorf("step %q has invalid 'unless' expression: %w", step.ID, err)
+			}
+		}
+
+		for _, m := range step.SecretMounts {
+			if m.Name == "" {
+				return fmt.Errorf("step %q: secret_mounts entry is missing 'name'", step.ID)
+			}
+			if !inputNames[m.InputRef] {
+				return fmt.Errorf("step %q: secret_mounts %q references unknown input %q", step.ID, m.Name, m.InputRef)
+			}
+		}
+
+		for _, a := range step.Artifacts {
+			if a.Name == "" {
+				return fmt.Errorf("step %q: artifacts entry is missing 'name'", step.ID)
+			}
+			if !validArtifactVia[a.Via] {
+				return fmt.Errorf("step %q: artifact %q has invalid 'via' %q (expected file, dir, or stdout)", step.ID, a.Name, a.Via)
+			}
+		}
+
+		if err := validateAssertions(step.Assertions, fmt.Sprintf("step %q", step.ID)); err != nil {
+			return err
+		}
+
+		if step.Command != nil && step.Command.Resources != nil {
+			r := step.Command.Resources
+			if r.MaxMemoryMB < 0 {
+				return fmt.Errorf("step %q: resources.max_memory_mb must not be negative", step.ID)
+			}
+			if r.MaxCPUSeconds < 0 {
+				return fmt.Errorf("step %q: resources.max_cpu_seconds must not be negative", step.ID)
+			}
+			if r.Sandbox != nil && !validSandboxBackend[r.Sandbox.Backend] {
+				return fmt.Errorf("step %q: resources.sandbox.backend %q is not one of bwrap, firejail, or sandbox-exec", step.ID, r.Sandbox.Backend)
+			}
+		}
+
+		if len(step.Matrix) > 0 {
+			for key, values := range step.Matrix {
+				if key == "" {
+					return fmt.Errorf("step %q: matrix has an entry with an empty key", step.ID)
+				}
+				if len(values) == 0 {
+					return fmt.Errorf("step %q: matrix.%s must list at least one value", step.ID, key)
+				}
+			}
+			if step.Parallelism < 0 {
+				return fmt.Errorf("step %q: parallelism must not be negative", step.ID)
+			}
+		} else if step.Parallelism != 0 || step.FailFast != nil {
+			return fmt.Errorf("step %q: parallelism/fail_fast only apply to a step that also defines 'matrix'", step.ID)
+		}
+	}
+
 	return nil
 }
 
-func ValidateRequiredInputs(wf *Workflow, varCtx VarContext) error {
+// validateAssertions statically checks a step's or workflow's `assertions:` block. subject names
+// what owns checks in an error message, e.g. `step "deploy"` or "workflow".
+func validateAssertions(checks []types.Assertion, subject string) error {
+	for i, a := range checks {
+		if a.Path == "" {
+			return fmt.Errorf("%s: assertions[%d] is missing 'path'", subject, i)
+		}
+		if a.Path != "exit_code" && a.Path != "stdout" && a.Path != "stderr" && !strings.HasPrefix(a.Path, "result.") && a.Path != "result" {
+			return fmt.Errorf(`%s: assertions[%d] path %q must be "exit_code", "stdout", "stderr", "result", or start with "result."`, subject, i, a.Path)
+		}
+		if !assertions.IsKnownOperator(a.Should) {
+			return fmt.Errorf("%s: assertions[%d] has unknown 'should' %q", subject, i, a.Should)
+		}
+	}
+	return nil
+}
+
+// ValidateRequiredInputs checks that every required input has a value (from varCtx or its
+// Default) and, for every input that does have one, runs its declared Type's registered Validator
+// (see DefaultInputTypeRegistry) against it, plus its Schema if one is set. workflowDir resolves a
+// Schema's "$ref" the same way any other workflow-relative path is. Every input is checked even
+// once one fails, so a workflow author sees every bad input in one pass instead of fixing them one
+// at a time.
+func ValidateRequiredInputs(wf *Workflow, varCtx VarContext, workflowDir string) error {
+	var errs []error
 	for _, input := range wf.Inputs {
-		if input.Required {
-			if _, exists := varCtx[input.Name]; !exists && input.Default == "" {
-				return fmt.Errorf("required input %q is missing from the varfile and no default value is provided", input.Name)
+		value, exists := varCtx[input.Name]
+		if !exists {
+			value = input.Default
+		}
+
+		if input.Required && !exists && input.Default == "" {
+			errs = append(errs, fmt.Errorf("required input %q is missing from the varfile and no default value is provided", input.Name))
+			continue
+		}
+		if !exists && input.Default == "" {
+			continue
+		}
+
+		if err := DefaultInputTypeRegistry.Validate(value, input); err != nil {
+			errs = append(errs, fmt.Errorf("input %q: %w", input.Name, err))
+			continue
+		}
+
+		if len(input.Schema) > 0 {
+			if err := validateInputSchema(input, value, workflowDir); err != nil {
+				errs = append(errs, fmt.Errorf("input %q: %w", input.Name, err))
 			}
 		}
 	}
-	return nil
+	return errors.Join(errs...)
+}
+
+// validateInputSchema loads input.Schema (inline or a "$ref" file) and checks value, parsed as
+// JSON, against it. value isn't expected to be JSON for most built-in types (Schema is meant for
+// an input whose registered Type decodes a structured value, e.g. a custom "object"/"array" type
+// registered via RegisterInputType); a value that doesn't parse as JSON is left unchecked rather
+// than failing the input on a Schema that doesn't apply to it.
+func validateInputSchema(input Input, value, workflowDir string) error {
+	schema, err := resolveInputSchema(workflowDir, input.Schema)
+	if err != nil {
+		return fmt.Errorf("loading 'schema': %w", err)
+	}
+
+	var decoded any
+	if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+		return nil
+	}
+	return validateValueAgainstSchema(schema, decoded)
+}
+
+// ValidateStepDependencies checks that every step's DependsOn resolves to another step in wf and
+// that they don't form a cycle, without actually running anything. ExecuteWorkflow's concurrent
+// path (wf.Parallel > 1) relies on the same check, but it's run here too so `dropstep lint` catches
+// a bad depends_on before a real run ever reaches it.
+func ValidateStepDependencies(wf *Workflow) error {
+	_, _, err := buildDependencyGraph(wf.Steps)
+	return err
 }
 
 func ValidateWorkflowRunners(wf *Workflow, workflowDir string) error {
+	return validateWorkflowRunners(wf, workflowDir, map[string]bool{})
+}
+
+// validateWorkflowRunners is ValidateWorkflowRunners' recursive implementation. For a `uses:
+// workflow` step, it also loads and validates the imported workflow; visited holds the absolute
+// path of every workflow already on the current import chain, so a cycle (A imports B imports A)
+// fails with a precise error instead of recursing until the stack overflows.
+func validateWorkflowRunners(wf *Workflow, workflowDir string, visited map[string]bool) error {
 	for _, step := range wf.Steps {
+		// A steplib reference (see pkg/steplib) resolves by cloning over the network and writing
+		// to its workflow's lockfile, neither of which belongs in a lint pass that's supposed to be
+		// read-only and to work offline. Check that the reference parses and stop there; the clone
+		// itself, and the manifest it yields, are only validated by actually running the workflow.
+		if steplib.IsRef(step.Uses) {
+			if _, err := steplib.ParseRef(step.Uses); err != nil {
+				return fmt.Errorf("step %q: %w", step.ID, err)
+			}
+			continue
+		}
+
 		ctx := types.ExecutionContext{
 			Step:        step,
 			WorkflowDir: workflowDir,
 		}
 
-		runner, err := steprunner.GetRunner(ctx)
+		runner, err := steprunner.GetRunner(context.Background(), ctx)
 		if err != nil {
 			return fmt.Errorf("getting runner for step %q: %w", step.ID, err)
 		}
@@ -94,6 +284,37 @@ func ValidateWorkflowRunners(wf *Workflow, workflowDir string) error {
 		if err = runner.Validate(); err != nil {
 			return fmt.Errorf("validating step %q: %w", step.ID, err)
 		}
+
+		if step.Uses != "workflow" || step.Workflow == nil {
+			continue
+		}
+
+		childPath := step.Workflow.Path
+		if !filepath.IsAbs(childPath) {
+			childPath = filepath.Join(workflowDir, childPath)
+		}
+		childAbsPath, err := filepath.Abs(childPath)
+		if err != nil {
+			return fmt.Errorf("step %q: resolving path for imported workflow %q: %w", step.ID, step.Workflow.Path, err)
+		}
+		if visited[childAbsPath] {
+			return fmt.Errorf("step %q: imported workflow %q forms an import cycle", step.ID, childAbsPath)
+		}
+
+		child, err := LoadWorkflowFromFile(childAbsPath)
+		if err != nil {
+			return fmt.Errorf("step %q: loading imported workflow %q: %w", step.ID, childAbsPath, err)
+		}
+
+		childVisited := make(map[string]bool, len(visited)+1)
+		for path := range visited {
+			childVisited[path] = true
+		}
+		childVisited[childAbsPath] = true
+
+		if err := validateWorkflowRunners(child, filepath.Dir(childAbsPath), childVisited); err != nil {
+			return fmt.Errorf("step %q: %w", step.ID, err)
+		}
 	}
 
 	return nil
@@ -0,0 +1,466 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateWorkflowStructure_Matrix(t *testing.T) {
+	tests := []struct {
+		name        string
+		step        core.Step
+		shouldError bool
+		errorMsg    string
+	}{
+		{
+			name: "valid matrix",
+			step: core.Step{
+				ID:      "scrape",
+				Uses:    "shell",
+				Command: &core.CommandBlock{Inline: "echo {{ matrix.region }}"},
+				Matrix:  map[string][]string{"region": {"us", "eu"}},
+			},
+			shouldError: false,
+		},
+		{
+			name: "matrix value list is empty",
+			step: core.Step{
+				ID:      "scrape",
+				Uses:    "shell",
+				Command: &core.CommandBlock{Inline: "echo hi"},
+				Matrix:  map[string][]string{"region": {}},
+			},
+			shouldError: true,
+			errorMsg:    `matrix.region must list at least one value`,
+		},
+		{
+			name: "negative parallelism",
+			step: core.Step{
+				ID:          "scrape",
+				Uses:        "shell",
+				Command:     &core.CommandBlock{Inline: "echo hi"},
+				Matrix:      map[string][]string{"region": {"us"}},
+				Parallelism: -1,
+			},
+			shouldError: true,
+			errorMsg:    "parallelism must not be negative",
+		},
+		{
+			name: "parallelism without matrix",
+			step: core.Step{
+				ID:          "scrape",
+				Uses:        "shell",
+				Command:     &core.CommandBlock{Inline: "echo hi"},
+				Parallelism: 2,
+			},
+			shouldError: true,
+			errorMsg:    "only apply to a step that also defines 'matrix'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &core.Workflow{Name: "wf", Steps: []core.Step{tt.step}}
+			err := core.ValidateWorkflowStructure(wf)
+			if tt.shouldError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateWorkflowStructure_Assertions(t *testing.T) {
+	tests := []struct {
+		name        string
+		step        core.Step
+		shouldError bool
+		errorMsg    string
+	}{
+		{
+			name: "valid assertions",
+			step: core.Step{
+				ID:      "fetch",
+				Uses:    "shell",
+				Command: &core.CommandBlock{Inline: "echo hi"},
+				Assertions: []core.Assertion{
+					{Path: "result.status", Should: "ShouldEqual", Expected: 200},
+					{Path: "stdout", Should: "ShouldContain", Expected: "hi"},
+				},
+			},
+			shouldError: false,
+		},
+		{
+			name: "missing path",
+			step: core.Step{
+				ID:         "fetch",
+				Uses:       "shell",
+				Command:    &core.CommandBlock{Inline: "echo hi"},
+				Assertions: []core.Assertion{{Should: "ShouldEqual", Expected: 200}},
+			},
+			shouldError: true,
+			errorMsg:    "missing 'path'",
+		},
+		{
+			name: "invalid path",
+			step: core.Step{
+				ID:         "fetch",
+				Uses:       "shell",
+				Command:    &core.CommandBlock{Inline: "echo hi"},
+				Assertions: []core.Assertion{{Path: "status", Should: "ShouldEqual", Expected: 200}},
+			},
+			shouldError: true,
+			errorMsg:    `must be "exit_code", "stdout", "stderr", "result", or start with "result."`,
+		},
+		{
+			name: "unknown operator",
+			step: core.Step{
+				ID:         "fetch",
+				Uses:       "shell",
+				Command:    &core.CommandBlock{Inline: "echo hi"},
+				Assertions: []core.Assertion{{Path: "result.status", Should: "ShouldBeAwesome", Expected: 200}},
+			},
+			shouldError: true,
+			errorMsg:    "unknown 'should'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &core.Workflow{Name: "wf", Steps: []core.Step{tt.step}}
+			err := core.ValidateWorkflowStructure(wf)
+			if tt.shouldError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateWorkflowRunners_ImportCycle(t *testing.T) {
+	dir := t.TempDir()
+
+	aPath := filepath.Join(dir, "a.yml")
+	bPath := filepath.Join(dir, "b.yml")
+
+	aYaml := `
+name: a
+inputs: []
+steps:
+  - id: call_b
+    uses: workflow
+    workflow:
+      path: b.yml
+`
+	bYaml := `
+name: b
+inputs: []
+steps:
+  - id: call_a
+    uses: workflow
+    workflow:
+      path: a.yml
+`
+	require.NoError(t, os.WriteFile(aPath, []byte(aYaml), 0o644))
+	require.NoError(t, os.WriteFile(bPath, []byte(bYaml), 0o644))
+
+	wf, err := core.LoadWorkflowFromFile(aPath)
+	require.NoError(t, err)
+
+	err = core.ValidateWorkflowRunners(wf, dir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "import cycle")
+}
+
+func TestValidateWorkflowRunners_ImportsValidChild(t *testing.T) {
+	dir := t.TempDir()
+
+	parentPath := filepath.Join(dir, "parent.yml")
+	childPath := filepath.Join(dir, "child.yml")
+
+	parentYaml := `
+name: parent
+inputs: []
+steps:
+  - id: call_child
+    uses: workflow
+    workflow:
+      path: child.yml
+`
+	childYaml := `
+name: child
+inputs: []
+steps:
+  - id: greet
+    uses: shell
+    run:
+      inline: echo hi
+outputs:
+  greeting: "{{ steps.greet.output }}"
+`
+	require.NoError(t, os.WriteFile(parentPath, []byte(parentYaml), 0o644))
+	require.NoError(t, os.WriteFile(childPath, []byte(childYaml), 0o644))
+
+	wf, err := core.LoadWorkflowFromFile(parentPath)
+	require.NoError(t, err)
+
+	assert.NoError(t, core.ValidateWorkflowRunners(wf, dir))
+}
+
+func TestValidateWorkflowStructure_IfUnless(t *testing.T) {
+	tests := []struct {
+		name        string
+		steps       []core.Step
+		shouldError bool
+		errorMsg    string
+	}{
+		{
+			name: "valid if and unless",
+			steps: []core.Step{
+				{ID: "probe", Uses: "shell", Command: &core.CommandBlock{Inline: "echo hi"}},
+				{
+					ID:      "deploy",
+					Uses:    "shell",
+					Command: &core.CommandBlock{Inline: "echo hi"},
+					If:      `steps.probe.status == "success"`,
+					Unless:  `steps.probe.output.skip_deploy == true`,
+				},
+			},
+			shouldError: false,
+		},
+		{
+			name: "malformed if expression",
+			steps: []core.Step{
+				{ID: "deploy", Uses: "shell", Command: &core.CommandBlock{Inline: "echo hi"}, If: `steps.probe.status ==`},
+			},
+			shouldError: true,
+			errorMsg:    "invalid 'if' expression",
+		},
+		{
+			name: "unless references an unknown step",
+			steps: []core.Step{
+				{ID: "probe", Uses: "shell", Command: &core.CommandBlock{Inline: "echo hi"}},
+				{
+					ID:      "deploy",
+					Uses:    "shell",
+					Command: &core.CommandBlock{Inline: "echo hi"},
+					Unless:  `steps.typo_d.output.skip == true`,
+				},
+			},
+			shouldError: true,
+			errorMsg:    `invalid 'unless' expression: expression "steps.typo_d.output.skip == true" references unknown step "typo_d"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wf := &core.Workflow{Name: "wf", Steps: tt.steps}
+			err := core.ValidateWorkflowStructure(wf)
+			if tt.shouldError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateRequiredInputs_Types(t *testing.T) {
+	ptr := func(f float64) *float64 { return &f }
+
+	tests := []struct {
+		name        string
+		input       core.Input
+		value       string
+		shouldError bool
+		errorMsg    string
+	}{
+		{
+			name:  "valid string",
+			input: core.Input{Name: "name", Type: "string"},
+			value: "anything",
+		},
+		{
+			name:        "string violates pattern",
+			input:       core.Input{Name: "name", Type: "string", Pattern: `^[a-z]+$`},
+			value:       "NOT-LOWERCASE",
+			shouldError: true,
+			errorMsg:    `does not match pattern`,
+		},
+		{
+			name:        "string not in enum",
+			input:       core.Input{Name: "name", Type: "string", Enum: []string{"a", "b"}},
+			value:       "c",
+			shouldError: true,
+			errorMsg:    `is not one of`,
+		},
+		{
+			name:        "empty file path",
+			input:       core.Input{Name: "name", Type: "file"},
+			value:       "",
+			shouldError: true,
+			errorMsg:    "must not be empty",
+		},
+		{
+			name:  "number within bounds",
+			input: core.Input{Name: "name", Type: "number", Min: ptr(1), Max: ptr(10)},
+			value: "5",
+		},
+		{
+			name:        "number below minimum",
+			input:       core.Input{Name: "name", Type: "number", Min: ptr(1)},
+			value:       "0",
+			shouldError: true,
+			errorMsg:    "is less than the minimum",
+		},
+		{
+			name:        "number is not a number",
+			input:       core.Input{Name: "name", Type: "number"},
+			value:       "abc",
+			shouldError: true,
+			errorMsg:    "is not a valid number",
+		},
+		{
+			name:        "number is NaN",
+			input:       core.Input{Name: "name", Type: "number", Min: ptr(0), Max: ptr(100)},
+			value:       "NaN",
+			shouldError: true,
+			errorMsg:    "is not a valid number",
+		},
+		{
+			name:  "valid boolean",
+			input: core.Input{Name: "name", Type: "boolean"},
+			value: "true",
+		},
+		{
+			name:        "invalid boolean",
+			input:       core.Input{Name: "name", Type: "boolean"},
+			value:       "yes",
+			shouldError: true,
+			errorMsg:    "is not a valid boolean",
+		},
+		{
+			name:  "valid url",
+			input: core.Input{Name: "name", Type: "url"},
+			value: "https://example.com/webhook",
+		},
+		{
+			name:        "invalid url",
+			input:       core.Input{Name: "name", Type: "url"},
+			value:       "not a url",
+			shouldError: true,
+			errorMsg:    "is not a valid absolute URL",
+		},
+		{
+			name:  "valid duration",
+			input: core.Input{Name: "name", Type: "duration"},
+			value: "10m",
+		},
+		{
+			name:        "invalid duration",
+			input:       core.Input{Name: "name", Type: "duration"},
+			value:       "10 minutes",
+			shouldError: true,
+			errorMsg:    "is not a valid duration",
+		},
+		{
+			name:  "valid enum",
+			input: core.Input{Name: "name", Type: "enum", Enum: []string{"staging", "prod"}},
+			value: "prod",
+		},
+		{
+			name:        "enum value not listed",
+			input:       core.Input{Name: "name", Type: "enum", Enum: []string{"staging", "prod"}},
+			value:       "dev",
+			shouldError: true,
+			errorMsg:    "is not one of",
+		},
+		{
+			name:        "enum type with no enum list",
+			input:       core.Input{Name: "name", Type: "enum"},
+			value:       "anything",
+			shouldError: true,
+			errorMsg:    "requires a non-empty 'enum' list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.input.Required = true
+			wf := &core.Workflow{Name: "wf", Inputs: []core.Input{tt.input}}
+			varCtx := core.VarContext{tt.input.Name: tt.value}
+
+			err := core.ValidateRequiredInputs(wf, varCtx, t.TempDir())
+			if tt.shouldError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestValidateRequiredInputs_AggregatesErrors checks that every invalid input is reported, not
+// just the first one encountered.
+func TestValidateRequiredInputs_AggregatesErrors(t *testing.T) {
+	wf := &core.Workflow{
+		Name: "wf",
+		Inputs: []core.Input{
+			{Name: "count", Type: "number", Required: true},
+			{Name: "mode", Type: "enum", Enum: []string{"a", "b"}, Required: true},
+		},
+	}
+	varCtx := core.VarContext{"count": "not-a-number", "mode": "c"}
+
+	err := core.ValidateRequiredInputs(wf, varCtx, t.TempDir())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `input "count"`)
+	assert.Contains(t, err.Error(), `input "mode"`)
+}
+
+// TestValidateRequiredInputs_Schema checks an input whose Schema is set (here inline, since a
+// custom registered type would be needed to actually hit it through a built-in Validator) is
+// checked against it once its value parses as JSON.
+func TestValidateRequiredInputs_Schema(t *testing.T) {
+	core.RegisterInputType("json_object", func(value any, spec core.InputSpec) error {
+		return nil
+	})
+
+	wf := &core.Workflow{
+		Name: "wf",
+		Inputs: []core.Input{
+			{
+				Name:     "payload",
+				Type:     "json_object",
+				Required: true,
+				Schema: map[string]any{
+					"type":     "object",
+					"required": []any{"id"},
+				},
+			},
+		},
+	}
+
+	t.Run("missing required property", func(t *testing.T) {
+		varCtx := core.VarContext{"payload": `{"name":"no id here"}`}
+		err := core.ValidateRequiredInputs(wf, varCtx, t.TempDir())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `missing required property "id"`)
+	})
+
+	t.Run("satisfies schema", func(t *testing.T) {
+		varCtx := core.VarContext{"payload": `{"id":"123"}`}
+		assert.NoError(t, core.ValidateRequiredInputs(wf, varCtx, t.TempDir()))
+	})
+}
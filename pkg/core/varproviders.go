@@ -0,0 +1,253 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// VarProvider resolves a "{{ name(arg) }}" varfile placeholder's parenthesized argument text into
+// a value, e.g. "{{ file("./path") }}" or "{{ exec("echo", "hi") }}". Built-in providers are
+// registered below; RegisterVarProvider lets an embedder add more, or override a stub (aws-sm,
+// gcp-sm, vault below) with a real implementation.
+type VarProvider interface {
+	// Name is the identifier that precedes "(" in a placeholder, e.g. "file".
+	Name() string
+	// Resolve receives arg exactly as written between the placeholder's parentheses, unparsed, so
+	// each provider interprets its own argument syntax (a single quoted path, a comma-separated
+	// argv list, etc.) rather than a shared one the registry would have to impose on all of them.
+	Resolve(arg string) (string, error)
+}
+
+var (
+	varProvidersMu sync.RWMutex
+	varProviders   = map[string]VarProvider{}
+)
+
+// RegisterVarProvider makes p available to varfile placeholders of the form "{{ <p.Name()>(...)
+// }}" (see ResolveVarfile). Re-registering a name replaces the previous provider. Callers outside
+// this package typically call this from an init() func, the same convention
+// RegisterTemplateFilter and steprunner.RegisterRunnerFactory use.
+func RegisterVarProvider(p VarProvider) {
+	varProvidersMu.Lock()
+	defer varProvidersMu.Unlock()
+	varProviders[p.Name()] = p
+}
+
+func lookupVarProvider(name string) (VarProvider, bool) {
+	varProvidersMu.RLock()
+	defer varProvidersMu.RUnlock()
+	p, ok := varProviders[name]
+	return p, ok
+}
+
+func init() {
+	RegisterVarProvider(envVarProvider{})
+	RegisterVarProvider(shellVarProvider{})
+	RegisterVarProvider(fileVarProvider{})
+	RegisterVarProvider(execVarProvider{})
+	RegisterVarProvider(awsSMVarProvider{})
+	RegisterVarProvider(gcpSMVarProvider{})
+	RegisterVarProvider(vaultVarProvider{})
+}
+
+// varProviderCallRe matches a whole varfile value of the form "{{ name(arg) }}". The provider
+// name may contain hyphens (e.g. "aws-sm"); the text between the parens is handed to the provider
+// unparsed, so it may itself contain commas, quotes, or nested parens.
+var varProviderCallRe = regexp.MustCompile(`^\s*\{\{\s*([a-zA-Z0-9_-]+)\((.*)\)\s*}}\s*$`)
+
+// resolveVarProviderCall resolves val if it matches the "{{ name(arg) }}" call syntax, returning
+// matched=false for anything else (a plain value, the legacy "{{ env.X }}" form, or a
+// "{{ secret.* }}" placeholder) so the caller can fall through to its other cases. providerName is
+// returned alongside so the caller can decide whether the resolved value should be masked (see
+// isSecretVarProviderName).
+func resolveVarProviderCall(val string) (resolved string, providerName string, matched bool, err error) {
+	match := varProviderCallRe.FindStringSubmatch(val)
+	if match == nil {
+		return "", "", false, nil
+	}
+	name, arg := match[1], match[2]
+	provider, ok := lookupVarProvider(name)
+	if !ok {
+		return "", name, true, fmt.Errorf("unknown variable provider %q", name)
+	}
+	resolved, err = provider.Resolve(arg)
+	if err != nil {
+		return "", name, true, fmt.Errorf("provider %q: %w", name, err)
+	}
+	return resolved, name, true, nil
+}
+
+// secretVarProviderNames tracks which VarProvider names' resolved values should be masked from
+// logs the same way a "{{ secret.* }}" value already is, beyond the automatic "secret-" prefix
+// convention. Empty by default.
+var (
+	secretVarProviderNamesMu sync.RWMutex
+	secretVarProviderNames   = map[string]bool{}
+)
+
+// RegisterSecretVarProviderName marks name's resolved varfile values as secrets, so
+// ResolveVarfile's maskFunc callback receives them even though the provider's own name doesn't
+// start with "secret-".
+func RegisterSecretVarProviderName(name string) {
+	secretVarProviderNamesMu.Lock()
+	defer secretVarProviderNamesMu.Unlock()
+	secretVarProviderNames[name] = true
+}
+
+// isSecretVarProviderName reports whether a VarProvider named name resolves values that should
+// flow into the run's secret-redaction pipeline.
+func isSecretVarProviderName(name string) bool {
+	if strings.HasPrefix(name, "secret-") {
+		return true
+	}
+	secretVarProviderNamesMu.RLock()
+	defer secretVarProviderNamesMu.RUnlock()
+	return secretVarProviderNames[name]
+}
+
+// envVarProvider resolves "{{ env("NAME") }}" from the process environment. Unlike the legacy
+// "{{ env.NAME }}" form ResolveVarfile still handles directly, a missing variable is an error here
+// rather than an empty string.
+type envVarProvider struct{}
+
+func (envVarProvider) Name() string { return "env" }
+
+func (envVarProvider) Resolve(arg string) (string, error) {
+	name := unquoteVarProviderArg(arg)
+	val, exists := os.LookupEnv(name)
+	if !exists {
+		return "", fmt.Errorf("environment variable %q not found", name)
+	}
+	return val, nil
+}
+
+// shellVarProvider resolves "{{ shell("cmd arg1 arg2") }}" by running its argument through the
+// user's shell ("sh -c"), so pipes/redirection/globbing work as they would at a shell prompt.
+// Prefer execVarProvider when the command and its arguments are already known: it runs argv
+// directly, with no shell interpolation.
+type shellVarProvider struct{}
+
+func (shellVarProvider) Name() string { return "shell" }
+
+func (shellVarProvider) Resolve(arg string) (string, error) {
+	cmdStr := unquoteVarProviderArg(arg)
+	out, err := exec.Command("sh", "-c", cmdStr).Output()
+	if err != nil {
+		return "", fmt.Errorf("running shell command %q: %w", cmdStr, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// fileVarProvider resolves "{{ file("./path") }}" to the named file's trimmed contents.
+type fileVarProvider struct{}
+
+func (fileVarProvider) Name() string { return "file" }
+
+func (fileVarProvider) Resolve(arg string) (string, error) {
+	path := unquoteVarProviderArg(arg)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading file %q: %w", path, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// execVarProvider resolves "{{ exec("cmd", "arg1", "arg2") }}" by running cmd with its arguments
+// passed directly as argv — no shell is invoked, so there's no shell-interpolation risk (G204) the
+// way shellVarProvider's "sh -c" carries.
+type execVarProvider struct{}
+
+func (execVarProvider) Name() string { return "exec" }
+
+func (execVarProvider) Resolve(arg string) (string, error) {
+	argv, err := splitVarProviderArgs(arg)
+	if err != nil {
+		return "", fmt.Errorf("parsing arguments: %w", err)
+	}
+	if len(argv) == 0 {
+		return "", fmt.Errorf("expects at least a command")
+	}
+	out, err := exec.Command(argv[0], argv[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("running %q: %w", strings.Join(argv, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// awsSMVarProvider, gcpSMVarProvider, and vaultVarProvider are intentionally unimplemented: a real
+// cloud-secret provider already exists per-workflow via the "secrets:" block and
+// "{{ secret.<provider>.<ref> }}" syntax (see pkg/secrets), resolved against the run's own
+// credentials rather than whatever resolved the varfile. These three exist as named placeholders
+// in the varfile provider namespace so "{{ aws-sm(\"arn:...\") }}" fails with a clear, actionable
+// error instead of "unknown variable provider", and so a future varfile-level implementation can
+// register over them (RegisterVarProvider) without a varfile syntax change.
+type awsSMVarProvider struct{}
+
+func (awsSMVarProvider) Name() string { return "aws-sm" }
+
+func (awsSMVarProvider) Resolve(string) (string, error) {
+	return "", fmt.Errorf("not yet implemented; use a 'secrets:' block with an aws-secretsmanager provider and {{ secret.<name>.<ref> }} instead")
+}
+
+type gcpSMVarProvider struct{}
+
+func (gcpSMVarProvider) Name() string { return "gcp-sm" }
+
+func (gcpSMVarProvider) Resolve(string) (string, error) {
+	return "", fmt.Errorf("not yet implemented; use a 'secrets:' block with a gcp-secretmanager provider and {{ secret.<name>.<ref> }} instead")
+}
+
+type vaultVarProvider struct{}
+
+func (vaultVarProvider) Name() string { return "vault" }
+
+func (vaultVarProvider) Resolve(string) (string, error) {
+	return "", fmt.Errorf("not yet implemented; use a 'secrets:' block with a vault provider and {{ secret.<name>.<ref> }} instead")
+}
+
+// unquoteVarProviderArg strips one layer of surrounding double quotes and whitespace from a
+// provider's raw argument text, so both "{{ file("./path") }}" and an unquoted "{{ env(NAME) }}"
+// resolve the same way.
+func unquoteVarProviderArg(arg string) string {
+	arg = strings.TrimSpace(arg)
+	if len(arg) >= 2 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+		return arg[1 : len(arg)-1]
+	}
+	return arg
+}
+
+// splitVarProviderArgs splits a provider's raw argument text on top-level commas, honoring quoted
+// strings (so a comma inside one doesn't split) and "\\\"" escapes, for a provider like exec that
+// takes an argv list rather than a single value.
+func splitVarProviderArgs(arg string) ([]string, error) {
+	var args []string
+	var cur strings.Builder
+	inQuote := false
+	n := len(arg)
+	for i := 0; i < n; i++ {
+		c := arg[i]
+		switch {
+		case c == '\\' && inQuote && i+1 < n:
+			cur.WriteByte(arg[i+1])
+			i++
+		case c == '"':
+			inQuote = !inQuote
+		case c == ',' && !inQuote:
+			args = append(args, strings.TrimSpace(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	if inQuote {
+		return nil, fmt.Errorf("unterminated quoted argument")
+	}
+	if trailing := strings.TrimSpace(cur.String()); trailing != "" || len(args) > 0 {
+		args = append(args, trailing)
+	}
+	return args, nil
+}
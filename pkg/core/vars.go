@@ -2,6 +2,7 @@ package core
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -11,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/arnavsurve/dropstep/pkg/secrets"
 	"gopkg.in/yaml.v3"
 )
 
@@ -20,8 +22,54 @@ type VarContext map[string]string
 // varRegex is a package-level compiled regular expression for matching {{ varName }} placeholders.
 var varRegex = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9\._-]+)\s*\}\}`)
 
-// ResolveVarfile loads a YAML varfile (e.g. dsvars.yml), parses it, and resolves special values.
-func ResolveVarfile(path string) (VarContext, error) {
+// templateKeyRe matches just the leading variable reference of a "{{ ... }}" placeholder,
+// whether or not it's followed by a "| filter ..." pipe chain, so checkInputScope can still catch
+// a scoped input used inside one (varRegex itself only matches a placeholder with nothing else in
+// it).
+var templateKeyRe = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9\._-]+)`)
+
+// secretRe matches a whole varfile value of the form "{{ secret.<provider>.<ref> }}", e.g.
+// "{{ secret.vault.kv/data/prod/openai#api_key }}". Unlike varRegex, the ref half is unrestricted
+// since provider refs (Vault paths, AWS secret names) commonly contain '/' and '#'.
+var secretRe = regexp.MustCompile(`^\s*\{\{\s*secret\.([a-zA-Z0-9_-]+)\.(.+?)\s*}}\s*$`)
+
+// secretPathRe matches a "{{ secretPath "name" }}" placeholder in a step's browser.prompt,
+// referencing a SecretMount by Name rather than an input/env var.
+var secretPathRe = regexp.MustCompile(`\{\{\s*secretPath\s+"([^"]+)"\s*}}`)
+
+// resolveSecretPaths substitutes each "{{ secretPath "name" }}" placeholder in step's
+// browser.prompt with the file path that name was staged to by pkg/secretmount, so a
+// browser_agent step can be pointed at a secret's location without the value ever appearing in
+// the prompt text itself.
+func resolveSecretPaths(step *Step, paths map[string]string) error {
+	if step.BrowserConfig.Prompt == "" {
+		return nil
+	}
+	var firstErr error
+	step.BrowserConfig.Prompt = secretPathRe.ReplaceAllStringFunc(step.BrowserConfig.Prompt, func(match string) string {
+		if firstErr != nil {
+			return match
+		}
+		name := secretPathRe.FindStringSubmatch(match)[1]
+		path, ok := paths[name]
+		if !ok {
+			firstErr = fmt.Errorf("secretPath %q does not match any secret_mounts entry", name)
+			return match
+		}
+		return path
+	})
+	return firstErr
+}
+
+// ResolveVarfile loads a YAML varfile (e.g. dsvars.yml), parses it, and resolves special values:
+// "{{ env.NAME }}" against the process environment, "{{ secret.<provider>.<ref> }}" against
+// registry (see pkg/secrets), and "{{ name(arg) }}" against any registered VarProvider (env,
+// shell, file, exec, and the aws-sm/gcp-sm/vault stubs; see varproviders.go). registry may be nil
+// if the workflow defines no `secrets:` block; a varfile referencing a secret in that case is an
+// error. maskFunc, if non-nil, is called with every value resolved via "{{ secret.* }}" or a
+// VarProvider whose name isSecretVarProviderName, so it flows into the run's log redaction the
+// same way a `secrets:` block's own resolved values already do.
+func ResolveVarfile(ctx context.Context, path string, registry *secrets.Registry, maskFunc func(string)) (VarContext, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, fmt.Errorf("reading varfile %q: %w", path, err)
@@ -36,7 +84,8 @@ func ResolveVarfile(path string) (VarContext, error) {
 
 	resolvedCtx := make(VarContext, len(rawVars))
 	for key, val := range rawVars {
-		if envRe.MatchString(val) {
+		switch {
+		case envRe.MatchString(val):
 			match := envRe.FindStringSubmatch(val)
 			envKey := match[1]
 			envVal, exists := os.LookupEnv(envKey)
@@ -44,7 +93,31 @@ func ResolveVarfile(path string) (VarContext, error) {
 				log.Printf("warning: environment variable %q not found for varfile key %q", envKey, key)
 			}
 			resolvedCtx[key] = envVal
-		} else {
+		case secretRe.MatchString(val):
+			match := secretRe.FindStringSubmatch(val)
+			providerName, ref := match[1], match[2]
+			if registry == nil {
+				return nil, fmt.Errorf("varfile key %q references secret provider %q, but the workflow defines no 'secrets:' block", key, providerName)
+			}
+			secretVal, err := registry.Resolve(ctx, providerName, ref)
+			if err != nil {
+				return nil, fmt.Errorf("resolving secret for varfile key %q: %w", key, err)
+			}
+			if maskFunc != nil {
+				maskFunc(secretVal)
+			}
+			resolvedCtx[key] = secretVal
+		default:
+			if resolved, providerName, matched, err := resolveVarProviderCall(val); matched {
+				if err != nil {
+					return nil, fmt.Errorf("resolving varfile key %q: %w", key, err)
+				}
+				if maskFunc != nil && isSecretVarProviderName(providerName) {
+					maskFunc(resolved)
+				}
+				resolvedCtx[key] = resolved
+				continue
+			}
 			resolvedCtx[key] = val
 		}
 	}
@@ -84,7 +157,9 @@ func ResolveValue(value any, resolver func(string) (string, error), globals VarC
 
 // ResolveStepVariables takes a single step and resolves all its templated
 // fields using the global context and the results of previously executed steps.
-func ResolveStepVariables(step *Step, globals VarContext, results StepResultsContext) (*Step, error) {
+// inputs, if given, is the workflow's input declarations; any input with a non-empty
+// AllowedSteps/AllowedUses/AllowedProviders is checked against step before resolution proceeds.
+func ResolveStepVariables(step *Step, globals VarContext, results StepResultsContext, inputs ...Input) (*Step, error) {
 	// Create a deep copy of the step to avoid modifying the original workflow definition.
 	var resolvedStep Step
 	b, _ := yaml.Marshal(step)
@@ -92,6 +167,10 @@ func ResolveStepVariables(step *Step, globals VarContext, results StepResultsCon
 		return nil, fmt.Errorf("deep copying step for resolution: %w", err)
 	}
 
+	if err := checkInputScope(step, b, inputs); err != nil {
+		return nil, err
+	}
+
 	resolutionCtx := make(VarContext)
 	for k, v := range globals {
 		resolutionCtx[k] = v
@@ -175,6 +254,47 @@ func ResolveStepVariables(step *Step, globals VarContext, results StepResultsCon
 				return nil, fmt.Errorf("resolved call.body for step %q is not a map, got %T", step.ID, resolvedBody)
 			}
 		}
+
+		resolvedStep.Call.RawBody, err = coreResolver(resolvedStep.Call.RawBody)
+		if err != nil {
+			return nil, fmt.Errorf("resolving call.raw_body for step %q: %w", step.ID, err)
+		}
+
+		if resolvedStep.Call.Files != nil {
+			resolvedFiles := make(map[string]string, len(resolvedStep.Call.Files))
+			for k, v := range resolvedStep.Call.Files {
+				resolvedV, errFile := coreResolver(v)
+				if errFile != nil {
+					return nil, fmt.Errorf("resolving call.files[%s] for step %q: %w", k, step.ID, errFile)
+				}
+				resolvedFiles[k] = resolvedV
+			}
+			resolvedStep.Call.Files = resolvedFiles
+		}
+
+		resolvedStep.Call.SaveTo, err = coreResolver(resolvedStep.Call.SaveTo)
+		if err != nil {
+			return nil, fmt.Errorf("resolving call.save_to for step %q: %w", step.ID, err)
+		}
+	}
+
+	if resolvedStep.Workflow != nil {
+		resolvedStep.Workflow.Path, err = coreResolver(resolvedStep.Workflow.Path)
+		if err != nil {
+			return nil, fmt.Errorf("resolving workflow.path for step %q: %w", step.ID, err)
+		}
+
+		if resolvedStep.Workflow.With != nil {
+			resolvedWith, errWith := ResolveValue(resolvedStep.Workflow.With, coreResolver, resolutionCtx, results)
+			if errWith != nil {
+				return nil, fmt.Errorf("resolving workflow.with for step %q: %w", step.ID, errWith)
+			}
+			castedWith, ok := resolvedWith.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("resolved workflow.with for step %q is not a map, got %T", step.ID, resolvedWith)
+			}
+			resolvedStep.Workflow.With = castedWith
+		}
 	}
 
 	for i := range resolvedStep.BrowserConfig.AllowedDomains {
@@ -205,6 +325,13 @@ func ResolveStepVariables(step *Step, globals VarContext, results StepResultsCon
 		}
 	}
 
+	for i := range resolvedStep.Artifacts {
+		resolvedStep.Artifacts[i].Path, err = coreResolver(resolvedStep.Artifacts[i].Path)
+		if err != nil {
+			return nil, fmt.Errorf("resolving artifacts[%d].path for step %q: %w", i, step.ID, err)
+		}
+	}
+
 	if resolvedStep.MaxFailures != nil {
 		maxFailuresStr, err := coreResolver(strconv.Itoa(*resolvedStep.MaxFailures))
 		if err != nil {
@@ -220,28 +347,71 @@ func ResolveStepVariables(step *Step, globals VarContext, results StepResultsCon
 	return &resolvedStep, nil
 }
 
-// ResolveStringWithContext is the core template resolution engine.
-func ResolveStringWithContext(input string, globals VarContext, results StepResultsContext) (string, error) {
-	var firstErr error
-	output := varRegex.ReplaceAllStringFunc(input, func(match string) string {
-		if firstErr != nil {
-			return match // Stop processing if an error has occurred
+// ResolveWorkflowOutputs resolves wf's top-level `outputs:` block, each value a template (see
+// ResolveStringWithContext) evaluated against globals and the completed run's StepResultsContext,
+// into a plain map — e.g. for a `uses: workflow` step to expose as {{ steps.<id>.output.<name> }}
+// in its parent. Returns a non-nil, empty map for a workflow that declares no outputs.
+func ResolveWorkflowOutputs(wf *Workflow, globals VarContext, results StepResultsContext) (map[string]any, error) {
+	resolved := make(map[string]any, len(wf.Outputs))
+	for name, tmpl := range wf.Outputs {
+		val, err := ResolveStringWithContext(tmpl, globals, results)
+		if err != nil {
+			return nil, fmt.Errorf("output %q: %w", name, err)
 		}
+		resolved[name] = val
+	}
+	return resolved, nil
+}
 
-		key := varRegex.FindStringSubmatch(match)[1]
-		val, found := FindValueInContext(key, globals, results)
+// checkInputScope scans rawStep (the step's config, marshaled back to YAML so every field is
+// covered in one pass) for references to any input in inputs that declares an allow-list, and
+// fails if step isn't permitted to consume it.
+func checkInputScope(step *Step, rawStep []byte, inputs []Input) error {
+	scoped := make(map[string]Input)
+	for _, input := range inputs {
+		if len(input.AllowedSteps) > 0 || len(input.AllowedUses) > 0 || len(input.AllowedProviders) > 0 {
+			scoped[input.Name] = input
+		}
+	}
+	if len(scoped) == 0 {
+		return nil
+	}
 
-		if !found {
-			firstErr = fmt.Errorf("undefined variable: %s", key)
-			return match
+	for _, match := range templateKeyRe.FindAllStringSubmatch(string(rawStep), -1) {
+		input, ok := scoped[match[1]]
+		if !ok {
+			continue
 		}
-		return fmt.Sprintf("%v", val)
-	})
+		if len(input.AllowedSteps) > 0 && !containsString(input.AllowedSteps, step.ID) {
+			return fmt.Errorf("secret %q is not allowed to be used by step %q (uses=%s)", input.Name, step.ID, step.Uses)
+		}
+		if len(input.AllowedUses) > 0 && !containsString(input.AllowedUses, step.Uses) {
+			return fmt.Errorf("secret %q is not allowed to be used by step %q (uses=%s)", input.Name, step.ID, step.Uses)
+		}
+		if len(input.AllowedProviders) > 0 && !containsString(input.AllowedProviders, step.Provider) {
+			return fmt.Errorf("secret %q is not allowed to be used by step %q (uses=%s)", input.Name, step.ID, step.Uses)
+		}
+	}
+	return nil
+}
 
-	if firstErr != nil {
-		return "", firstErr
+func containsString(list []string, val string) bool {
+	for _, v := range list {
+		if v == val {
+			return true
+		}
 	}
-	return output, nil
+	return false
+}
+
+// ResolveStringWithContext is the core template resolution engine. Each "{{ ... }}" placeholder's
+// body is a variable reference (exactly as before) optionally followed by one or more
+// "| filter arg..." pipe stages evaluated left to right against the registry in filters.go; see
+// evalTemplateExpr and RegisterTemplateFilter.
+func ResolveStringWithContext(input string, globals VarContext, results StepResultsContext) (string, error) {
+	return scanTemplateExprs(input, func(body string) (string, error) {
+		return evalTemplateExpr(body, globals, results)
+	})
 }
 
 // FindValueInContext orchestrates the lookup for a variable.
@@ -256,7 +426,7 @@ func FindValueInContext(key string, globals VarContext, results StepResultsConte
 
 	// Try to resolve as a `steps` variable
 	if strings.HasPrefix(key, "steps.") {
-		parts := strings.Split(key, ".")
+		parts := splitKeyPath(key)
 		if len(parts) < 3 { // Must be at least `steps.id.field`
 			return nil, false
 		}
@@ -271,6 +441,18 @@ func FindValueInContext(key string, globals VarContext, results StepResultsConte
 				if len(parts) == 3 {
 					value, found = result.OutputFile, true
 				}
+			case "outputs":
+				if len(parts) == 4 {
+					value, found = result.Outputs[parts[3]]
+				}
+			case "artifacts":
+				if len(parts) == 5 && parts[4] == "path" {
+					var artifact ArtifactResult
+					artifact, found = result.Artifacts[parts[3]]
+					if found {
+						value = artifact.Path
+					}
+				}
 			}
 		}
 	} else {
@@ -294,37 +476,6 @@ func FindValueInContext(key string, globals VarContext, results StepResultsConte
 	return value, true
 }
 
-// GetNestedValue traverses a data structure (map or string) using a path slice.
-func GetNestedValue(data any, path []string) (any, bool) {
-	if len(path) == 0 {
-		return data, true
-	}
-	if data == nil {
-		return nil, false
-	}
-
-	current := data
-	for _, keyInPath := range path {
-		switch typedCurrent := current.(type) {
-		case map[string]any:
-			if val, exists := typedCurrent[keyInPath]; exists {
-				current = val
-			} else {
-				return nil, false
-			}
-		case map[string]string:
-			if val, exists := typedCurrent[keyInPath]; exists {
-				current = val
-			} else {
-				return nil, false
-			}
-		default:
-			return nil, false
-		}
-	}
-	return current, true
-}
-
 // InjectVarsIntoWorkflow is kept for the linter, but it only resolves global variables.
 func InjectVarsIntoWorkflow(wf *Workflow, globalVarCtx VarContext) (*Workflow, error) {
 	if wf == nil {
@@ -375,7 +526,25 @@ func InjectVarsIntoWorkflow(wf *Workflow, globalVarCtx VarContext) (*Workflow, e
 	return &updatedWf, nil
 }
 
-func ResolveProviderVariables(p *ProviderConfig, globals VarContext) (*ProviderConfig, error) {
+// ResolveProviderVariables resolves p's templated fields (currently just api_key) against
+// globals. inputs, if given, is the workflow's input declarations; an input whose AllowedSteps
+// or AllowedUses is non-empty cannot be consumed by a provider at all (providers aren't tied to
+// one step), and a non-empty AllowedProviders must include p.Name.
+func ResolveProviderVariables(p *ProviderConfig, globals VarContext, inputs ...Input) (*ProviderConfig, error) {
+	for _, match := range varRegex.FindAllStringSubmatch(p.APIKey, -1) {
+		for _, input := range inputs {
+			if input.Name != match[1] {
+				continue
+			}
+			if len(input.AllowedSteps) > 0 || len(input.AllowedUses) > 0 {
+				return nil, fmt.Errorf("secret %q is not allowed to be used by provider %q: it is scoped to specific steps", input.Name, p.Name)
+			}
+			if len(input.AllowedProviders) > 0 && !containsString(input.AllowedProviders, p.Name) {
+				return nil, fmt.Errorf("secret %q is not allowed to be used by provider %q", input.Name, p.Name)
+			}
+		}
+	}
+
 	// Create a deep copy to avoid modifying the original
 	var resolvedProvider ProviderConfig
 	b, _ := yaml.Marshal(p)
@@ -1,11 +1,13 @@
 package core_test
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/arnavsurve/dropstep/pkg/core"
+	"github.com/arnavsurve/dropstep/pkg/types"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -28,7 +30,7 @@ empty_env_var: "{{ env.NONEXISTENT_VAR }}"
 	require.NoError(t, os.WriteFile(varfilePath, []byte(varfileContent), 0644))
 
 	// Test resolving the varfile
-	vars, err := core.ResolveVarfile(varfilePath)
+	vars, err := core.ResolveVarfile(context.Background(), varfilePath, nil, nil)
 	require.NoError(t, err)
 
 	// Verify resolved values
@@ -37,18 +39,28 @@ empty_env_var: "{{ env.NONEXISTENT_VAR }}"
 	assert.Equal(t, "", vars["empty_env_var"])
 
 	// Test error cases
-	_, err = core.ResolveVarfile("nonexistent_file.yml")
+	_, err = core.ResolveVarfile(context.Background(), "nonexistent_file.yml", nil, nil)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "reading varfile")
 
 	// Test invalid YAML
 	invalidPath := filepath.Join(tempDir, "invalid.yml")
 	require.NoError(t, os.WriteFile(invalidPath, []byte("invalid: yaml: ]:"), 0644))
-	_, err = core.ResolveVarfile(invalidPath)
+	_, err = core.ResolveVarfile(context.Background(), invalidPath, nil, nil)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "parsing varfile YAML")
 }
 
+func TestResolveVarfile_SecretWithoutRegistry(t *testing.T) {
+	tempDir := t.TempDir()
+	varfilePath := filepath.Join(tempDir, "test_vars.yml")
+	require.NoError(t, os.WriteFile(varfilePath, []byte(`api_key: "{{ secret.vault.kv/data/prod/openai#api_key }}"`), 0644))
+
+	_, err := core.ResolveVarfile(context.Background(), varfilePath, nil, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "no 'secrets:' block")
+}
+
 func TestFindValueInContext(t *testing.T) {
 	globals := core.VarContext{"url": "https://example.com"}
 	results := core.StepResultsContext{
@@ -118,6 +130,45 @@ func TestResolveStepVariables(t *testing.T) {
 	assert.Equal(t, "cat /data/prev_output.txt", resolved.Command.Inline)
 }
 
+func TestResolveStepVariables_InputScoping(t *testing.T) {
+	globals := core.VarContext{"password": "hunter2"}
+	inputs := []core.Input{
+		{Name: "password", Secret: true, AllowedSteps: []string{"publish"}, AllowedUses: []string{"shell"}},
+	}
+
+	allowedStep := &core.Step{
+		ID:   "publish",
+		Uses: "shell",
+		Command: &core.CommandBlock{
+			Inline: "deploy --password {{ password }}",
+		},
+	}
+	_, err := core.ResolveStepVariables(allowedStep, globals, nil, inputs...)
+	require.NoError(t, err)
+
+	disallowedStep := &core.Step{
+		ID:   "publish",
+		Uses: "http",
+		Command: &core.CommandBlock{
+			Inline: "{{ password }}",
+		},
+	}
+	_, err = core.ResolveStepVariables(disallowedStep, globals, nil, inputs...)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `secret "password" is not allowed to be used by step "publish" (uses=http)`)
+
+	otherStep := &core.Step{
+		ID:   "other",
+		Uses: "shell",
+		Command: &core.CommandBlock{
+			Inline: "{{ password }}",
+		},
+	}
+	_, err = core.ResolveStepVariables(otherStep, globals, nil, inputs...)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `secret "password" is not allowed to be used by step "other"`)
+}
+
 func TestResolveStringWithContext_UndefinedVar(t *testing.T) {
 	input := "Hello {{ undefined_var }}"
 	_, err := core.ResolveStringWithContext(input, nil, nil)
@@ -179,6 +230,86 @@ func TestResolveStringWithContext_Json(t *testing.T) {
 	}
 }
 
+func TestResolveStringWithContext_PipeFilters(t *testing.T) {
+	globals := core.VarContext{
+		"name":  "  Ada Lovelace  ",
+		"empty": "",
+		"csv":   "a,b,c",
+	}
+	results := core.StepResultsContext{
+		"fetch": {
+			Output: map[string]any{"status": float64(200), "token": "eyJabc"},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"single filter", "{{ name | trim }}", "Ada Lovelace"},
+		{"chained filters", "{{ name | trim | upper }}", "ADA LOVELACE"},
+		{"default on empty", "{{ empty | default \"fallback\" }}", "fallback"},
+		{"default leaves non-empty alone", "{{ name | trim | default \"fallback\" }}", "Ada Lovelace"},
+		{"arg with two values", "{{ csv | replace \",\" \";\" }}", "a;b;c"},
+		{"split then join", "{{ csv | split \",\" | join \"-\" }}", "a-b-c"},
+		{"steps value through a filter", "{{ steps.fetch.output.token | upper }}", "EYJABC"},
+		{"nested expr argument", "{{ empty | default (name | trim) }}", "Ada Lovelace"},
+		{"b64 round trip", "{{ name | trim | b64enc | b64dec }}", "Ada Lovelace"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := core.ResolveStringWithContext(tc.input, globals, results)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestResolveStringWithContext_PipeFilters_Errors(t *testing.T) {
+	_, err := core.ResolveStringWithContext("{{ undefined | upper }}", nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "undefined variable: undefined")
+
+	_, err = core.ResolveStringWithContext("{{ name | notareel }}", core.VarContext{"name": "x"}, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown template filter "notareel"`)
+}
+
+func TestResolveStringWithContext_StepArrayPaths(t *testing.T) {
+	results := core.StepResultsContext{
+		"fetch": {
+			Output: map[string]any{
+				"items": []any{
+					map[string]any{"id": "a", "status": "ok"},
+					map[string]any{"id": "b", "status": "fail"},
+					map[string]any{"id": "c", "status": "ok"},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"dotted numeric index", "{{ steps.fetch.output.items.0.id }}", "a"},
+		{"bracket index", "{{ steps.fetch.output.items[0].id }}", "a"},
+		{"wildcard as json array", "{{ steps.fetch.output.items[*].id.json }}", `["a","b","c"]`},
+		{"filter predicate as json array", `{{ steps.fetch.output.items[?(@.status=="ok")].id.json }}`, `["a","c"]`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := core.ResolveStringWithContext(tc.input, nil, results)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
 func TestGetNestedValue(t *testing.T) {
 	testData := map[string]interface{}{
 		"a": map[string]interface{}{
@@ -263,6 +394,52 @@ func TestGetNestedValue(t *testing.T) {
 			expected: nil,
 			found:    false,
 		},
+		{
+			name: "Numeric index into array",
+			data: map[string]interface{}{
+				"items": []any{
+					map[string]any{"id": "a"},
+					map[string]any{"id": "b"},
+				},
+			},
+			path:     []string{"items", "0", "id"},
+			expected: "a",
+			found:    true,
+		},
+		{
+			name: "Out of range index",
+			data: map[string]interface{}{
+				"items": []any{map[string]any{"id": "a"}},
+			},
+			path:     []string{"items", "5", "id"},
+			expected: nil,
+			found:    false,
+		},
+		{
+			name: "Wildcard collects across every element",
+			data: map[string]interface{}{
+				"items": []any{
+					map[string]any{"id": "a"},
+					map[string]any{"id": "b"},
+				},
+			},
+			path:     []string{"items", "*", "id"},
+			expected: []any{"a", "b"},
+			found:    true,
+		},
+		{
+			name: "Filter predicate selects matching elements",
+			data: map[string]interface{}{
+				"items": []any{
+					map[string]any{"id": "a", "status": "ok"},
+					map[string]any{"id": "b", "status": "fail"},
+					map[string]any{"id": "c", "status": "ok"},
+				},
+			},
+			path:     []string{"items", `?(@.status=="ok")`, "id"},
+			expected: []any{"a", "c"},
+			found:    true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -446,3 +623,97 @@ func TestResolveStepVariables_HttpCall(t *testing.T) {
 	assert.Equal(t, "456", resolved.Call.Body["userId"])
 	assert.Equal(t, "update", resolved.Call.Body["action"])
 }
+
+func TestResolveWorkflowOutputs(t *testing.T) {
+	wf := &core.Workflow{
+		Name: "wf",
+		Outputs: map[string]string{
+			"greeting": "hello {{ name }}",
+			"token":    "{{ steps.auth.output.token }}",
+		},
+	}
+	globals := core.VarContext{"name": "world"}
+	results := core.StepResultsContext{
+		"auth": types.StepResult{Output: map[string]any{"token": "abc123"}},
+	}
+
+	outputs, err := core.ResolveWorkflowOutputs(wf, globals, results)
+	require.NoError(t, err)
+
+	assert.Equal(t, "hello world", outputs["greeting"])
+	assert.Equal(t, "abc123", outputs["token"])
+}
+
+func TestResolveWorkflowOutputs_Empty(t *testing.T) {
+	wf := &core.Workflow{Name: "wf"}
+
+	outputs, err := core.ResolveWorkflowOutputs(wf, core.VarContext{}, core.StepResultsContext{})
+	require.NoError(t, err)
+	assert.NotNil(t, outputs)
+	assert.Empty(t, outputs)
+}
+
+func TestResolveVarfile_VarProviders(t *testing.T) {
+	tempDir := t.TempDir()
+	varfilePath := filepath.Join(tempDir, "test_vars.yml")
+
+	dataFile := filepath.Join(tempDir, "token.txt")
+	require.NoError(t, os.WriteFile(dataFile, []byte("file-token\n"), 0644))
+
+	t.Setenv("TEST_PROVIDER_ENV_VAR", "env-value")
+
+	varfileContent := `
+env_var: '{{ env("TEST_PROVIDER_ENV_VAR") }}'
+file_var: '{{ file("` + dataFile + `") }}'
+exec_var: '{{ exec("echo", "hello") }}'
+shell_var: '{{ shell("echo hi | tr a-z A-Z") }}'
+`
+	require.NoError(t, os.WriteFile(varfilePath, []byte(varfileContent), 0644))
+
+	vars, err := core.ResolveVarfile(context.Background(), varfilePath, nil, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "env-value", vars["env_var"])
+	assert.Equal(t, "file-token", vars["file_var"])
+	assert.Equal(t, "hello", vars["exec_var"])
+	assert.Equal(t, "HI", vars["shell_var"])
+}
+
+func TestResolveVarfile_VarProviders_Errors(t *testing.T) {
+	tempDir := t.TempDir()
+	varfilePath := filepath.Join(tempDir, "test_vars.yml")
+	require.NoError(t, os.WriteFile(varfilePath, []byte(`bad: '{{ notaprovider("x") }}'`), 0644))
+
+	_, err := core.ResolveVarfile(context.Background(), varfilePath, nil, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), `unknown variable provider "notaprovider"`)
+}
+
+func TestResolveVarfile_VarProviderStubs(t *testing.T) {
+	tempDir := t.TempDir()
+	varfilePath := filepath.Join(tempDir, "test_vars.yml")
+	require.NoError(t, os.WriteFile(varfilePath, []byte(`secret: '{{ aws-sm("arn:aws:secretsmanager:::secret:x") }}'`), 0644))
+
+	_, err := core.ResolveVarfile(context.Background(), varfilePath, nil, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not yet implemented")
+}
+
+func TestResolveVarfile_MasksSecretProviderValues(t *testing.T) {
+	tempDir := t.TempDir()
+	varfilePath := filepath.Join(tempDir, "test_vars.yml")
+	require.NoError(t, os.WriteFile(varfilePath, []byte(`token: '{{ exec("echo", "topsecret") }}'`), 0644))
+
+	// RegisterSecretVarProviderName has no matching Unregister (mirrors RegisterVarProvider and
+	// RegisterTemplateFilter, both process-global for the run's lifetime); "exec" staying marked
+	// as a secret provider for the rest of the test binary doesn't affect any other test here.
+	core.RegisterSecretVarProviderName("exec")
+
+	var masked []string
+	vars, err := core.ResolveVarfile(context.Background(), varfilePath, nil, func(s string) {
+		masked = append(masked, s)
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "topsecret", vars["token"])
+	assert.Equal(t, []string{"topsecret"}, masked)
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/arnavsurve/dropstep/pkg/steprunner"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// WorkflowRunner implements `uses: workflow`: it loads another workflow file and runs it to
+// completion as this step, exposing the child's declared `outputs:` (see ResolveWorkflowOutputs)
+// as this step's Output map, the same way an http step's Output is its parsed response body. It
+// lives here in pkg/core, rather than alongside the other runners in pkg/steprunner/runners,
+// since running a workflow end to end means calling back into LoadWorkflowFromFile and
+// WorkflowEngine.ExecuteWorkflow themselves.
+//
+// A sub-workflow run started this way doesn't inherit the parent's step cache, artifact
+// archiving, or run summary — it's scoped to composing workflows together, not to every engine
+// feature a top-level `dropstep run` gets.
+type WorkflowRunner struct {
+	StepCtx types.ExecutionContext
+}
+
+func init() {
+	steprunner.RegisterRunnerFactory("workflow", func(ctx types.ExecutionContext) (steprunner.StepRunner, error) {
+		return &WorkflowRunner{StepCtx: ctx}, nil
+	})
+}
+
+func (wr *WorkflowRunner) Validate() error {
+	step := wr.StepCtx.Step
+
+	if step.Workflow == nil {
+		return fmt.Errorf("workflow step %q must define 'workflow'", step.ID)
+	}
+	if step.Workflow.Path == "" {
+		return fmt.Errorf("workflow step %q: 'workflow.path' is required", step.ID)
+	}
+	if step.Command != nil {
+		return fmt.Errorf("workflow step %q must not define 'run'", step.ID)
+	}
+	if step.Call != nil {
+		return fmt.Errorf("workflow step %q must not define 'call'", step.ID)
+	}
+	if step.BrowserConfig.Prompt != "" {
+		return fmt.Errorf("workflow step %q must not define 'browser.prompt'", step.ID)
+	}
+	if step.Provider != "" {
+		return fmt.Errorf("workflow step %q must not define 'provider'", step.ID)
+	}
+
+	return nil
+}
+
+func (wr *WorkflowRunner) Run(ctx context.Context) (*types.StepResult, error) {
+	step := wr.StepCtx.Step
+
+	childPath := step.Workflow.Path
+	if !filepath.IsAbs(childPath) {
+		childPath = filepath.Join(wr.StepCtx.WorkflowDir, childPath)
+	}
+	childAbsPath, err := filepath.Abs(childPath)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path for imported workflow %q: %w", step.Workflow.Path, err)
+	}
+
+	child, err := LoadWorkflowFromFile(childAbsPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading imported workflow %q: %w", childAbsPath, err)
+	}
+
+	childVars := make(VarContext, len(step.Workflow.With))
+	for k, v := range step.Workflow.With {
+		if v == nil {
+			continue
+		}
+		childVars[k] = fmt.Sprintf("%v", v)
+	}
+	for _, input := range child.Inputs {
+		if _, ok := childVars[input.Name]; !ok && input.Default != "" {
+			childVars[input.Name] = input.Default
+		}
+	}
+	if err := ValidateRequiredInputs(child, childVars, filepath.Dir(childAbsPath)); err != nil {
+		return nil, fmt.Errorf("imported workflow %q: %w", childAbsPath, err)
+	}
+
+	resolvedProviders := make(map[string]ProviderConfig, len(child.Providers))
+	for _, p := range child.Providers {
+		resolvedP, err := ResolveProviderVariables(&p, childVars, child.Inputs...)
+		if err != nil {
+			return nil, fmt.Errorf("imported workflow %q: resolving variables for provider %q: %w", childAbsPath, p.Name, err)
+		}
+		resolvedProviders[p.Name] = *resolvedP
+	}
+
+	childEngine := WorkflowEngine{
+		Logger:     wr.StepCtx.Logger,
+		NoProgress: wr.StepCtx.NoProgress,
+		Silent:     wr.StepCtx.Silent,
+		MaskFunc:   wr.StepCtx.AddMask,
+		PathFunc:   wr.StepCtx.AddPath,
+		PathDirs:   wr.StepCtx.PathDirs,
+	}
+
+	_, outputs, err := childEngine.ExecuteWorkflow(ctx, child, childVars, nil, filepath.Dir(childAbsPath), resolvedProviders)
+	if err != nil {
+		return nil, fmt.Errorf("running imported workflow %q: %w", childAbsPath, err)
+	}
+
+	return &types.StepResult{Output: outputs}, nil
+}
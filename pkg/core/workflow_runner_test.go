@@ -0,0 +1,121 @@
+package core_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/core"
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/arnavsurve/dropstep/pkg/steprunner"
+	"github.com/arnavsurve/dropstep/pkg/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkflowRunner_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		step        core.Step
+		shouldError bool
+		errorMsg    string
+	}{
+		{
+			name: "valid",
+			step: core.Step{
+				ID:       "call_child",
+				Uses:     "workflow",
+				Workflow: &types.WorkflowCall{Path: "child.yml"},
+			},
+			shouldError: false,
+		},
+		{
+			name:        "missing workflow block",
+			step:        core.Step{ID: "call_child", Uses: "workflow"},
+			shouldError: true,
+			errorMsg:    "must define 'workflow'",
+		},
+		{
+			name: "missing path",
+			step: core.Step{
+				ID:       "call_child",
+				Uses:     "workflow",
+				Workflow: &types.WorkflowCall{},
+			},
+			shouldError: true,
+			errorMsg:    "'workflow.path' is required",
+		},
+		{
+			name: "conflicting run",
+			step: core.Step{
+				ID:       "call_child",
+				Uses:     "workflow",
+				Workflow: &types.WorkflowCall{Path: "child.yml"},
+				Command:  &core.CommandBlock{Inline: "echo hi"},
+			},
+			shouldError: true,
+			errorMsg:    "must not define 'run'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner, err := steprunner.GetRunner(types.ExecutionContext{Step: tt.step})
+			require.NoError(t, err)
+
+			err = runner.Validate()
+			if tt.shouldError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWorkflowRunner_Run(t *testing.T) {
+	dir := t.TempDir()
+	childPath := filepath.Join(dir, "child.yml")
+
+	childYaml := `
+name: child
+inputs:
+  - name: who
+    required: true
+steps:
+  - id: greet
+    uses: shell
+    run:
+      inline: "echo -n hello-{{ who }}"
+outputs:
+  greeting: "{{ steps.greet.output }}"
+`
+	require.NoError(t, os.WriteFile(childPath, []byte(childYaml), 0o644))
+
+	step := core.Step{
+		ID:   "call_child",
+		Uses: "workflow",
+		Workflow: &types.WorkflowCall{
+			Path: "child.yml",
+			With: map[string]any{"who": "world"},
+		},
+	}
+
+	runner, err := steprunner.GetRunner(types.ExecutionContext{
+		Step:        step,
+		WorkflowDir: dir,
+		Logger:      log.NewZerologAdapter(zerolog.New(io.Discard)),
+	})
+	require.NoError(t, err)
+
+	result, err := runner.Run(context.Background())
+	require.NoError(t, err)
+
+	outputs, ok := result.Output.(map[string]any)
+	require.True(t, ok, "expected result.Output to be a map, got %T", result.Output)
+	assert.Equal(t, "hello-world", outputs["greeting"])
+}
@@ -0,0 +1,428 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// stepStatus derives a step's coarse pass/fail status for steps.<id>.status: "skipped" for the
+// Output:"skipped" sentinel the engine records for a step whose if/unless/cache condition skipped
+// it (see core.WorkflowEngine and core's DAG executor), "success" otherwise, since a step that
+// failed never makes it into Env.Results at all (the workflow run aborts on the first step error).
+// This is the same sentinel the rest of pkg/core already uses for a skipped step's Output, so a
+// step whose real output happens to be the literal string "skipped" would be misread as skipped
+// too; that pre-existing ambiguity is inherent to the sentinel, not new here.
+func stepStatus(result types.StepResult) string {
+	if s, ok := result.Output.(string); ok && s == "skipped" {
+		return "skipped"
+	}
+	return "success"
+}
+
+type literal struct {
+	value any
+}
+
+func (l *literal) eval(Env) (any, error) {
+	return l.value, nil
+}
+
+// ident resolves a dotted reference: "inputs.<name>", "env.<NAME>", or
+// "steps.<id>.output[.<nested>...]".
+type ident struct {
+	path []string
+}
+
+func (id *ident) eval(env Env) (any, error) {
+	if env.Resolve != nil {
+		val, ok := env.Resolve(joinDotted(id.path))
+		if !ok {
+			return nil, fmt.Errorf("undefined variable: %s", joinDotted(id.path))
+		}
+		return val, nil
+	}
+
+	if len(id.path) < 2 {
+		return nil, fmt.Errorf("variable reference %q must have at least two segments (e.g. inputs.name)", joinDotted(id.path))
+	}
+
+	switch id.path[0] {
+	case "inputs":
+		val, ok := env.Inputs[id.path[1]]
+		if !ok {
+			return nil, fmt.Errorf("undefined input %q", id.path[1])
+		}
+		return val, nil
+	case "env":
+		return env.getenv(id.path[1]), nil
+	case "steps":
+		if len(id.path) < 3 {
+			return nil, fmt.Errorf("step reference %q must be in the form steps.<id>.output[.<field>...] or steps.<id>.status", joinDotted(id.path))
+		}
+		result, ok := env.Results[id.path[1]]
+		if !ok {
+			return nil, fmt.Errorf("step %q has not run yet", id.path[1])
+		}
+		switch id.path[2] {
+		case "output":
+			val, found := getNested(result.Output, id.path[3:])
+			if !found {
+				return nil, fmt.Errorf("step %q output has no field %q", id.path[1], joinDotted(id.path[3:]))
+			}
+			return val, nil
+		case "status":
+			if len(id.path) != 3 {
+				return nil, fmt.Errorf("step reference %q must be in the form steps.<id>.status", joinDotted(id.path))
+			}
+			return stepStatus(result), nil
+		default:
+			return nil, fmt.Errorf("step reference %q must be in the form steps.<id>.output[.<field>...] or steps.<id>.status", joinDotted(id.path))
+		}
+	default:
+		return nil, fmt.Errorf("unknown variable namespace %q (expected inputs, steps, or env)", id.path[0])
+	}
+}
+
+func getNested(data any, path []string) (any, bool) {
+	if len(path) == 0 {
+		return data, true
+	}
+	m, ok := data.(map[string]any)
+	if !ok {
+		return nil, false
+	}
+	val, ok := m[path[0]]
+	if !ok {
+		return nil, false
+	}
+	return getNested(val, path[1:])
+}
+
+func joinDotted(path []string) string {
+	out := ""
+	for i, p := range path {
+		if i > 0 {
+			out += "."
+		}
+		out += p
+	}
+	return out
+}
+
+type notExpr struct {
+	operand node
+}
+
+func (n *notExpr) eval(env Env) (any, error) {
+	val, err := n.operand.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	return !truthy(val), nil
+}
+
+type binaryExpr struct {
+	op          string
+	left, right node
+}
+
+func (b *binaryExpr) eval(env Env) (any, error) {
+	switch b.op {
+	// && and || short-circuit and return whichever operand decided the result, JS-style, rather
+	// than coercing to bool, so e.g. "steps.x.output.count > 0 && \"yes\" || \"no\"" yields a
+	// string. Evaluate (the if/unless entry point) still requires the overall expression's final
+	// result to be a literal bool, so a bare non-boolean operand as a whole if: condition is
+	// unaffected by this.
+	case "&&":
+		left, err := b.left.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		if !truthy(left) {
+			return left, nil
+		}
+		return b.right.eval(env)
+	case "||":
+		left, err := b.left.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		if truthy(left) {
+			return left, nil
+		}
+		return b.right.eval(env)
+	case "==", "!=":
+		left, err := b.left.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		right, err := b.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		eq := valuesEqual(left, right)
+		if b.op == "!=" {
+			return !eq, nil
+		}
+		return eq, nil
+	case "<", ">", "<=", ">=":
+		left, err := b.left.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		right, err := b.right.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		return compareOrdered(b.op, left, right)
+	default:
+		return nil, fmt.Errorf("unknown operator %q", b.op)
+	}
+}
+
+// compareOrdered evaluates a relational operator over two values that must both coerce to a
+// number, mirroring valuesEqual's string<->number coercion for =='s operands.
+func compareOrdered(op string, left, right any) (bool, error) {
+	lf, lok := toFloatForCompare(left)
+	rf, rok := toFloatForCompare(right)
+	if !lok || !rok {
+		return false, fmt.Errorf("operator %q requires numeric operands, got %T and %T", op, left, right)
+	}
+	switch op {
+	case "<":
+		return lf < rf, nil
+	case ">":
+		return lf > rf, nil
+	case "<=":
+		return lf <= rf, nil
+	case ">=":
+		return lf >= rf, nil
+	default:
+		return false, fmt.Errorf("unsupported operator %q", op)
+	}
+}
+
+func toFloatForCompare(v any) (float64, bool) {
+	switch t := v.(type) {
+	case float64:
+		return t, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(t), 64)
+		if err != nil {
+			return 0, false
+		}
+		return f, true
+	default:
+		return 0, false
+	}
+}
+
+// valuesEqual compares two values from possibly different sources (a step output's native JSON
+// type vs. a literal, or an input's always-string value vs. a bool/number literal), coercing
+// the right-hand side's type to match the left-hand side's when they differ.
+func valuesEqual(left, right any) bool {
+	switch l := left.(type) {
+	case string:
+		switch r := right.(type) {
+		case string:
+			return l == r
+		case bool:
+			b, err := strconv.ParseBool(l)
+			return err == nil && b == r
+		case float64:
+			f, err := strconv.ParseFloat(l, 64)
+			return err == nil && f == r
+		}
+	case bool:
+		switch r := right.(type) {
+		case bool:
+			return l == r
+		case string:
+			b, err := strconv.ParseBool(r)
+			return err == nil && b == l
+		}
+	case float64:
+		switch r := right.(type) {
+		case float64:
+			return l == r
+		case string:
+			f, err := strconv.ParseFloat(r, 64)
+			return err == nil && f == l
+		}
+	}
+	return fmt.Sprintf("%v", left) == fmt.Sprintf("%v", right)
+}
+
+// truthy applies JS-like truthiness for &&/||/unary "!": nil, "", 0, and false are falsy;
+// everything else (including a non-empty slice/map) is truthy.
+func truthy(v any) bool {
+	switch t := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return t
+	case string:
+		return t != ""
+	case float64:
+		return t != 0
+	default:
+		return true
+	}
+}
+
+// knownFuncs maps a helper name to the number of arguments it takes, checked at parse time so a
+// typo'd name or wrong arg count fails workflow validation rather than mid-run.
+var knownFuncs = map[string]int{
+	"contains":   2,
+	"startsWith": 2,
+	"int":        1,
+	"matches":    2,
+}
+
+type funcCall struct {
+	name string
+	args []node
+}
+
+// newFuncCall builds a call node for name, which may be one of knownFuncs (arg count checked
+// right away) or a name an Env.Funcs supplies only at evaluation time — Parse/Validate have no
+// Env to check those against, so an unrecognized name is accepted here and only reported as
+// "unknown function" if eval doesn't find it in Env.Funcs either.
+func newFuncCall(name string, args []node) (node, error) {
+	if wantArgs, ok := knownFuncs[name]; ok {
+		if len(args) != wantArgs {
+			return nil, fmt.Errorf("%s() takes %d argument(s), got %d", name, wantArgs, len(args))
+		}
+	}
+	return &funcCall{name: name, args: args}, nil
+}
+
+func (f *funcCall) eval(env Env) (any, error) {
+	argVals := make([]any, len(f.args))
+	for i, a := range f.args {
+		v, err := a.eval(env)
+		if err != nil {
+			// default()'s whole purpose is to stand in for a variable that may not exist, so an
+			// "undefined variable" error resolving its first argument isn't a real failure — it's
+			// treated as nil, same as an existing-but-empty value, and left to whichever "default"
+			// implementation (built-in or Env.Funcs) to fall back from.
+			if f.name == "default" && i == 0 {
+				argVals[i] = nil
+				continue
+			}
+			return nil, err
+		}
+		argVals[i] = v
+	}
+
+	// A caller-supplied Env.Funcs entry takes priority over a same-named built-in, so a caller
+	// whose own version is a superset (e.g. core's "contains", which also accepts a []any
+	// haystack, not just a string) isn't shadowed by this package's stricter built-in.
+	if fn, ok := env.Funcs[f.name]; ok {
+		result, err := fn(argVals)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.name, err)
+		}
+		return result, nil
+	}
+
+	switch f.name {
+	case "contains":
+		haystack, needle, err := toStringPair(argVals)
+		if err != nil {
+			return nil, fmt.Errorf("contains(): %w", err)
+		}
+		return strings.Contains(haystack, needle), nil
+	case "startsWith":
+		s, prefix, err := toStringPair(argVals)
+		if err != nil {
+			return nil, fmt.Errorf("startsWith(): %w", err)
+		}
+		return strings.HasPrefix(s, prefix), nil
+	case "int":
+		return toInt(argVals[0])
+	case "matches":
+		s, pattern, err := toStringPair(argVals)
+		if err != nil {
+			return nil, fmt.Errorf("matches(): %w", err)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("matches(): invalid regex %q: %w", pattern, err)
+		}
+		return re.MatchString(s), nil
+	default:
+		return nil, fmt.Errorf("unknown function %q", f.name)
+	}
+}
+
+func toStringPair(vals []any) (string, string, error) {
+	a, err := toString(vals[0])
+	if err != nil {
+		return "", "", err
+	}
+	b, err := toString(vals[1])
+	if err != nil {
+		return "", "", err
+	}
+	return a, b, nil
+}
+
+func toString(val any) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		return "", fmt.Errorf("value %v (%T) cannot be used as a string", v, v)
+	}
+}
+
+// toInt coerces val to a whole-number float64 (every number in this language is a float64, per
+// literal and binaryExpr), truncating toward zero like a Go int conversion would.
+func toInt(val any) (float64, error) {
+	switch v := val.(type) {
+	case float64:
+		return math.Trunc(v), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("int(): value %q is not numeric", v)
+		}
+		return math.Trunc(f), nil
+	default:
+		return 0, fmt.Errorf("int(): value %v (%T) is not numeric", v, v)
+	}
+}
+
+// collectStepRefs walks n's tree and returns every step id referenced via a steps.<id>... ident,
+// so Validate can check each one against the workflow's known step ids.
+func collectStepRefs(n node) []string {
+	switch v := n.(type) {
+	case *ident:
+		if len(v.path) >= 2 && v.path[0] == "steps" {
+			return []string{v.path[1]}
+		}
+		return nil
+	case *notExpr:
+		return collectStepRefs(v.operand)
+	case *binaryExpr:
+		return append(collectStepRefs(v.left), collectStepRefs(v.right)...)
+	case *funcCall:
+		var refs []string
+		for _, a := range v.args {
+			refs = append(refs, collectStepRefs(a)...)
+		}
+		return refs
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,94 @@
+// Package expr implements the small expression language used by a step's `if:`/`unless:` fields,
+// e.g. `steps.probe.output.ready == true && contains(inputs.env, "prod")`, and, via EvalValue and
+// Env's Resolve/Funcs hooks, by core's `{{ }}` template interpolation. Expressions support `==`,
+// `!=`, `<`, `>`, `<=`, `>=`, `&&`, `||`, `!`, parentheses, string/number/bool literals, dotted
+// variable references into `inputs.*`, `steps.<id>.output.*`, `steps.<id>.status`, and `env.*`, and
+// calls to the helpers contains(a, b), startsWith(a, b), int(a), and matches(a, regex).
+package expr
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// Env supplies the variables an expression may reference.
+type Env struct {
+	Inputs  map[string]string
+	Results map[string]types.StepResult
+	// Getenv resolves `env.*` references. Defaults to os.Getenv when nil.
+	Getenv func(string) string
+	// Resolve, if set, overrides the built-in inputs/steps/env dotted-path lookup entirely: it's
+	// handed an ident's full dotted path (e.g. "steps.build.output.version") and returns its value.
+	// Callers with their own richer variable context (e.g. core's template interpolation, which
+	// resolves additional forms like steps.<id>.outputs.<name> and a ".json" suffix shortcut via
+	// core.FindValueInContext) supply this instead of Inputs/Results.
+	Resolve func(path string) (any, bool)
+	// Funcs supplies additional named functions callable from an expression, consulted before the
+	// built-in knownFuncs table (contains, startsWith, int, matches) — a name in both uses the
+	// Funcs entry, so a caller with a richer version of a built-in (e.g. core's "contains", which
+	// also accepts a []any haystack) isn't shadowed by this package's stricter one. Unlike
+	// knownFuncs, a Funcs entry isn't arg-count-checked by Validate, since Validate has no Env to
+	// check against — a wrong arg count surfaces as an error from the function itself at
+	// evaluation time instead.
+	Funcs map[string]func(args []any) (any, error)
+}
+
+func (e Env) getenv(key string) string {
+	if e.Getenv != nil {
+		return e.Getenv(key)
+	}
+	return os.Getenv(key)
+}
+
+// Evaluate parses and evaluates expression against env, returning its boolean result.
+func Evaluate(expression string, inputs map[string]string, results map[string]types.StepResult) (bool, error) {
+	node, err := Parse(expression)
+	if err != nil {
+		return false, err
+	}
+	val, err := node.eval(Env{Inputs: inputs, Results: results})
+	if err != nil {
+		return false, fmt.Errorf("evaluating %q: %w", expression, err)
+	}
+	b, ok := val.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q did not evaluate to a boolean (got %T)", expression, val)
+	}
+	return b, nil
+}
+
+// EvalValue parses and evaluates expression against env, returning its raw result without
+// requiring it to be a boolean (unlike Evaluate). Used by callers like core's template
+// interpolation, which embeds an expression's result (a number, string, or nested value) directly
+// into a larger "{{ ... }}" substitution rather than branching on it.
+func EvalValue(expression string, env Env) (any, error) {
+	node, err := Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	val, err := node.eval(env)
+	if err != nil {
+		return nil, fmt.Errorf("evaluating %q: %w", expression, err)
+	}
+	return val, nil
+}
+
+// Validate parses expression without evaluating it, so a malformed condition (e.g. a typo'd
+// operator, an unclosed paren, or a call to an unknown helper) fails at workflow-validation time
+// rather than mid-run. It also checks every steps.<id>... reference in expression against
+// knownSteps, so a typo'd or renamed step id is caught the same way instead of surfacing as "step
+// has not run yet" mid-run.
+func Validate(expression string, knownSteps map[string]bool) error {
+	node, err := Parse(expression)
+	if err != nil {
+		return err
+	}
+	for _, id := range collectStepRefs(node) {
+		if !knownSteps[id] {
+			return fmt.Errorf("expression %q references unknown step %q", expression, id)
+		}
+	}
+	return nil
+}
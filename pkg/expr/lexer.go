@@ -0,0 +1,146 @@
+package expr
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokAnd
+	tokOr
+	tokNot
+	tokEq
+	tokNeq
+	tokLt
+	tokGt
+	tokLe
+	tokGe
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+type lexer struct {
+	input string
+	pos   int
+}
+
+func newLexer(input string) *lexer {
+	return &lexer{input: input}
+}
+
+func (l *lexer) peekByte() byte {
+	if l.pos >= len(l.input) {
+		return 0
+	}
+	return l.input[l.pos]
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && (l.input[l.pos] == ' ' || l.input[l.pos] == '\t') {
+		l.pos++
+	}
+	if l.pos >= len(l.input) {
+		return token{kind: tokEOF}, nil
+	}
+
+	c := l.input[l.pos]
+	switch {
+	case c == '(':
+		l.pos++
+		return token{kind: tokLParen}, nil
+	case c == ')':
+		l.pos++
+		return token{kind: tokRParen}, nil
+	case c == ',':
+		l.pos++
+		return token{kind: tokComma}, nil
+	case c == '&' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '&':
+		l.pos += 2
+		return token{kind: tokAnd}, nil
+	case c == '|' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '|':
+		l.pos += 2
+		return token{kind: tokOr}, nil
+	case c == '=' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '=':
+		l.pos += 2
+		return token{kind: tokEq}, nil
+	case c == '!' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '=':
+		l.pos += 2
+		return token{kind: tokNeq}, nil
+	case c == '<' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '=':
+		l.pos += 2
+		return token{kind: tokLe}, nil
+	case c == '>' && l.pos+1 < len(l.input) && l.input[l.pos+1] == '=':
+		l.pos += 2
+		return token{kind: tokGe}, nil
+	case c == '<':
+		l.pos++
+		return token{kind: tokLt}, nil
+	case c == '>':
+		l.pos++
+		return token{kind: tokGt}, nil
+	case c == '!':
+		l.pos++
+		return token{kind: tokNot}, nil
+	case c == '"' || c == '\'':
+		return l.lexString(c)
+	case isDigit(c):
+		return l.lexNumber()
+	case isIdentStart(c):
+		return l.lexIdent()
+	default:
+		return token{}, fmt.Errorf("unexpected character %q at position %d", c, l.pos)
+	}
+}
+
+func (l *lexer) lexString(quote byte) (token, error) {
+	start := l.pos
+	l.pos++ // skip opening quote
+	var sb strings.Builder
+	for l.pos < len(l.input) && l.input[l.pos] != quote {
+		sb.WriteByte(l.input[l.pos])
+		l.pos++
+	}
+	if l.pos >= len(l.input) {
+		return token{}, fmt.Errorf("unterminated string literal starting at position %d", start)
+	}
+	l.pos++ // skip closing quote
+	return token{kind: tokString, text: sb.String()}, nil
+}
+
+func (l *lexer) lexNumber() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && (isDigit(l.input[l.pos]) || l.input[l.pos] == '.') {
+		l.pos++
+	}
+	return token{kind: tokNumber, text: l.input[start:l.pos]}, nil
+}
+
+func (l *lexer) lexIdent() (token, error) {
+	start := l.pos
+	for l.pos < len(l.input) && (isIdentPart(l.input[l.pos]) || l.input[l.pos] == '.') {
+		l.pos++
+	}
+	return token{kind: tokIdent, text: l.input[start:l.pos]}, nil
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentPart(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
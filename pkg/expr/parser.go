@@ -0,0 +1,206 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// node is a parsed expression node. eval resolves it against env.
+type node interface {
+	eval(env Env) (any, error)
+}
+
+// Parse parses expression into a node tree, ready for eval. It does not evaluate it, so callers
+// that only want to validate syntax can discard the result.
+func Parse(expression string) (node, error) {
+	p := &parser{lex: newLexer(expression)}
+	if err := p.advance(); err != nil {
+		return nil, fmt.Errorf("parsing expression %q: %w", expression, err)
+	}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("parsing expression %q: %w", expression, err)
+	}
+	if p.cur.kind != tokEOF {
+		return nil, fmt.Errorf("parsing expression %q: unexpected trailing input near %q", expression, p.cur.text)
+	}
+	return n, nil
+}
+
+type parser struct {
+	lex *lexer
+	cur token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.cur = tok
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.cur.kind == tokOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.cur.kind == tokAnd {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryExpr{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.cur.kind == tokNot {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	op, ok := comparisonOps[p.cur.kind]
+	if !ok {
+		return left, nil
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return &binaryExpr{op: op, left: left, right: right}, nil
+}
+
+// comparisonOps maps each comparison token to its operator string, consulted by parseComparison.
+var comparisonOps = map[tokenKind]string{
+	tokEq:  "==",
+	tokNeq: "!=",
+	tokLt:  "<",
+	tokGt:  ">",
+	tokLe:  "<=",
+	tokGe:  ">=",
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	switch p.cur.kind {
+	case tokLParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.cur.kind != tokRParen {
+			return nil, fmt.Errorf("expected ')'")
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case tokString:
+		lit := &literal{value: p.cur.text}
+		return lit, p.advance()
+	case tokNumber:
+		f, err := strconv.ParseFloat(p.cur.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", p.cur.text)
+		}
+		lit := &literal{value: f}
+		return lit, p.advance()
+	case tokIdent:
+		text := p.cur.text
+		switch strings.ToLower(text) {
+		case "true":
+			lit := &literal{value: true}
+			return lit, p.advance()
+		case "false":
+			lit := &literal{value: false}
+			return lit, p.advance()
+		}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.cur.kind == tokLParen {
+			return p.parseFuncCall(text)
+		}
+		id := &ident{path: strings.Split(text, ".")}
+		return id, nil
+	default:
+		return nil, fmt.Errorf("unexpected token near %q", p.cur.text)
+	}
+}
+
+// parseFuncCall parses a call to one of the helpers in knownFuncs (e.g. contains(a, b)), with cur
+// already positioned on the opening '('.
+func (p *parser) parseFuncCall(name string) (node, error) {
+	if err := p.advance(); err != nil { // consume '('
+		return nil, err
+	}
+
+	var args []node
+	if p.cur.kind != tokRParen {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.cur.kind != tokComma {
+				break
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if p.cur.kind != tokRParen {
+		return nil, fmt.Errorf("expected ')' to close call to %q", name)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	return newFuncCall(name, args)
+}
@@ -1,4 +1,7 @@
-package core
+// Package fileutil holds small, dependency-free path helpers shared by pkg/core and
+// pkg/steprunner's runners. It depends on nothing beyond the standard library so that both sides
+// of the pkg/core <-> pkg/steprunner boundary can import it without risking an import cycle.
+package fileutil
 
 import "path/filepath"
 
@@ -12,4 +15,4 @@ func ResolvePathFromWorkflow(workflowDir, pathFromYAML string) (string, error) {
 
 	absPath := filepath.Join(workflowDir, pathFromYAML)
 	return absPath, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,26 @@
+package log
+
+import "github.com/arnavsurve/dropstep/pkg/types"
+
+// leveledSink wraps another Sink so it only ever sees events at or above a minimum level, letting
+// e.g. a file sink capture everything while the console only shows warnings and above.
+type leveledSink struct {
+	inner Sink
+	min   types.Level
+}
+
+// LeveledSink adapts inner so Router.Write's events below min are dropped before reaching it.
+func LeveledSink(inner Sink, min types.Level) Sink {
+	return &leveledSink{inner: inner, min: min}
+}
+
+func (s *leveledSink) Write(event *LogEvent) error {
+	if event.Level < s.min {
+		return nil
+	}
+	return s.inner.Write(event)
+}
+
+func (s *leveledSink) Close() error {
+	return s.inner.Close()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/arnavsurve/dropstep/pkg/security"
@@ -27,8 +28,10 @@ type Sink interface {
 	io.Closer
 }
 
-// Router routes log events to multiple sinks
+// Router routes log events to multiple sinks. Its methods are safe to call concurrently, since
+// matrix/parallel steps (see core.WorkflowEngine) can log from several goroutines at once.
 type Router struct {
+	mu       sync.RWMutex
 	sinks    []Sink
 	redactor *security.Redactor
 }
@@ -78,33 +81,40 @@ func (r *Router) Write(p []byte) (n int, err error) {
 		}
 	}
 
-	if r.redactor != nil {
-		evt.Message = r.redactor.Redact(evt.Message)
+	r.mu.RLock()
+	redactor := r.redactor
+	sinks := make([]Sink, len(r.sinks))
+	copy(sinks, r.sinks)
+	r.mu.RUnlock()
+
+	if redactor != nil {
+		evt.Message = redactor.Redact(evt.Message)
 		for k, v := range evt.Fields {
 			if strVal, ok := v.(string); ok {
-				evt.Fields[k] = r.redactor.Redact(strVal)
+				evt.Fields[k] = redactor.RedactField(k, strVal)
 			}
 		}
 		for _, v := range evt.Fields {
 			if m, ok := v.(map[string]any); ok {
 				for kk, vv := range m {
 					if strVal, ok := vv.(string); ok {
-						m[kk] = r.redactor.Redact(strVal)
+						m[kk] = redactor.RedactField(kk, strVal)
 					}
 				}
 			}
 			if s, ok := v.([]any); ok {
 				for i, vv := range s {
 					if strVal, ok := vv.(string); ok {
-						s[i] = r.redactor.Redact(strVal)
+						s[i] = redactor.Redact(strVal)
 					}
 				}
 			}
 		}
 	}
 
-	for _, sink := range r.sinks {
-		// TODO: check evt.Level against sink's minLevel if sinks have individual levels
+	// A sink wrapped with LeveledSink filters out-of-range events itself, so there's nothing
+	// else to consult here.
+	for _, sink := range sinks {
 		if err := sink.Write(evt); err != nil {
 			fmt.Fprintf(os.Stderr, "Router: Error writing to sink: %v\n", err)
 		}
@@ -131,10 +141,44 @@ func ConvertZerologLevel(zl zerolog.Level) types.Level {
 }
 
 func (r *Router) AddSink(sink Sink) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.sinks = append(r.sinks, sink)
 }
 
+// SetRedactor installs the redactor used to scrub secrets from every event passed through r,
+// preserving any secret values already registered via AddSecretMask (e.g. from a secrets provider
+// or varfile resolved before the caller has enough context to build redactor). Call this once the
+// workflow's own Secret: true inputs are resolved; call AddSecretMask any time before or after.
+func (r *Router) SetRedactor(redactor *security.Redactor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.redactor != nil && len(r.redactor.Secrets) > 0 {
+		redactor.Secrets = append(append([]string{}, r.redactor.Secrets...), redactor.Secrets...)
+	}
+	r.redactor = redactor
+}
+
+// AddSecretMask registers a value discovered at runtime (e.g. a step's "::add-mask::" workflow
+// command) to be redacted from all subsequent events, for the rest of the run. It installs a new
+// *security.Redactor rather than mutating the existing one's Secrets slice in place, since
+// Router.Write reads that slice outside of r.mu once it copies out the redactor pointer.
+func (r *Router) AddSecretMask(secret string) {
+	if secret == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var secrets []string
+	if r.redactor != nil {
+		secrets = append(secrets, r.redactor.Secrets...)
+	}
+	r.redactor = &security.Redactor{Secrets: append(secrets, secret)}
+}
+
 func (r *Router) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	var firstErr error
 	for _, sink := range r.sinks {
 		if err := sink.Close(); err != nil && firstErr == nil {
@@ -0,0 +1,173 @@
+package log_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/arnavsurve/dropstep/pkg/log/sinks"
+	"github.com/arnavsurve/dropstep/pkg/security"
+	"github.com/arnavsurve/dropstep/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capturingSink records every event it's handed, so a test can assert on the redacted values the
+// router actually delivered rather than re-parsing a sink's own serialization. Its own mutex
+// keeps it safe to share across goroutines in a concurrency test, independent of whatever
+// guarantees Router itself makes.
+type capturingSink struct {
+	mu     sync.Mutex
+	events []*log.LogEvent
+}
+
+func (c *capturingSink) Write(event *log.LogEvent) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, event)
+	return nil
+}
+
+func (c *capturingSink) Close() error { return nil }
+
+func writeZerologLine(t *testing.T, router *log.Router, fields map[string]any, msg string) {
+	t.Helper()
+	entry := map[string]any{
+		"level":   "info",
+		"time":    "2026-01-01T00:00:00Z",
+		"message": msg,
+	}
+	for k, v := range fields {
+		entry[k] = v
+	}
+	data, err := json.Marshal(entry)
+	require.NoError(t, err)
+
+	_, err = router.Write(append(data, '\n'))
+	require.NoError(t, err)
+}
+
+func TestRouter_RedactsKnownSecretValues(t *testing.T) {
+	capture := &capturingSink{}
+	router := log.NewRouter(capture)
+	router.SetRedactor(&security.Redactor{Secrets: []string{"sk-super-secret"}})
+
+	writeZerologLine(t, router, map[string]any{"token": "sk-super-secret"}, "using token sk-super-secret to call API")
+
+	require.Len(t, capture.events, 1)
+	evt := capture.events[0]
+	assert.Equal(t, "using token ******** to call API", evt.Message)
+	assert.Equal(t, "********", evt.Fields["token"])
+}
+
+func TestRouter_RedactsSecretsNestedInMapsAndSlices(t *testing.T) {
+	capture := &capturingSink{}
+	router := log.NewRouter(capture)
+	router.SetRedactor(&security.Redactor{Secrets: []string{"sk-super-secret"}})
+
+	writeZerologLine(t, router, map[string]any{
+		"headers": map[string]any{"X-Custom": "sk-super-secret"},
+		"tags":    []any{"sk-super-secret", "public"},
+	}, "making request")
+
+	require.Len(t, capture.events, 1)
+	evt := capture.events[0]
+	headers, ok := evt.Fields["headers"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "********", headers["X-Custom"])
+
+	tags, ok := evt.Fields["tags"].([]any)
+	require.True(t, ok)
+	assert.Equal(t, "********", tags[0])
+	assert.Equal(t, "public", tags[1])
+}
+
+func TestRouter_AlwaysRedactsSensitiveFieldNames(t *testing.T) {
+	capture := &capturingSink{}
+	router := log.NewRouter(capture)
+	// No secret values registered at all: a hardcoded Authorization header was never routed
+	// through a {{ secret.* }} template or a Secret: true input, so value-based matching alone
+	// wouldn't catch it.
+	router.SetRedactor(&security.Redactor{})
+
+	writeZerologLine(t, router, map[string]any{
+		"headers": map[string]any{"Authorization": "Bearer hardcoded-token-123"},
+	}, "making request")
+
+	require.Len(t, capture.events, 1)
+	headers, ok := capture.events[0].Fields["headers"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, "********", headers["Authorization"])
+}
+
+func TestRouter_RedactsAcrossSinks(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "run.log")
+	fileSink, err := sinks.NewFileSink(logPath)
+	require.NoError(t, err)
+	defer fileSink.Close()
+
+	capture := &capturingSink{}
+	router := log.NewRouter(fileSink, capture)
+	router.SetRedactor(&security.Redactor{Secrets: []string{"sk-super-secret"}})
+
+	writeZerologLine(t, router, map[string]any{"api_key": "sk-super-secret"}, "token is sk-super-secret")
+
+	require.Len(t, capture.events, 1)
+	assert.Equal(t, "token is ********", capture.events[0].Message)
+
+	raw, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "sk-super-secret")
+
+	var fileEntry map[string]any
+	require.NoError(t, json.Unmarshal(raw[:len(raw)-1], &fileEntry))
+	assert.Equal(t, "token is ********", fileEntry["message"])
+}
+
+func TestLeveledSink_FiltersBelowMinimum(t *testing.T) {
+	capture := &capturingSink{}
+	leveled := log.LeveledSink(capture, types.WarnLevel)
+	router := log.NewRouter(leveled)
+
+	writeZerologLine(t, router, map[string]any{"level": "info"}, "info message")
+	writeZerologLine(t, router, map[string]any{"level": "warn"}, "warn message")
+	writeZerologLine(t, router, map[string]any{"level": "error"}, "error message")
+
+	require.Len(t, capture.events, 2)
+	assert.Equal(t, "warn message", capture.events[0].Message)
+	assert.Equal(t, "error message", capture.events[1].Message)
+}
+
+func TestRouter_SetRedactorPreservesPriorSecretMasks(t *testing.T) {
+	capture := &capturingSink{}
+	router := log.NewRouter(capture)
+	router.AddSecretMask("sk-from-varfile")
+	router.SetRedactor(&security.Redactor{Secrets: []string{"sk-from-input"}})
+
+	writeZerologLine(t, router, nil, "values sk-from-varfile and sk-from-input")
+
+	require.Len(t, capture.events, 1)
+	assert.Equal(t, "values ******** and ********", capture.events[0].Message)
+}
+
+func TestRouter_ConcurrentWritesAndAddSink(t *testing.T) {
+	router := log.NewRouter()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			router.AddSink(&capturingSink{})
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			writeZerologLine(t, router, nil, "concurrent message")
+		}(i)
+	}
+	wg.Wait()
+}
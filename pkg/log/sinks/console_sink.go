@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/arnavsurve/dropstep/pkg/log"
@@ -11,14 +12,51 @@ import (
 	"github.com/fatih/color"
 )
 
-type ConsoleSink struct{}
+// groupIndent is the number of spaces each nested "::group::"/"::endgroup::" level indents its
+// output by, matching the visual nesting GitHub Actions' log UI uses.
+const groupIndent = 2
+
+type ConsoleSink struct {
+	mu    sync.Mutex
+	depth int
+}
 
 func NewConsoleSink() *ConsoleSink {
 	return &ConsoleSink{}
 }
 
 func (c *ConsoleSink) Write(event *log.LogEvent) error {
+	if groupTitle := getStringField(event.Fields, "group_start"); groupTitle != "" {
+		c.mu.Lock()
+		fmt.Printf("%s▼ %s\n", strings.Repeat(" ", c.depth*groupIndent), groupTitle)
+		c.depth++
+		c.mu.Unlock()
+		return nil
+	}
+	if _, isEndGroup := event.Fields["group_end"]; isEndGroup {
+		c.mu.Lock()
+		if c.depth > 0 {
+			c.depth--
+		}
+		c.mu.Unlock()
+		return nil
+	}
+
+	c.mu.Lock()
+	indent := strings.Repeat(" ", c.depth*groupIndent)
+	c.mu.Unlock()
+
+	fmt.Println(indent + RenderEvent(event))
+	return nil
+}
+
+// RenderEvent formats event the same way ConsoleSink prints it live, minus group indentation
+// (which depends on the caller's own notion of nesting depth, e.g. a live run vs. a replayed log
+// file). Shared by ConsoleSink.Write and `dropstep logs show`/`tail` so replayed runs look
+// identical to what the user saw the first time.
+func RenderEvent(event *log.LogEvent) string {
 	stepId := getStringField(event.Fields, "step_id")
+	matrix := getStringField(event.Fields, "matrix")
 	msg := event.Message
 	source := getStringField(event.Fields, "source")
 	agentLine := getStringField(event.Fields, "agent_line")
@@ -46,8 +84,10 @@ func (c *ConsoleSink) Write(event *log.LogEvent) error {
 	if stepLabel == "" {
 		stepLabel = "workflow"
 	}
+	if matrix != "" {
+		stepLabel = fmt.Sprintf("%s[%s]", stepLabel, matrix)
+	}
 
-	var output string
 	commonPrefix := fmt.Sprintf("[%s %s] %s: ",
 		levelFmt(levelStr),
 		timestampFmt(timestampStr),
@@ -56,21 +96,19 @@ func (c *ConsoleSink) Write(event *log.LogEvent) error {
 
 	switch {
 	case agentLine != "" && source != "":
-		output = fmt.Sprintf("%s[agent/%s]: %s", commonPrefix, color.BlueString(source), agentLine)
+		return fmt.Sprintf("%s[agent/%s]: %s", commonPrefix, color.BlueString(source), agentLine)
 	case shellLine != "" && source != "":
-		output = fmt.Sprintf("%s[shell/%s]: %s", commonPrefix, color.BlueString(source), shellLine)
+		return fmt.Sprintf("%s[shell/%s]: %s", commonPrefix, color.BlueString(source), shellLine)
 	case pythonLine != "" && source != "":
-		output = fmt.Sprintf("%s[python/%s]: %s", commonPrefix, color.BlueString(source), pythonLine)
+		return fmt.Sprintf("%s[python/%s]: %s", commonPrefix, color.BlueString(source), pythonLine)
 	case errorMsg != "":
-		output = fmt.Sprintf("%s%s", commonPrefix, errorMsg)
+		return fmt.Sprintf("%s%s", commonPrefix, errorMsg)
 	case msg != "":
-		output = fmt.Sprintf("%s%s", commonPrefix, msg)
+		return fmt.Sprintf("%s%s", commonPrefix, msg)
 	default:
 		fieldsStr, _ := json.MarshalIndent(event.Fields, "", "  ")
-		output = fmt.Sprintf("%s%s %s", commonPrefix, msg, string(fieldsStr))
+		return fmt.Sprintf("%s%s %s", commonPrefix, msg, string(fieldsStr))
 	}
-	fmt.Println(output)
-	return nil
 }
 
 // Helper to safely get string field from LogEvent.Fields
@@ -0,0 +1,89 @@
+package sinks
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// reservedLogFields are the top-level keys FileSink writes that aren't part of LogEvent.Fields.
+var reservedLogFields = map[string]struct{}{
+	"level":   {},
+	"time":    {},
+	"message": {},
+}
+
+// ReadEvents reads every line FileSink wrote to path back into a LogEvent, in the order they were
+// written. Unparseable lines are skipped rather than failing the whole read, since a run that was
+// killed mid-write can leave a truncated final line.
+func ReadEvents(path string) ([]*log.LogEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening log file %q: %w", path, err)
+	}
+	defer f.Close()
+
+	var events []*log.LogEvent
+	scanner := bufio.NewScanner(f)
+	// FileSink can write large single-line events (e.g. an archived artifact's manifest entry), so
+	// raise the scanner's line buffer past bufio's 64KB default.
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		event, ok := parseLogLine(scanner.Bytes())
+		if ok {
+			events = append(events, event)
+		}
+	}
+	if err := scanner.Err(); err != nil && err != io.EOF {
+		return events, fmt.Errorf("reading log file %q: %w", path, err)
+	}
+	return events, nil
+}
+
+func parseLogLine(line []byte) (*log.LogEvent, bool) {
+	var raw map[string]any
+	if err := json.Unmarshal(line, &raw); err != nil {
+		return nil, false
+	}
+
+	event := &log.LogEvent{Fields: make(map[string]any)}
+	if lvlStr := getStringField(raw, "level"); lvlStr != "" {
+		event.Level = levelFromString(lvlStr)
+	}
+	event.Message = getStringField(raw, "message")
+	if tsStr := getStringField(raw, "time"); tsStr != "" {
+		if ts, err := time.Parse(time.RFC3339Nano, tsStr); err == nil {
+			event.Timestamp = ts
+		}
+	}
+	for k, v := range raw {
+		if _, reserved := reservedLogFields[k]; !reserved {
+			event.Fields[k] = v
+		}
+	}
+	return event, true
+}
+
+// levelFromString is the inverse of levelToString.
+func levelFromString(s string) types.Level {
+	switch s {
+	case "debug":
+		return types.DebugLevel
+	case "info":
+		return types.InfoLevel
+	case "warn":
+		return types.WarnLevel
+	case "error":
+		return types.ErrorLevel
+	case "fatal":
+		return types.FatalLevel
+	default:
+		return types.InfoLevel
+	}
+}
@@ -0,0 +1,226 @@
+package sinks
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arnavsurve/dropstep/pkg/log"
+)
+
+// RotatingFileSinkConfig configures a RotatingFileSink. MaxSizeBytes, MaxAge, and MaxBackups are
+// each optional on their own (zero disables that particular limit), but at least one of
+// MaxSizeBytes or MaxAge should be set for rotation to ever happen.
+type RotatingFileSinkConfig struct {
+	// Path is the active log file's path. Rotated segments are written alongside it as
+	// "<Path>.<timestamp>" (and "<Path>.<timestamp>.gz" once Compress finishes).
+	Path string
+	// MaxSizeBytes rotates the active file once writing to it would exceed this size. Zero means
+	// no size-based rotation.
+	MaxSizeBytes int64
+	// MaxAge prunes rotated segments older than this once a rotation happens. Zero means rotated
+	// segments are never pruned by age.
+	MaxAge time.Duration
+	// MaxBackups caps the number of rotated segments kept, pruning the oldest first. Zero means
+	// no cap.
+	MaxBackups int
+	// Compress gzips each rotated segment in a background goroutine once it's closed out.
+	Compress bool
+}
+
+// RotatingFileSink is a FileSink that rotates its active file by size, pruning old rotated
+// segments by age and count, optionally gzip-compressing them in the background so rotation never
+// blocks the caller.
+type RotatingFileSink struct {
+	cfg RotatingFileSinkConfig
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// rotations tracks in-flight finishRotation goroutines, so Close can wait for the last
+	// rotation's compression and pruning to finish instead of racing the process exit.
+	rotations sync.WaitGroup
+}
+
+// NewRotatingFileSink opens (or creates) cfg.Path for append and returns a sink ready to write to
+// it, rotating according to cfg once it grows past MaxSizeBytes.
+func NewRotatingFileSink(cfg RotatingFileSinkConfig) (*RotatingFileSink, error) {
+	f, err := os.OpenFile(cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening rotating file sink %q: %w", cfg.Path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("stating rotating file sink %q: %w", cfg.Path, err)
+	}
+	return &RotatingFileSink{cfg: cfg, file: f, size: info.Size()}, nil
+}
+
+func (s *RotatingFileSink) Write(event *log.LogEvent) error {
+	logEntry := map[string]any{
+		"level":   levelToString(event.Level),
+		"time":    event.Timestamp,
+		"message": event.Message,
+	}
+	for k, v := range event.Fields {
+		logEntry[k] = v
+	}
+
+	data, err := json.Marshal(logEntry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal log event for rotating file sink: %w", err)
+	}
+	line := append(data, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cfg.MaxSizeBytes > 0 && s.size+int64(len(line)) > s.cfg.MaxSizeBytes {
+		if err := s.rotateLocked(); err != nil {
+			return fmt.Errorf("rotating file sink: %w", err)
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write to rotating file sink: %w", err)
+	}
+	return nil
+}
+
+// rotateLocked closes the active file, renames it aside with a timestamp suffix, and reopens
+// Path fresh. Compression and backup pruning happen afterward in the background so a caller's
+// Write never blocks on either. Callers must hold s.mu.
+func (s *RotatingFileSink) rotateLocked() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+
+	rotated := fmt.Sprintf("%s.%s", s.cfg.Path, time.Now().Format("20060102T150405.000000000"))
+	if err := os.Rename(s.cfg.Path, rotated); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.cfg.Path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	s.file = f
+	s.size = 0
+
+	s.rotations.Add(1)
+	go func() {
+		defer s.rotations.Done()
+		s.finishRotation(rotated)
+	}()
+	return nil
+}
+
+func (s *RotatingFileSink) finishRotation(rotated string) {
+	if s.cfg.Compress {
+		if compressed, err := gzipAndRemove(rotated); err != nil {
+			fmt.Fprintf(os.Stderr, "RotatingFileSink: failed to compress %q: %v\n", rotated, err)
+		} else {
+			rotated = compressed
+		}
+	}
+	s.pruneBackups()
+}
+
+// gzipAndRemove compresses src into "<src>.gz" and removes src once that succeeds, returning the
+// compressed file's path.
+func gzipAndRemove(src string) (string, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return "", err
+	}
+	defer in.Close()
+
+	dstPath := src + ".gz"
+	out, err := os.Create(dstPath)
+	if err != nil {
+		return "", err
+	}
+
+	gw := gzip.NewWriter(out)
+	if _, err := io.Copy(gw, in); err != nil {
+		gw.Close()
+		out.Close()
+		return "", err
+	}
+	if err := gw.Close(); err != nil {
+		out.Close()
+		return "", err
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Remove(src); err != nil {
+		return "", err
+	}
+	return dstPath, nil
+}
+
+// pruneBackups removes rotated segments of s.cfg.Path older than MaxAge and, beyond that, the
+// oldest segments past MaxBackups, newest first.
+func (s *RotatingFileSink) pruneBackups() {
+	if s.cfg.MaxAge <= 0 && s.cfg.MaxBackups <= 0 {
+		return
+	}
+
+	dir := filepath.Dir(s.cfg.Path)
+	base := filepath.Base(s.cfg.Path)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	type backup struct {
+		path    string
+		modTime time.Time
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == base || !strings.HasPrefix(name, base+".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, name), modTime: info.ModTime()})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.After(backups[j].modTime) })
+
+	now := time.Now()
+	kept := 0
+	for _, b := range backups {
+		expired := s.cfg.MaxAge > 0 && now.Sub(b.modTime) > s.cfg.MaxAge
+		tooMany := s.cfg.MaxBackups > 0 && kept >= s.cfg.MaxBackups
+		if expired || tooMany {
+			os.Remove(b.path)
+			continue
+		}
+		kept++
+	}
+}
+
+// Close closes the active file, first waiting for any rotation triggered by the final Write to
+// finish compressing and pruning, so a run's last rotated segment isn't left half-written.
+func (s *RotatingFileSink) Close() error {
+	s.rotations.Wait()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
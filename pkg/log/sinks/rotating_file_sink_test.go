@@ -0,0 +1,105 @@
+package sinks_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/arnavsurve/dropstep/pkg/log/sinks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeN(t *testing.T, sink *sinks.RotatingFileSink, n int, msg string) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		require.NoError(t, sink.Write(&log.LogEvent{Message: msg, Timestamp: time.Now()}))
+	}
+}
+
+func TestRotatingFileSink_RotatesOnceMaxSizeExceeded(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "run.log")
+
+	sink, err := sinks.NewRotatingFileSink(sinks.RotatingFileSinkConfig{
+		Path:         path,
+		MaxSizeBytes: 120,
+	})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	writeN(t, sink, 10, "a moderately sized log line to force rollover")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var rotated, active int
+	for _, e := range entries {
+		if e.Name() == "run.log" {
+			active++
+		} else {
+			rotated++
+		}
+	}
+	assert.Equal(t, 1, active)
+	assert.Greater(t, rotated, 0, "expected at least one rotated segment once MaxSizeBytes was exceeded")
+}
+
+func TestRotatingFileSink_CompressesRotatedSegments(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "run.log")
+
+	sink, err := sinks.NewRotatingFileSink(sinks.RotatingFileSinkConfig{
+		Path:         path,
+		MaxSizeBytes: 80,
+		Compress:     true,
+	})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	writeN(t, sink, 10, "a moderately sized log line to force rollover and compression")
+
+	require.Eventually(t, func() bool {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return false
+		}
+		for _, e := range entries {
+			if filepath.Ext(e.Name()) == ".gz" {
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond, "expected a rotated segment to be gzip-compressed in the background")
+}
+
+func TestRotatingFileSink_PrunesBackupsByMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "run.log")
+
+	sink, err := sinks.NewRotatingFileSink(sinks.RotatingFileSinkConfig{
+		Path:         path,
+		MaxSizeBytes: 40,
+		MaxBackups:   1,
+	})
+	require.NoError(t, err)
+	defer sink.Close()
+
+	writeN(t, sink, 30, "line")
+
+	require.Eventually(t, func() bool {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return false
+		}
+		rotated := 0
+		for _, e := range entries {
+			if e.Name() != "run.log" {
+				rotated++
+			}
+		}
+		return rotated <= 1
+	}, 2*time.Second, 10*time.Millisecond, "expected pruning to keep at most MaxBackups rotated segments")
+}
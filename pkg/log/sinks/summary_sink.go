@@ -0,0 +1,54 @@
+package sinks
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// SummarySink captures WARN/ERROR/FATAL events, bucketed by the event's step_id field, for
+// inclusion in a run's Markdown step summary (see pkg/summary). It does no Markdown rendering
+// itself; core.WorkflowEngine drains Issues(stepID) into each step's summary.StepEntry once the
+// step completes.
+type SummarySink struct {
+	mu     sync.Mutex
+	issues map[string][]string
+}
+
+func NewSummarySink() *SummarySink {
+	return &SummarySink{issues: make(map[string][]string)}
+}
+
+func (s *SummarySink) Write(event *log.LogEvent) error {
+	if event.Level < types.WarnLevel {
+		return nil
+	}
+
+	msg := event.Message
+	if msg == "" {
+		msg = getStringField(event.Fields, "error")
+	}
+	line := fmt.Sprintf("**%s:** %s", strings.ToUpper(levelToString(event.Level)), msg)
+
+	stepID := getStringField(event.Fields, "step_id")
+	s.mu.Lock()
+	s.issues[stepID] = append(s.issues[stepID], line)
+	s.mu.Unlock()
+	return nil
+}
+
+// Issues returns and clears the issue lines captured for stepID, in the order they were recorded.
+func (s *SummarySink) Issues(stepID string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issues := s.issues[stepID]
+	delete(s.issues, stepID)
+	return issues
+}
+
+func (s *SummarySink) Close() error {
+	return nil
+}
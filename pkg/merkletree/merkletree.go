@@ -0,0 +1,81 @@
+// Package merkletree builds the Merkle tree of a remote-execution step's input root and computes
+// the resulting Action digest, following the same shape as Bazel Remote Execution API v2: the
+// tree is built bottom-up from leaf file digests so that identical input sets always produce the
+// same root digest regardless of the order files were discovered or uploaded in, and an Action
+// combines that input root digest with the command, environment, and target platform into a
+// single digest a worker pool can use as a cache key.
+package merkletree
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/arnavsurve/dropstep/pkg/cas"
+)
+
+// FileNode is a single file in a step's input root.
+type FileNode struct {
+	Path         string
+	Digest       cas.Digest
+	IsExecutable bool
+}
+
+// BuildInputRoot computes the digest of the Merkle tree rooted at files. Files are sorted by path
+// before hashing so the result doesn't depend on discovery order.
+func BuildInputRoot(files []FileNode) (cas.Digest, error) {
+	sorted := make([]FileNode, len(files))
+	copy(sorted, files)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	type entry struct {
+		Path         string `json:"path"`
+		Hash         string `json:"hash"`
+		SizeBytes    int64  `json:"size_bytes"`
+		IsExecutable bool   `json:"is_executable,omitempty"`
+	}
+
+	entries := make([]entry, len(sorted))
+	for i, f := range sorted {
+		entries[i] = entry{Path: f.Path, Hash: f.Digest.Hash, SizeBytes: f.Digest.SizeBytes, IsExecutable: f.IsExecutable}
+	}
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return cas.Digest{}, fmt.Errorf("marshaling input root: %w", err)
+	}
+
+	return cas.DigestOf(data), nil
+}
+
+// ActionDigest computes the digest of a remote Action: a command, its environment, the target
+// platform properties a worker must match, the workflow-relative paths it's expected to produce
+// (outputPaths — REv2's output_files and output_directories combined, since both just narrow what
+// a worker must return and neither affects how the command itself runs), and the digest of its
+// input root. Map fields (env/platform) are marshaled via encoding/json, which sorts map keys, so
+// the digest is stable regardless of a caller's map insertion order.
+func ActionDigest(command []string, env map[string]string, platform map[string]string, outputPaths []string, inputRootDigest cas.Digest) (cas.Digest, error) {
+	action := struct {
+		Command         []string          `json:"command"`
+		Env             map[string]string `json:"env,omitempty"`
+		Platform        map[string]string `json:"platform,omitempty"`
+		OutputPaths     []string          `json:"output_paths,omitempty"`
+		InputRootDigest string            `json:"input_root_digest"`
+	}{
+		Command:         command,
+		Env:             env,
+		Platform:        platform,
+		OutputPaths:     outputPaths,
+		InputRootDigest: inputRootDigest.Hash,
+	}
+
+	data, err := json.Marshal(action)
+	if err != nil {
+		return cas.Digest{}, fmt.Errorf("marshaling action: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	return cas.Digest{Hash: hex.EncodeToString(sum[:]), SizeBytes: int64(len(data))}, nil
+}
@@ -0,0 +1,75 @@
+package merkletree
+
+import (
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/cas"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildInputRoot_StableAcrossOrdering(t *testing.T) {
+	a := []FileNode{
+		{Path: "b.txt", Digest: cas.DigestOf([]byte("b"))},
+		{Path: "a.txt", Digest: cas.DigestOf([]byte("a"))},
+	}
+	b := []FileNode{
+		{Path: "a.txt", Digest: cas.DigestOf([]byte("a"))},
+		{Path: "b.txt", Digest: cas.DigestOf([]byte("b"))},
+	}
+
+	digestA, err := BuildInputRoot(a)
+	require.NoError(t, err)
+	digestB, err := BuildInputRoot(b)
+	require.NoError(t, err)
+
+	assert.Equal(t, digestA, digestB)
+}
+
+func TestBuildInputRoot_ChangesWithContent(t *testing.T) {
+	digest1, err := BuildInputRoot([]FileNode{{Path: "a.txt", Digest: cas.DigestOf([]byte("v1"))}})
+	require.NoError(t, err)
+	digest2, err := BuildInputRoot([]FileNode{{Path: "a.txt", Digest: cas.DigestOf([]byte("v2"))}})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, digest1, digest2)
+}
+
+func TestActionDigest_StableAcrossMapKeyOrdering(t *testing.T) {
+	inputRoot, err := BuildInputRoot(nil)
+	require.NoError(t, err)
+
+	digestA, err := ActionDigest([]string{"python3", "run.py"},
+		map[string]string{"A": "1", "B": "2"}, map[string]string{"os": "linux"}, nil, inputRoot)
+	require.NoError(t, err)
+
+	digestB, err := ActionDigest([]string{"python3", "run.py"},
+		map[string]string{"B": "2", "A": "1"}, map[string]string{"os": "linux"}, nil, inputRoot)
+	require.NoError(t, err)
+
+	assert.Equal(t, digestA, digestB)
+}
+
+func TestActionDigest_ChangesWithCommand(t *testing.T) {
+	inputRoot, err := BuildInputRoot(nil)
+	require.NoError(t, err)
+
+	digest1, err := ActionDigest([]string{"python3", "run.py"}, nil, nil, nil, inputRoot)
+	require.NoError(t, err)
+	digest2, err := ActionDigest([]string{"python3", "other.py"}, nil, nil, nil, inputRoot)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, digest1, digest2)
+}
+
+func TestActionDigest_ChangesWithOutputPaths(t *testing.T) {
+	inputRoot, err := BuildInputRoot(nil)
+	require.NoError(t, err)
+
+	digest1, err := ActionDigest([]string{"python3", "run.py"}, nil, nil, []string{"out.txt"}, inputRoot)
+	require.NoError(t, err)
+	digest2, err := ActionDigest([]string{"python3", "run.py"}, nil, nil, []string{"other.txt"}, inputRoot)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, digest1, digest2)
+}
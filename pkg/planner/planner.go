@@ -0,0 +1,98 @@
+// Package planner selects, for a given trigger event, the ordered subset of a workflow's steps
+// that should run, so the same dropstep.yml can serve a scheduled run, a one-shot CLI invocation,
+// and an incoming webhook without duplicating YAML. It also flattens an event's JSON payload into
+// VarContext entries so `{{ event.<path> }}` resolves through the existing template engine.
+package planner
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/arnavsurve/dropstep/pkg/core"
+)
+
+// Event describes what triggered a run.
+type Event struct {
+	// Trigger is the event name, e.g. "schedule", "webhook", or "manual".
+	Trigger string
+	// Payload is the event's JSON body (e.g. a webhook's request body), if any.
+	Payload map[string]any
+}
+
+// Plan is the ordered subset of a workflow's steps selected for an Event.
+type Plan struct {
+	Trigger string
+	Steps   []core.Step
+	// EventVars holds Payload flattened into "event.<path>" keys, ready to merge into a
+	// VarContext so `{{ event.<path> }}` resolves through ResolveStepVariables like any other
+	// variable.
+	EventVars core.VarContext
+}
+
+// Plan computes the ordered subset of wf.Steps that should run for event. wf.On, if non-empty,
+// restricts which triggers the workflow accepts at all; a step's own On further restricts it to a
+// subset of those triggers, defaulting to every trigger the workflow accepts.
+func Plan(wf *core.Workflow, event Event) (*Plan, error) {
+	if event.Trigger == "" {
+		return nil, fmt.Errorf("event trigger must not be empty")
+	}
+	if len(wf.On) > 0 && !contains(wf.On, event.Trigger) {
+		return nil, fmt.Errorf("workflow %q does not accept trigger %q (accepts: %v)", wf.Name, event.Trigger, wf.On)
+	}
+
+	var steps []core.Step
+	for _, step := range wf.Steps {
+		if len(step.On) > 0 && !contains(step.On, event.Trigger) {
+			continue
+		}
+		steps = append(steps, step)
+	}
+
+	eventVars := make(core.VarContext)
+	flatten("event", event.Payload, eventVars)
+
+	return &Plan{Trigger: event.Trigger, Steps: steps, EventVars: eventVars}, nil
+}
+
+// LoadPayload parses a JSON event payload (e.g. the contents of a --payload file). Empty data
+// yields a nil payload rather than an error, since most triggers (schedule, manual) have none.
+func LoadPayload(data []byte) (map[string]any, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, fmt.Errorf("parsing event payload JSON: %w", err)
+	}
+	return payload, nil
+}
+
+func contains(list []string, val string) bool {
+	for _, v := range list {
+		if v == val {
+			return true
+		}
+	}
+	return false
+}
+
+// flatten walks a JSON-decoded value and writes one VarContext entry per leaf, keyed by its
+// dotted path prefixed with prefix (e.g. flatten("event", map[string]any{"user": map[string]any{"id": 5}}, out)
+// sets out["event.user.id"] = "5").
+func flatten(prefix string, value any, out core.VarContext) {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, val := range v {
+			flatten(prefix+"."+k, val, out)
+		}
+	case []any:
+		for i, val := range v {
+			flatten(fmt.Sprintf("%s.%d", prefix, i), val, out)
+		}
+	case nil:
+		// No entry for an explicit null; a reference to it resolves as undefined, same as any
+		// other missing variable.
+	default:
+		out[prefix] = fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,131 @@
+// Package pywheels manages a content-addressed, hash-verified local cache of Python wheels for
+// the browser_agent's venv (see pkg/steprunner/runners/browseragent), so its setup can install
+// reproducibly from a pinned lockfile instead of resolving against PyPI on every run, and can run
+// fully offline once the cache is warm.
+package pywheels
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// LockEntry pins a single package to an exact version, wheel source, and expected hash.
+type LockEntry struct {
+	Package  string `json:"package"`
+	Version  string `json:"version"`
+	SHA256   string `json:"sha256"`
+	WheelURL string `json:"wheel_url"`
+}
+
+// Lockfile is the embedded requirements.lock.json format: one LockEntry per pinned package.
+type Lockfile struct {
+	Packages []LockEntry `json:"packages"`
+}
+
+// ParseLockfile parses the JSON contents of a requirements.lock.json.
+func ParseLockfile(data []byte) (*Lockfile, error) {
+	var lock Lockfile
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("parsing wheel lockfile: %w", err)
+	}
+	return &lock, nil
+}
+
+// WheelPath returns the path a locked entry's wheel is cached at: <cacheRoot>/<sha256>/<filename>,
+// where filename is the last path segment of its WheelURL.
+func WheelPath(cacheRoot string, e LockEntry) string {
+	return filepath.Join(cacheRoot, e.SHA256, filepath.Base(e.WheelURL))
+}
+
+// Ensure makes every entry in lock present and hash-verified under cacheRoot. Already-cached
+// wheels (identified by their content-addressed path) are trusted without re-hashing; anything
+// missing is downloaded and verified, unless offline is true, in which case a missing wheel is an
+// error rather than a network fetch.
+func Ensure(ctx context.Context, cacheRoot string, lock *Lockfile, offline bool, logger types.Logger) error {
+	for _, e := range lock.Packages {
+		path := WheelPath(cacheRoot, e)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+
+		if offline {
+			return fmt.Errorf("offline mode: wheel for %s==%s (sha256 %s) is not cached at %q", e.Package, e.Version, e.SHA256, path)
+		}
+
+		logger.Info().Msgf("Downloading wheel %s==%s", e.Package, e.Version)
+		if err := downloadAndVerify(ctx, e, path); err != nil {
+			return fmt.Errorf("fetching wheel %s==%s: %w", e.Package, e.Version, err)
+		}
+	}
+	return nil
+}
+
+// downloadAndVerify fetches a locked entry's wheel to a temp file alongside destPath, verifies
+// its SHA-256 against the lock entry, and only then renames it into place, so a failed or
+// tampered download never leaves a wheel at the expected cache path.
+func downloadAndVerify(ctx context.Context, e LockEntry, destPath string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.WheelURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s fetching %s", resp.Status, e.WheelURL)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+		return err
+	}
+
+	tmpPath := destPath + ".download"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+
+	h := sha256.New()
+	_, copyErr := io.Copy(io.MultiWriter(f, h), resp.Body)
+	closeErr := f.Close()
+	if copyErr != nil {
+		os.Remove(tmpPath)
+		return copyErr
+	}
+	if closeErr != nil {
+		os.Remove(tmpPath)
+		return closeErr
+	}
+
+	sum := hex.EncodeToString(h.Sum(nil))
+	if sum != e.SHA256 {
+		os.Remove(tmpPath)
+		return fmt.Errorf("sha256 mismatch: got %s, want %s", sum, e.SHA256)
+	}
+
+	return os.Rename(tmpPath, destPath)
+}
+
+// WriteLockRequirements writes a pip requirements file pinning every entry in lock to its exact
+// version and hash, suitable for `pip install --no-index --find-links=<cacheRoot>
+// --require-hashes -r <file>`.
+func WriteLockRequirements(w io.Writer, lock *Lockfile) error {
+	for _, e := range lock.Packages {
+		if _, err := fmt.Fprintf(w, "%s==%s --hash=sha256:%s\n", e.Package, e.Version, e.SHA256); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+// Package secretmount stages a step's secret values to private, ephemeral files instead of
+// interpolating them into command argv/env text or prompt strings, so a process listing (ps), a
+// crash dump, or the redactor's own blind spots can't expose the plaintext value — at most a file
+// path. Staged files live under a per-run directory (tmpfs-backed via $XDG_RUNTIME_DIR on Linux
+// where available) and are zeroed and unlinked by the returned cleanup func.
+package secretmount
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+	"github.com/google/uuid"
+)
+
+// Mounted is the result of staging a step's secret mounts to disk.
+type Mounted struct {
+	// Env holds one "DROPSTEP_SECRET_<NAME>=<path>" entry per mount, ready to append to a
+	// subprocess's environment.
+	Env []string
+	// Paths maps each mount's Name to the file path holding its staged value, for callers (like
+	// the browser_agent prompt's `secretPath` helper) that need the path directly rather than via
+	// env.
+	Paths map[string]string
+}
+
+// Stage writes each mount's resolved value (looked up from values by InputRef) to a private file
+// and returns the resulting env entries and paths. The returned cleanup func zeroes and removes
+// the staged files and must be called exactly once, whether or not the step succeeds.
+func Stage(mounts []types.SecretMount, values map[string]string) (*Mounted, func(), error) {
+	if len(mounts) == 0 {
+		return &Mounted{}, func() {}, nil
+	}
+
+	dir, err := stageDir()
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating secret mount directory: %w", err)
+	}
+	cleanup := func() { cleanupDir(dir, mounts) }
+
+	mounted := &Mounted{Paths: make(map[string]string, len(mounts))}
+	for _, m := range mounts {
+		val, ok := values[m.InputRef]
+		if !ok {
+			cleanup()
+			return nil, nil, fmt.Errorf("secret mount %q references undefined input %q", m.Name, m.InputRef)
+		}
+
+		path := m.MountPath
+		if path == "" {
+			path = filepath.Join(dir, m.Name)
+		}
+		if err := os.WriteFile(path, []byte(val), 0o600); err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("writing secret mount %q: %w", m.Name, err)
+		}
+
+		mounted.Paths[m.Name] = path
+		mounted.Env = append(mounted.Env, fmt.Sprintf("DROPSTEP_SECRET_%s=%s", m.Name, path))
+	}
+
+	return mounted, cleanup, nil
+}
+
+// stageDir creates a private, per-run directory to hold staged secret files: under
+// $XDG_RUNTIME_DIR on Linux, which is tmpfs-backed on most distros, falling back to a dedicated
+// directory under os.TempDir() elsewhere or when unset.
+func stageDir() (string, error) {
+	base := os.TempDir()
+	if runtime.GOOS == "linux" {
+		if xdg := os.Getenv("XDG_RUNTIME_DIR"); xdg != "" {
+			base = xdg
+		}
+	}
+	dir := filepath.Join(base, "dropstep-secrets-"+uuid.New().String())
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// cleanupDir zeroes each staged file's contents before unlinking it, then removes the staging
+// directory, so a secret's bytes don't linger in a deleted-but-still-allocated inode.
+func cleanupDir(dir string, mounts []types.SecretMount) {
+	for _, m := range mounts {
+		path := m.MountPath
+		if path == "" {
+			path = filepath.Join(dir, m.Name)
+		}
+		if info, err := os.Stat(path); err == nil {
+			_ = os.WriteFile(path, make([]byte, info.Size()), 0o600)
+		}
+		_ = os.Remove(path)
+	}
+	_ = os.RemoveAll(dir)
+}
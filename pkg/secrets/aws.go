@@ -0,0 +1,58 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// AWSConfig points at an AWS Secrets Manager region. Credentials are resolved through the
+// standard AWS SDK credential chain (env vars, shared config, instance role, etc.).
+type AWSConfig struct {
+	Region string `yaml:"region"`
+}
+
+// AWSSecretsManagerProvider resolves secret refs of the form "<secret-name>" (the whole secret
+// string) or "<secret-name>#<json-key>" (a single field of a JSON secret string).
+type AWSSecretsManagerProvider struct {
+	client *secretsmanager.Client
+}
+
+func NewAWSSecretsManagerProvider(cfg AWSConfig) (*AWSSecretsManagerProvider, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background(), awsconfig.WithRegion(cfg.Region))
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config for region %q: %w", cfg.Region, err)
+	}
+	return &AWSSecretsManagerProvider{
+		client: secretsmanager.NewFromConfig(awsCfg),
+	}, nil
+}
+
+func (p *AWSSecretsManagerProvider) Lookup(ctx context.Context, ref string) (string, error) {
+	name, field, hasField := strings.Cut(ref, "#")
+
+	out, err := p.client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: &name})
+	if err != nil {
+		return "", fmt.Errorf("fetching AWS secret %q: %w", name, err)
+	}
+	if out.SecretString == nil {
+		return "", fmt.Errorf("AWS secret %q has no string value", name)
+	}
+	if !hasField {
+		return *out.SecretString, nil
+	}
+
+	var parsed map[string]any
+	if err := json.Unmarshal([]byte(*out.SecretString), &parsed); err != nil {
+		return "", fmt.Errorf("AWS secret %q is not valid JSON, cannot extract field %q: %w", name, field, err)
+	}
+	val, ok := parsed[field]
+	if !ok {
+		return "", fmt.Errorf("AWS secret %q has no field %q", name, field)
+	}
+	return fmt.Sprintf("%v", val), nil
+}
@@ -0,0 +1,36 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joho/godotenv"
+)
+
+// FileConfig points at a local .env-style file (KEY=value per line).
+type FileConfig struct {
+	Path string `yaml:"path"`
+}
+
+// FileProvider resolves secret refs as keys into a .env-style file, loaded fresh on every Lookup
+// so edits to the file take effect without restarting the run.
+type FileProvider struct {
+	cfg FileConfig
+}
+
+func NewFileProvider(cfg FileConfig) *FileProvider {
+	return &FileProvider{cfg: cfg}
+}
+
+func (p *FileProvider) Lookup(_ context.Context, ref string) (string, error) {
+	values, err := godotenv.Read(p.cfg.Path)
+	if err != nil {
+		return "", fmt.Errorf("reading secrets file %q: %w", p.cfg.Path, err)
+	}
+
+	val, ok := values[ref]
+	if !ok {
+		return "", fmt.Errorf("secrets file %q has no key %q", p.cfg.Path, ref)
+	}
+	return val, nil
+}
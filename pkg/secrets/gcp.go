@@ -0,0 +1,62 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+)
+
+// GCPConfig points at a GCP Secret Manager project. Credentials are resolved through the standard
+// Google Cloud client library credential chain (GOOGLE_APPLICATION_CREDENTIALS, the metadata
+// server, etc.), mirroring how AWSSecretsManagerProvider defers to the AWS SDK's own chain.
+type GCPConfig struct {
+	// Project is the GCP project ID secret names are resolved relative to.
+	Project string `yaml:"project"`
+}
+
+// GCPSecretsManagerProvider resolves secret refs of the form "<secret-name>" (the latest version
+// of the whole secret payload) or "<secret-name>#<json-key>" (a single field of a JSON payload),
+// the same ref shape AWSSecretsManagerProvider uses.
+type GCPSecretsManagerProvider struct {
+	cfg    GCPConfig
+	client *secretmanager.Client
+}
+
+func NewGCPSecretsManagerProvider(ctx context.Context, cfg GCPConfig) (*GCPSecretsManagerProvider, error) {
+	client, err := secretmanager.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating GCP Secret Manager client: %w", err)
+	}
+	return &GCPSecretsManagerProvider{cfg: cfg, client: client}, nil
+}
+
+func (p *GCPSecretsManagerProvider) Lookup(ctx context.Context, ref string) (string, error) {
+	name, field, hasField := strings.Cut(ref, "#")
+
+	req := &secretmanagerpb.AccessSecretVersionRequest{
+		Name: fmt.Sprintf("projects/%s/secrets/%s/versions/latest", p.cfg.Project, name),
+	}
+	result, err := p.client.AccessSecretVersion(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("fetching GCP secret %q: %w", name, err)
+	}
+	payload := string(result.Payload.Data)
+
+	if !hasField {
+		return payload, nil
+	}
+
+	var parsed map[string]any
+	if err := json.Unmarshal([]byte(payload), &parsed); err != nil {
+		return "", fmt.Errorf("GCP secret %q is not valid JSON, cannot extract field %q: %w", name, field, err)
+	}
+	val, ok := parsed[field]
+	if !ok {
+		return "", fmt.Errorf("GCP secret %q has no field %q", name, field)
+	}
+	return fmt.Sprintf("%v", val), nil
+}
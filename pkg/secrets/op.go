@@ -0,0 +1,50 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// OpConfig selects the 1Password CLI binary used to resolve secret refs. Authentication (a
+// signed-in session, or OP_SERVICE_ACCOUNT_TOKEN in the environment) is left entirely to the `op`
+// binary itself, the same way VaultProvider leaves Vault auth to an env var it reads at lookup
+// time rather than managing a session.
+type OpConfig struct {
+	// Binary overrides the `op` executable looked up on PATH; empty means "op".
+	Binary string `yaml:"binary,omitempty"`
+}
+
+// OpProvider resolves secret refs by shelling out to the 1Password CLI's "op read" command. ref
+// is a 1Password secret reference URI, e.g. "op://vault/item/field".
+type OpProvider struct {
+	cfg OpConfig
+}
+
+func NewOpProvider(cfg OpConfig) *OpProvider {
+	return &OpProvider{cfg: cfg}
+}
+
+func (p *OpProvider) Lookup(ctx context.Context, ref string) (string, error) {
+	binary := p.cfg.Binary
+	if binary == "" {
+		binary = "op"
+	}
+	bin, err := exec.LookPath(binary)
+	if err != nil {
+		return "", fmt.Errorf("secret provider type 'op' requires the %q command in PATH: %w", binary, err)
+	}
+
+	// #nosec G204 -- ref is a workflow-authored 1Password reference URI, not arbitrary user input.
+	cmd := exec.CommandContext(ctx, bin, "read", ref)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("op read %q failed: %w: %s", ref, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
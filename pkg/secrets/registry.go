@@ -0,0 +1,141 @@
+package secrets
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+)
+
+// defaultCacheCapacity bounds the per-run secret cache. A workflow referencing more distinct
+// (provider, ref) pairs than this in a single run will evict its least-recently-used entries,
+// trading a few extra backend fetches for a fixed memory footprint.
+const defaultCacheCapacity = 256
+
+// CacheStats reports how many secret lookups a Registry served from cache versus the backend,
+// for inclusion in a run's debug output.
+type CacheStats struct {
+	Hits   int
+	Misses int
+}
+
+// Registry dispatches `secret.<provider>.<ref>` lookups to the named Provider and caches results
+// for the lifetime of a single run, so a secret referenced by many steps triggers exactly one
+// backend fetch.
+type Registry struct {
+	providers map[string]Provider
+	maskFunc  func(string)
+
+	mu    sync.Mutex
+	cache *lruCache
+	stats CacheStats
+}
+
+// NewRegistry builds a Registry from a workflow's `secrets:` block. maskFunc, if non-nil, is
+// called with every secret value the first time it's fetched from a backend, so it can be
+// registered with the run's log redactor (see pkg/log.Router.AddSecretMask).
+func NewRegistry(configs []ProviderConfig, maskFunc func(string)) (*Registry, error) {
+	providers := make(map[string]Provider, len(configs))
+	for _, cfg := range configs {
+		provider, err := NewProvider(cfg)
+		if err != nil {
+			return nil, err
+		}
+		providers[cfg.Name] = provider
+	}
+	return &Registry{
+		providers: providers,
+		maskFunc:  maskFunc,
+		cache:     newLRUCache(defaultCacheCapacity),
+	}, nil
+}
+
+// Resolve looks up ref against the named provider, serving from the per-run cache when possible.
+func (r *Registry) Resolve(ctx context.Context, providerName, ref string) (string, error) {
+	key := providerName + "\x00" + ref
+
+	r.mu.Lock()
+	if val, ok := r.cache.get(key); ok {
+		r.stats.Hits++
+		r.mu.Unlock()
+		return val, nil
+	}
+	r.mu.Unlock()
+
+	provider, ok := r.providers[providerName]
+	if !ok {
+		return "", fmt.Errorf("secret provider %q is not defined in 'secrets:'", providerName)
+	}
+
+	val, err := provider.Lookup(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("secret provider %q: %w", providerName, err)
+	}
+
+	r.mu.Lock()
+	r.cache.put(key, val)
+	r.stats.Misses++
+	r.mu.Unlock()
+
+	if r.maskFunc != nil {
+		r.maskFunc(val)
+	}
+
+	return val, nil
+}
+
+// Stats returns the number of cache hits and misses served so far.
+func (r *Registry) Stats() CacheStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stats
+}
+
+// lruCache is a small fixed-capacity least-recently-used cache. It is not safe for concurrent use
+// on its own; Registry guards it with mu.
+type lruCache struct {
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // front = most recently used
+}
+
+type lruEntry struct {
+	key string
+	val string
+}
+
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *lruCache) get(key string) (string, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).val, true
+}
+
+func (c *lruCache) put(key, val string) {
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruEntry).val = val
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	elem := c.order.PushFront(&lruEntry{key: key, val: val})
+	c.entries[key] = elem
+
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(*lruEntry).key)
+		}
+	}
+}
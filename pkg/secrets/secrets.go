@@ -0,0 +1,69 @@
+// Package secrets resolves `{{ secret.<provider>.<ref> }}` references in a varfile against
+// external secret stores (HashiCorp Vault, AWS Secrets Manager, GCP Secret Manager, 1Password, or
+// a local .env-style file) instead of requiring the secret's plaintext value to live in the
+// varfile itself.
+package secrets
+
+import (
+	"context"
+	"fmt"
+)
+
+// Provider fetches a single secret value from a backing store.
+type Provider interface {
+	// Lookup resolves ref (a provider-specific reference, e.g. a Vault path plus field, or an
+	// AWS Secrets Manager secret name) to its plaintext value.
+	Lookup(ctx context.Context, ref string) (string, error)
+}
+
+// ProviderConfig configures a single named secret provider, declared under a workflow's
+// top-level `secrets:` block (mirroring `providers:`). Exactly one of Vault, AWS, GCP, Op, or File
+// should be set, matching Type.
+type ProviderConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+
+	Vault *VaultConfig `yaml:"vault,omitempty"`
+	AWS   *AWSConfig   `yaml:"aws,omitempty"`
+	GCP   *GCPConfig   `yaml:"gcp,omitempty"`
+	Op    *OpConfig    `yaml:"op,omitempty"`
+	File  *FileConfig  `yaml:"file,omitempty"`
+}
+
+// NewProvider builds the Provider implementation named by cfg.Type.
+func NewProvider(cfg ProviderConfig) (Provider, error) {
+	switch cfg.Type {
+	case "vault":
+		if cfg.Vault == nil {
+			return nil, fmt.Errorf("secret provider %q: type 'vault' requires a 'vault' block", cfg.Name)
+		}
+		return NewVaultProvider(*cfg.Vault), nil
+	case "aws_secrets_manager":
+		if cfg.AWS == nil {
+			return nil, fmt.Errorf("secret provider %q: type 'aws_secrets_manager' requires an 'aws' block", cfg.Name)
+		}
+		return NewAWSSecretsManagerProvider(*cfg.AWS)
+	case "gcp_secrets_manager":
+		if cfg.GCP == nil {
+			return nil, fmt.Errorf("secret provider %q: type 'gcp_secrets_manager' requires a 'gcp' block", cfg.Name)
+		}
+		// NewRegistry (this provider's only caller) isn't handed a context of its own, since
+		// building a run's secret registry happens once up front, the same moment providers like
+		// NewVaultProvider construct their own long-lived http.Client; context.Background() here
+		// only bounds the GCP client's dial, not any later Lookup call.
+		return NewGCPSecretsManagerProvider(context.Background(), *cfg.GCP)
+	case "op":
+		op := OpConfig{}
+		if cfg.Op != nil {
+			op = *cfg.Op
+		}
+		return NewOpProvider(op), nil
+	case "file":
+		if cfg.File == nil {
+			return nil, fmt.Errorf("secret provider %q: type 'file' requires a 'file' block", cfg.Name)
+		}
+		return NewFileProvider(*cfg.File), nil
+	default:
+		return nil, fmt.Errorf("secret provider %q: unknown type %q", cfg.Name, cfg.Type)
+	}
+}
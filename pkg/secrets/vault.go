@@ -0,0 +1,84 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+const defaultVaultTimeout = 10 * time.Second
+
+// VaultConfig points at a HashiCorp Vault KV v2 mount.
+type VaultConfig struct {
+	// Address is the Vault server's base URL, e.g. "https://vault.internal:8200".
+	Address string `yaml:"address"`
+	// TokenEnv names the environment variable holding the Vault token to authenticate with.
+	TokenEnv string `yaml:"token_env"`
+	// Namespace is an optional Vault Enterprise namespace.
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+// VaultProvider resolves secret refs of the form "<kv-v2-data-path>#<field>", e.g.
+// "kv/data/prod/openai#api_key", against a Vault server's KV v2 HTTP API.
+type VaultProvider struct {
+	cfg    VaultConfig
+	client *http.Client
+}
+
+func NewVaultProvider(cfg VaultConfig) *VaultProvider {
+	return &VaultProvider{
+		cfg:    cfg,
+		client: &http.Client{Timeout: defaultVaultTimeout},
+	}
+}
+
+func (p *VaultProvider) Lookup(ctx context.Context, ref string) (string, error) {
+	path, field, found := strings.Cut(ref, "#")
+	if !found {
+		return "", fmt.Errorf("vault secret ref %q must be in the form <kv-v2-data-path>#<field>", ref)
+	}
+
+	token := os.Getenv(p.cfg.TokenEnv)
+	if token == "" {
+		return "", fmt.Errorf("vault token env var %q is not set", p.cfg.TokenEnv)
+	}
+
+	url := strings.TrimSuffix(p.cfg.Address, "/") + "/v1/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("building vault request for %q: %w", path, err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if p.cfg.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.cfg.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request for %q failed: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault request for %q returned status %d", path, resp.StatusCode)
+	}
+
+	var parsed struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&parsed); err != nil {
+		return "", fmt.Errorf("decoding vault response for %q: %w", path, err)
+	}
+
+	val, ok := parsed.Data.Data[field]
+	if !ok {
+		return "", fmt.Errorf("vault secret %q has no field %q", path, field)
+	}
+	return fmt.Sprintf("%v", val), nil
+}
@@ -11,6 +11,20 @@ type Redactor struct {
 	Secrets []string
 }
 
+// alwaysRedactFieldNames holds field/header names whose value is replaced unconditionally,
+// regardless of whether it matches a known secret in Secrets. This covers values that were never
+// routed through a `{{ secret.* }}` template or a Secret: true input — e.g. a hardcoded
+// Authorization header in a workflow file — and so would otherwise slip past value-based
+// matching. Matched case-insensitively against a field/header name.
+var alwaysRedactFieldNames = map[string]bool{
+	"authorization": true,
+	"api_key":       true,
+	"apikey":        true,
+	"x-api-key":     true,
+	"password":      true,
+	"secret":        true,
+}
+
 func NewRedactor(inputs []core.Input, varCtx core.VarContext) *Redactor {
 	var secretValues []string
 	for _, input := range inputs {
@@ -46,3 +60,13 @@ func (r *Redactor) Redact(s string) string {
 	}
 	return s
 }
+
+// RedactField returns "********" if key names a field dropstep always treats as sensitive (see
+// alwaysRedactFieldNames), regardless of whether val matches a tracked secret; otherwise it falls
+// back to Redact(val).
+func (r *Redactor) RedactField(key, val string) string {
+	if alwaysRedactFieldNames[strings.ToLower(key)] {
+		return "********"
+	}
+	return r.Redact(val)
+}
@@ -206,3 +206,45 @@ func TestNewRedactor(t *testing.T) {
 		})
 	}
 }
+
+func TestRedactor_RedactField(t *testing.T) {
+	r := &security.Redactor{Secrets: []string{"trackedsecret"}}
+
+	tests := []struct {
+		name string
+		key  string
+		val  string
+		want string
+	}{
+		{
+			name: "tracked secret value redacted regardless of field name",
+			key:  "x-custom",
+			val:  "trackedsecret",
+			want: "********",
+		},
+		{
+			name: "untracked value passed through for a non-sensitive field",
+			key:  "x-custom",
+			val:  "plainvalue",
+			want: "plainvalue",
+		},
+		{
+			name: "authorization header always redacted, even when untracked",
+			key:  "Authorization",
+			val:  "Bearer untracked-token",
+			want: "********",
+		},
+		{
+			name: "field name match is case-insensitive",
+			key:  "API_KEY",
+			val:  "untracked-key",
+			want: "********",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, r.RedactField(tt.key, tt.val))
+		})
+	}
+}
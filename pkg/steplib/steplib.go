@@ -0,0 +1,245 @@
+// Package steplib resolves a step's `uses:` against an external, versioned step definition instead
+// of only the in-process runner registry (see pkg/steprunner), the same way a Go import path can
+// resolve to a module outside the current repo. A reference looks like
+// "github.com/acme/pdf-extract@v1.2.0": a host, an owner/repo pair, and a pinned version. On
+// first reference the repo is cloned into a local, version-scoped cache dir; every later run reuses
+// that checkout unchanged, so a workflow pinning a step to a version gets the same code every time
+// it runs. The cached repo must contain a step.yml manifest (see Manifest) declaring which built-in
+// runner (python, shell, node, ruby, remote_exec, browser_agent, ...) actually executes it and
+// where its entrypoint script lives; Resolve rewrites the step in place to point at that runner and
+// script before steprunner.GetRunner ever sees it.
+package steplib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+	"gopkg.in/yaml.v3"
+)
+
+// refLocks holds one mutex per ref, so concurrent resolutions of the *same* ref (the only case
+// that can actually race on a cache dir or a lockfile line) serialize against each other without
+// blocking unrelated refs a matrix's or parallel workflow's other cells might resolve at once.
+var (
+	refLocksMu sync.Mutex
+	refLocks   = map[string]*sync.Mutex{}
+)
+
+// lockFor returns the mutex guarding ref's fetch-then-lock critical section, creating it on first
+// use.
+func lockFor(ref Ref) *sync.Mutex {
+	key := ref.String()
+	refLocksMu.Lock()
+	defer refLocksMu.Unlock()
+	mu, ok := refLocks[key]
+	if !ok {
+		mu = &sync.Mutex{}
+		refLocks[key] = mu
+	}
+	return mu
+}
+
+// cacheRoot is where cloned step repos are checked out, relative to the current working directory,
+// matching every other ".dropstep/<thing>" cache this module keeps (pkg/cache, pkg/cas,
+// pkg/artifacts).
+const cacheRoot = ".dropstep/steps"
+
+// manifestFile is the name of a step library repo's own manifest, read from its checkout root.
+const manifestFile = "step.yml"
+
+// lockFile records every ref this workflow has resolved and the exact version each resolved to,
+// so a later run (or a teammate's machine) can confirm it got the same pinned version rather than
+// silently picking up a moved tag. It lives alongside the workflow file, like a package manager's
+// lockfile next to its manifest.
+const lockFile = "dropstep-steps.lock"
+
+// refPattern matches a steplib-style `uses:` reference: a dotted host, an owner, a repo, and an
+// "@version" pin. The host segment requiring a "." is what distinguishes a steplib reference from
+// a built-in runner name like "shell" or "python", neither of which contain one.
+var refPattern = regexp.MustCompile(`^([a-zA-Z0-9.-]+\.[a-zA-Z0-9.-]+)/([\w.-]+)/([\w.-]+)@([\w.-]+)$`)
+
+// Ref identifies one version-pinned step in an external repo.
+type Ref struct {
+	Host    string
+	Owner   string
+	Repo    string
+	Version string
+}
+
+// String renders ref back to the `uses:` form it was parsed from.
+func (r Ref) String() string {
+	return fmt.Sprintf("%s/%s/%s@%s", r.Host, r.Owner, r.Repo, r.Version)
+}
+
+// CloneURL is the https URL Resolve clones ref's repo from.
+func (r Ref) CloneURL() string {
+	return fmt.Sprintf("https://%s/%s/%s", r.Host, r.Owner, r.Repo)
+}
+
+// cacheDir is where ref's checkout lives on disk, sharded by host/owner/repo/version so distinct
+// versions of the same step coexist and a different repo never collides with it.
+func (r Ref) cacheDir() string {
+	return filepath.Join(cacheRoot, r.Host, r.Owner, r.Repo, r.Version)
+}
+
+// IsRef reports whether uses names a steplib reference rather than a built-in runner type.
+func IsRef(uses string) bool {
+	return refPattern.MatchString(uses)
+}
+
+// ParseRef parses uses as a steplib reference.
+func ParseRef(uses string) (Ref, error) {
+	m := refPattern.FindStringSubmatch(uses)
+	if m == nil {
+		return Ref{}, fmt.Errorf("steplib: %q is not a valid step reference (want host/owner/repo@version)", uses)
+	}
+	return Ref{Host: m[1], Owner: m[2], Repo: m[3], Version: m[4]}, nil
+}
+
+// Entrypoint declares the script a manifest's step runs, in the same shape as types.CommandBlock's
+// own Path/Inline pair.
+type Entrypoint struct {
+	Path   string `yaml:"path"`
+	Inline string `yaml:"inline"`
+}
+
+// Manifest is a step library repo's step.yml: what runner actually executes the step, and its
+// entrypoint relative to the repo root.
+type Manifest struct {
+	// Runner names the underlying, already-registered runner type this step delegates to, e.g.
+	// "python", "node", "remote_exec", "browser_agent".
+	Runner string `yaml:"runner"`
+	// Entrypoint is resolved relative to the checkout root; exactly one of Path/Inline should be
+	// set, the same constraint each runner's own Validate already enforces on a step's `run:`
+	// block.
+	Entrypoint Entrypoint `yaml:"entrypoint"`
+	// Interpreter overrides the runner's default interpreter, same meaning as
+	// types.CommandBlock.Interpreter.
+	Interpreter string `yaml:"interpreter,omitempty"`
+}
+
+// Resolve rewrites step so that its Uses/Command point at the runner and entrypoint a steplib
+// reference's manifest declares, cloning and caching the referenced repo on first use. It returns
+// an error for anything that isn't a steplib reference at all, so steprunner.GetRunner can fall
+// back to its own "no runner registered" message instead of steplib's. ctx bounds the clone: a step
+// that times out while its steplib reference is still being fetched aborts the clone the same way
+// it would abort the step's own process.
+func Resolve(ctx context.Context, step types.Step, workflowDir string) (types.Step, error) {
+	ref, err := ParseRef(step.Uses)
+	if err != nil {
+		return step, err
+	}
+
+	// Serialize the whole fetch-then-lock critical section for this ref: two steps racing to
+	// resolve the same not-yet-cached ref would otherwise both see the cache dir missing and clone
+	// into it at once, or both read the lockfile before either appends and duplicate the entry.
+	mu := lockFor(ref)
+	mu.Lock()
+	defer mu.Unlock()
+
+	checkoutDir := ref.cacheDir()
+	if _, statErr := os.Stat(checkoutDir); statErr != nil {
+		if err := fetch(ctx, ref, checkoutDir); err != nil {
+			return step, fmt.Errorf("steplib: fetching %s: %w", ref, err)
+		}
+	}
+
+	manifest, err := loadManifest(checkoutDir)
+	if err != nil {
+		return step, fmt.Errorf("steplib: reading manifest for %s: %w", ref, err)
+	}
+
+	if manifest.Runner == "" {
+		return step, fmt.Errorf("steplib: %s's step.yml is missing 'runner'", ref)
+	}
+	if manifest.Entrypoint.Path == "" && manifest.Entrypoint.Inline == "" {
+		return step, fmt.Errorf("steplib: %s's step.yml must define either 'entrypoint.path' or 'entrypoint.inline'", ref)
+	}
+	if manifest.Entrypoint.Path != "" && manifest.Entrypoint.Inline != "" {
+		return step, fmt.Errorf("steplib: %s's step.yml must only define either 'entrypoint.path' or 'entrypoint.inline'", ref)
+	}
+
+	resolved := step
+	resolved.Uses = manifest.Runner
+	cmd := &types.CommandBlock{Interpreter: manifest.Interpreter, Inline: manifest.Entrypoint.Inline}
+	if manifest.Entrypoint.Path != "" {
+		cmd.Path = filepath.Join(checkoutDir, manifest.Entrypoint.Path)
+	}
+	// Carry over Resources/Remote if the workflow author set a `run:` block alongside `uses:` to
+	// constrain or dispatch this steplib step; only Path/Inline/Interpreter come from the manifest.
+	if step.Command != nil {
+		cmd.Resources = step.Command.Resources
+		cmd.Remote = step.Command.Remote
+	}
+	resolved.Command = cmd
+
+	if err := recordLock(workflowDir, ref); err != nil {
+		return step, fmt.Errorf("steplib: recording lockfile entry for %s: %w", ref, err)
+	}
+
+	return resolved, nil
+}
+
+// fetch clones ref's repo at its pinned version into dir. Shallow and branch/tag-pinned, so a
+// moving tag only ever resolves to whatever it pointed at the first time this ref was fetched on
+// this machine; re-pinning a workflow to a new version (or clearing the cache dir) is what picks
+// up a later commit.
+func fetch(ctx context.Context, ref Ref, dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	// #nosec G204
+	cmd := exec.CommandContext(ctx, "git", "clone", "--quiet", "--depth", "1", "--branch", ref.Version, ref.CloneURL(), dir)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git clone failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// loadManifest reads and parses checkoutDir's step.yml.
+func loadManifest(checkoutDir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(checkoutDir, manifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var manifest Manifest
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", manifestFile, err)
+	}
+	return &manifest, nil
+}
+
+// recordLock appends ref to workflowDir's lockfile if it isn't already pinned there, one line per
+// ref in "host/owner/repo@version" form. Resolve calls this every time, but a ref already present
+// is left untouched rather than duplicated.
+func recordLock(workflowDir string, ref Ref) error {
+	path := filepath.Join(workflowDir, lockFile)
+
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(existing), "\n") {
+		if strings.TrimSpace(line) == ref.String() {
+			return nil
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintln(f, ref.String())
+	return err
+}
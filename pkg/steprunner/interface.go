@@ -1,8 +1,15 @@
 package steprunner
 
-import "github.com/arnavsurve/dropstep/pkg/types"
+import (
+	"context"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
 
 type StepRunner interface {
 	Validate() error
-	Run() (*types.StepResult, error)
+	// Run executes the step. ctx carries the workflow/step deadline (see the `timeout:` YAML
+	// field) and is canceled on SIGINT/SIGTERM, so implementations should use it to abort any
+	// subprocess or in-flight request they started rather than letting it leak past cancellation.
+	Run(ctx context.Context) (*types.StepResult, error)
 }
@@ -0,0 +1,95 @@
+package steprunner
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ProgressReporter renders live progress for a long-running step, driven by a "progress"
+// StreamEvent a script emits on its own stdout (see StreamSentinel, ApplyStreamEvent). Start may be
+// called more than once per step, since every progress event re-declares total/label; only the
+// first call begins the elapsed-time clock an implementation may choose to render.
+type ProgressReporter interface {
+	// Start declares (or re-declares) the unit total and a human-readable label for what's in
+	// progress. total <= 0 means the total is unknown; an implementation should render current
+	// alone in that case.
+	Start(total int64, label string)
+	// Update reports progress against the most recently declared Start.
+	Update(current int64)
+	// Finish ends reporting, clearing the reporter's own rendered output (if any) so it doesn't
+	// interleave with whatever the step logs next.
+	Finish()
+}
+
+// NewProgressReporter returns a reporter writing to out, or a no-op one if progress shouldn't be
+// shown: noProgress/silent were requested (see types.ExecutionContext.NoProgress/Silent), or out
+// isn't an interactive terminal.
+func NewProgressReporter(out *os.File, noProgress, silent bool) ProgressReporter {
+	if noProgress || silent || !IsTerminal(out) {
+		return noopProgressReporter{}
+	}
+	return &ttyProgressReporter{out: out}
+}
+
+// IsTerminal reports whether f is attached to an interactive terminal, without pulling in a
+// terminal-handling dependency: a character device is the standard no-dependency signal for this
+// on every platform Go's os package targets. Shared with browseragent's own progress reporter,
+// which has the same need.
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// noopProgressReporter discards every call; used whenever a live reporter would just add noise
+// (non-interactive output, --no-progress/--silent).
+type noopProgressReporter struct{}
+
+func (noopProgressReporter) Start(int64, string) {}
+func (noopProgressReporter) Update(int64)        {}
+func (noopProgressReporter) Finish()             {}
+
+// ttyProgressReporter renders a single, continuously-redrawn line of "<label> current/total
+// elapsed", the same redraw-in-place technique as browseragent's own step-progress line. Like that
+// one, it assumes it owns the terminal line: a matrix/parallel workflow running more than one
+// progress-reporting step at once will interleave their redraws, the same accepted limitation
+// browseragent's reporter already has.
+type ttyProgressReporter struct {
+	out *os.File
+
+	mu    sync.Mutex
+	start time.Time
+	total int64
+	label string
+}
+
+func (p *ttyProgressReporter) Start(total int64, label string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.start.IsZero() {
+		p.start = time.Now()
+	}
+	p.total = total
+	p.label = label
+}
+
+func (p *ttyProgressReporter) Update(current int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	progress := fmt.Sprintf("%d", current)
+	if p.total > 0 {
+		progress = fmt.Sprintf("%d/%d", current, p.total)
+	}
+	fmt.Fprintf(p.out, "\r%s  %s  %s\x1b[K", p.label, progress, time.Since(p.start).Round(time.Second))
+}
+
+func (p *ttyProgressReporter) Finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprint(p.out, "\r\x1b[K") // \x1b[K clears to end of line so the next log line starts clean
+}
@@ -1,12 +1,14 @@
 package steprunner
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/arnavsurve/dropstep/pkg/core"
+	"github.com/arnavsurve/dropstep/pkg/steplib"
+	"github.com/arnavsurve/dropstep/pkg/types"
 )
 
-type RunnerFactory func(ctx core.ExecutionContext) (StepRunner, error)
+type RunnerFactory func(ctx types.ExecutionContext) (StepRunner, error)
 
 // registry stores each type of step runner's factory function. GetRunner calls the appropriate StepRunner
 // factory function to yield a new instance of that StepRunner
@@ -20,13 +22,27 @@ func RegisterRunnerFactory(stepType string, factory RunnerFactory) {
 }
 
 // GetRunner returns an instance of the appropriate StepRunner based on the step's 'uses' field,
-// calling the corresponding runner's factory function from the registry.
-func GetRunner(ctx core.ExecutionContext) (StepRunner, error) {
-	stepType := ctx.Step.Uses
+// calling the corresponding runner's factory function from the registry. A 'uses' that isn't a
+// built-in type is tried against pkg/steplib as an external, versioned step reference (e.g.
+// "github.com/acme/pdf-extract@v1.2.0") before giving up; ctx bounds that resolution's clone the
+// same way it bounds the step's own execution.
+func GetRunner(ctx context.Context, execCtx types.ExecutionContext) (StepRunner, error) {
+	stepType := execCtx.Step.Uses
 	factory, ok := registry[stepType]
 	if !ok {
-		return nil, fmt.Errorf("no runner registered for type: %s", stepType)
+		if !steplib.IsRef(stepType) {
+			return nil, fmt.Errorf("no runner registered for type: %s", stepType)
+		}
+		resolvedStep, err := steplib.Resolve(ctx, execCtx.Step, execCtx.WorkflowDir)
+		if err != nil {
+			return nil, fmt.Errorf("resolving step library reference %q: %w", stepType, err)
+		}
+		execCtx.Step = resolvedStep
+		factory, ok = registry[execCtx.Step.Uses]
+		if !ok {
+			return nil, fmt.Errorf("step library reference %q resolved to unregistered runner %q", stepType, execCtx.Step.Uses)
+		}
 	}
 
-	return factory(ctx)
+	return factory(execCtx)
 }
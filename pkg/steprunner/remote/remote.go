@@ -0,0 +1,213 @@
+// Package remote lets a step execute against a remote worker pool using a content-addressed
+// protocol modeled on Bazel's Remote Execution API v2: a step's declared input files are hashed
+// into a Merkle input root (pkg/merkletree), combined with its command, the target platform's
+// properties, and its declared output paths into an Action digest, and an Action whose digest has
+// already run short-circuits to the cached result instead of running again. Config.SkipCacheLookup
+// and Config.DoNotCache let a step opt out of the read or write side of that cache independently,
+// the same way REv2's ExecuteRequest.skip_cache_lookup and Action.do_not_cache do.
+//
+// This package implements the content-addressing and result-caching half of that protocol, which
+// is useful on its own. It does NOT implement the REv2 network client — submitting Execute
+// requests, streaming Operation updates, and downloading outputs by digest from an actual worker
+// pool — because that needs the remote_execution.proto definitions and a gRPC stack, neither of
+// which this module vendors yet. Runner.Run falls back to running the wrapped StepRunner locally
+// on every cache miss; swapping that fallback for a real Execute RPC is the natural follow-up once
+// those dependencies are available. Config.OutputFiles/OutputDirectories are accepted and folded
+// into the action digest today so caching behaves correctly once that follow-up lands, even though
+// nothing downloads them yet.
+package remote
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/arnavsurve/dropstep/pkg/cache"
+	"github.com/arnavsurve/dropstep/pkg/cas"
+	"github.com/arnavsurve/dropstep/pkg/merkletree"
+	"github.com/arnavsurve/dropstep/pkg/steprunner"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// Config mirrors the workflow's `execution.remote` block, or a step's own `command.remote` block
+// when it sets one.
+type Config struct {
+	Endpoint     string
+	InstanceName string
+	TLS          bool
+	Platform     map[string]string
+	// CacheOnly makes a cache miss in Run an error instead of falling back to running the step
+	// locally.
+	CacheOnly bool
+	// Timeout bounds Run's dispatch attempt (e.g. "5m"). Parsed with time.ParseDuration. Empty
+	// means no separate deadline beyond whatever ctx already carries.
+	Timeout string
+	// OutputFiles and OutputDirectories declare the paths a remote worker would be expected to
+	// produce. They're folded into the action digest so declaring a different set of outputs is a
+	// cache miss even with identical inputs; nothing is fetched from them yet (see package doc).
+	OutputFiles       []string
+	OutputDirectories []string
+	// SkipCacheLookup bypasses the Results.Get check in Run, forcing a (local, for now) re-run even
+	// on what would otherwise be a cache hit. The result is still written back unless DoNotCache is
+	// also set.
+	SkipCacheLookup bool
+	// DoNotCache suppresses the Results.Put call after Run executes the step.
+	DoNotCache bool
+}
+
+// Runner wraps another StepRunner, short-circuiting its Run via a content-addressed cache keyed by
+// the step's Action digest before falling back to running it (see the package doc for what "remote
+// execution" means here today).
+type Runner struct {
+	Inner   steprunner.StepRunner
+	Step    types.Step
+	Config  Config
+	Blobs   cas.Store
+	Results cache.Store
+	// Logger, if set, gets a warning every time Run falls back to running the step locally after a
+	// cache miss, since this package doesn't dispatch to Config.Endpoint yet (see the package doc).
+	// Nil is safe; Wrap's existing callers that predate this field just run silently, as before.
+	Logger types.Logger
+}
+
+// Wrap returns a Runner that dispatches step's execution through inner, content-addressed against
+// blobs and results.
+func Wrap(inner steprunner.StepRunner, step types.Step, cfg Config, blobs cas.Store, results cache.Store, logger types.Logger) *Runner {
+	return &Runner{Inner: inner, Step: step, Config: cfg, Blobs: blobs, Results: results, Logger: logger}
+}
+
+func (r *Runner) Validate() error {
+	return r.Inner.Validate()
+}
+
+func (r *Runner) Run(ctx context.Context) (*types.StepResult, error) {
+	if r.Config.Timeout != "" {
+		d, err := time.ParseDuration(r.Config.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("step %q: invalid execution.remote timeout %q: %w", r.Step.ID, r.Config.Timeout, err)
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+
+	actionDigest, err := r.computeActionDigest()
+	if err != nil {
+		return nil, fmt.Errorf("computing action digest for step %q: %w", r.Step.ID, err)
+	}
+
+	if !r.Config.SkipCacheLookup {
+		if cached, found, err := r.Results.Get(actionDigest); err == nil && found {
+			return &cached, nil
+		}
+	}
+
+	// TODO: dispatch the Action to r.Config.Endpoint via the REv2 Execute RPC instead of running
+	// locally, once this module vendors a gRPC client for remote_execution.proto.
+	if r.Config.CacheOnly {
+		return nil, fmt.Errorf("step %q: execution.remote cache_only is set but action %s has no cached result, and REv2 Execute dispatch isn't implemented yet", r.Step.ID, actionDigest)
+	}
+
+	if r.Logger != nil {
+		r.Logger.Warn().Str("step", r.Step.ID).Str("endpoint", r.Config.Endpoint).Str("action", actionDigest).
+			Msg("execution.remote is configured but this build has no REv2 Execute client yet; running the step locally instead of dispatching it to endpoint")
+	}
+
+	result, err := r.Inner.Run(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if result != nil && !r.Config.DoNotCache {
+		if err := r.Results.Put(actionDigest, *result); err != nil {
+			return result, fmt.Errorf("caching result for step %q: %w", r.Step.ID, err)
+		}
+	}
+	return result, nil
+}
+
+// computeActionDigest uploads every file the step's config references to the CAS, builds their
+// Merkle input root, and combines it with the step's command and the target platform into an
+// Action digest.
+func (r *Runner) computeActionDigest() (string, error) {
+	files := referencedFiles(&r.Step)
+
+	nodes := make([]merkletree.FileNode, 0, len(files))
+	var missing []cas.Digest
+	digestsByPath := make(map[string]cas.Digest, len(files))
+	dataByHash := make(map[string][]byte, len(files))
+
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("reading input file %q: %w", path, err)
+		}
+		digest := cas.DigestOf(data)
+		digestsByPath[path] = digest
+		dataByHash[digest.Hash] = data
+		missing = append(missing, digest)
+	}
+
+	missing, err := r.Blobs.FindMissing(missing)
+	if err != nil {
+		return "", fmt.Errorf("checking CAS for missing blobs: %w", err)
+	}
+	for _, digest := range missing {
+		if err := r.Blobs.Put(digest, dataByHash[digest.Hash]); err != nil {
+			return "", fmt.Errorf("uploading blob %q: %w", digest.Hash, err)
+		}
+	}
+
+	for _, path := range files {
+		nodes = append(nodes, merkletree.FileNode{Path: path, Digest: digestsByPath[path]})
+	}
+
+	inputRoot, err := merkletree.BuildInputRoot(nodes)
+	if err != nil {
+		return "", err
+	}
+
+	outputPaths := make([]string, 0, len(r.Config.OutputFiles)+len(r.Config.OutputDirectories))
+	outputPaths = append(outputPaths, r.Config.OutputFiles...)
+	outputPaths = append(outputPaths, r.Config.OutputDirectories...)
+
+	actionDigest, err := merkletree.ActionDigest(commandArgv(&r.Step), nil, r.Config.Platform, outputPaths, inputRoot)
+	if err != nil {
+		return "", err
+	}
+	return actionDigest.Hash, nil
+}
+
+// referencedFiles returns the paths of every file a step's resolved config points at, for
+// inclusion in its Merkle input root.
+func referencedFiles(step *types.Step) []string {
+	var files []string
+	if step.Command != nil && step.Command.Path != "" {
+		files = append(files, step.Command.Path)
+	}
+	for _, f := range step.BrowserConfig.UploadFiles {
+		if f.Path != "" {
+			files = append(files, f.Path)
+		}
+	}
+	if step.BrowserConfig.OutputSchemaFile != "" {
+		files = append(files, step.BrowserConfig.OutputSchemaFile)
+	}
+	return files
+}
+
+// commandArgv describes the command a step's Action runs, for inclusion in its Action digest.
+func commandArgv(step *types.Step) []string {
+	if step.Command == nil {
+		return []string{step.Uses}
+	}
+	interpreter := step.Command.Interpreter
+	if interpreter == "" {
+		interpreter = "default"
+	}
+	if step.Command.Path != "" {
+		return []string{interpreter, step.Command.Path}
+	}
+	return []string{interpreter, "-c", step.Command.Inline}
+}
@@ -0,0 +1,125 @@
+package runners
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/arnavsurve/dropstep/pkg/assertions"
+	"github.com/arnavsurve/dropstep/pkg/steprunner"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// AssertRunner runs a `uses: assert` step's Asserts against StepCtx.EvalExpr, the engine's
+// read-only view into the run's inputs and every step result so far. Unlike a shell/python step's
+// inline `assertions:` (see pkg/assertions, evaluated against that one step's own
+// result/stdout/stderr/exit_code), an assert step's checks can reach any earlier step, the same
+// dotted-path/expression namespace `{{ }}` templates and `if:`/`unless:` already read from.
+type AssertRunner struct {
+	StepCtx types.ExecutionContext
+}
+
+func init() {
+	steprunner.RegisterRunnerFactory("assert", func(ctx types.ExecutionContext) (steprunner.StepRunner, error) {
+		return &AssertRunner{StepCtx: ctx}, nil
+	})
+}
+
+func (ar *AssertRunner) Validate() error {
+	step := ar.StepCtx.Step
+
+	if step.Command != nil {
+		return fmt.Errorf("assert step %q must not define 'run'", step.ID)
+	}
+	if step.Call != nil {
+		return fmt.Errorf("assert step %q must not define 'call'", step.ID)
+	}
+	if step.BrowserConfig.Prompt != "" {
+		return fmt.Errorf("assert step %q must not define 'browser.prompt'", step.ID)
+	}
+	if len(step.Asserts) == 0 {
+		return fmt.Errorf("assert step %q must define at least one entry in 'asserts'", step.ID)
+	}
+
+	for i, a := range step.Asserts {
+		if a.Expr == "" {
+			return fmt.Errorf("assert step %q: asserts[%d] is missing 'expr'", step.ID, i)
+		}
+		if !assertions.IsKnownOperator(a.Should) {
+			return fmt.Errorf("assert step %q: asserts[%d] has unknown 'should' %q", step.ID, i, a.Should)
+		}
+	}
+
+	return nil
+}
+
+// Run evaluates every Asserts entry in order, resolving Expr via StepCtx.EvalExpr before checking
+// it with assertions.EvaluateValue. A "must:" check (the default) that fails makes the step fail;
+// a "should:" check (Must explicitly false) that fails is logged as a warning and left out of the
+// failure count, but still recorded in Output so a later step can branch on it.
+func (ar *AssertRunner) Run(ctx context.Context) (*types.StepResult, error) {
+	step := ar.StepCtx.Step
+	logger := ar.StepCtx.Logger
+
+	if ar.StepCtx.EvalExpr == nil {
+		return nil, fmt.Errorf("assert step %q: no expression evaluator was wired into this step's ExecutionContext", step.ID)
+	}
+
+	results := make([]types.AssertCheckResult, 0, len(step.Asserts))
+	failed := 0
+
+	for _, a := range step.Asserts {
+		must := a.Must == nil || *a.Must
+
+		actual, err := ar.StepCtx.EvalExpr(a.Expr)
+		if err != nil {
+			results = append(results, types.AssertCheckResult{
+				Expr: a.Expr, Should: a.Should, Expected: a.Expected, Must: must,
+				Reason: fmt.Sprintf("resolving expr: %v", err),
+			})
+			if must {
+				failed++
+			}
+			continue
+		}
+
+		ok, reason, err := assertions.EvaluateValue(a.Should, actual, a.Expected, a.Negate)
+		if err != nil {
+			reason = err.Error()
+		}
+		if !ok && reason == "" {
+			reason = fmt.Sprintf("%s failed: got %v, want %v", a.Should, actual, a.Expected)
+		}
+
+		results = append(results, types.AssertCheckResult{
+			Expr: a.Expr, Should: a.Should, Expected: a.Expected, Actual: actual,
+			Must: must, Passed: ok, Reason: reason,
+		})
+
+		if !ok {
+			event := logger.Warn()
+			if must {
+				event = logger.Error()
+				failed++
+			}
+			event.Str("expr", a.Expr).Str("should", a.Should).Interface("expected", a.Expected).
+				Interface("actual", actual).Msg(reason)
+		}
+	}
+
+	checks := make([]any, len(results))
+	for i, r := range results {
+		checks[i] = map[string]any{
+			"expr": r.Expr, "should": r.Should, "expected": r.Expected,
+			"actual": r.Actual, "must": r.Must, "passed": r.Passed, "reason": r.Reason,
+		}
+	}
+	output := map[string]any{"passed": failed == 0, "checks": checks}
+	result := &types.StepResult{Output: output}
+
+	if failed > 0 {
+		return result, fmt.Errorf("step %q failed %d assertion(s)", step.ID, failed)
+	}
+
+	logger.Info().Msg("All assertions passed")
+	return result, nil
+}
@@ -0,0 +1,167 @@
+package runners_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/core"
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/arnavsurve/dropstep/pkg/steprunner/runners"
+	"github.com/arnavsurve/dropstep/pkg/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertRunner_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		step        core.Step
+		shouldError bool
+		errorMsg    string
+	}{
+		{
+			name: "valid",
+			step: core.Step{
+				ID: "valid_step",
+				Asserts: []types.AssertCheck{
+					{Expr: "steps.login.output.status_code", Should: "ShouldEqual", Expected: 200},
+				},
+			},
+		},
+		{
+			name:        "no asserts",
+			step:        core.Step{ID: "invalid_step"},
+			shouldError: true,
+			errorMsg:    "must define at least one entry in 'asserts'",
+		},
+		{
+			name: "missing expr",
+			step: core.Step{
+				ID:      "invalid_step",
+				Asserts: []types.AssertCheck{{Should: "ShouldEqual", Expected: 200}},
+			},
+			shouldError: true,
+			errorMsg:    "is missing 'expr'",
+		},
+		{
+			name: "unknown should",
+			step: core.Step{
+				ID:      "invalid_step",
+				Asserts: []types.AssertCheck{{Expr: "inputs.foo", Should: "ShouldVibe"}},
+			},
+			shouldError: true,
+			errorMsg:    "unknown 'should'",
+		},
+		{
+			name: "has run",
+			step: core.Step{
+				ID:      "invalid_step",
+				Command: &core.CommandBlock{Inline: "echo hi"},
+				Asserts: []types.AssertCheck{{Expr: "inputs.foo", Should: "ShouldEqual", Expected: "bar"}},
+			},
+			shouldError: true,
+			errorMsg:    "must not define 'run'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ar := &runners.AssertRunner{StepCtx: core.ExecutionContext{Step: tt.step}}
+			err := ar.Validate()
+			if tt.shouldError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAssertRunner_Run(t *testing.T) {
+	logger := log.NewZerologAdapter(zerolog.New(io.Discard))
+
+	values := map[string]any{
+		"steps.login.output.status_code": 200,
+		"inputs.env":                     "prod",
+	}
+	evalExpr := func(expr string) (any, error) {
+		return values[expr], nil
+	}
+
+	t.Run("all pass", func(t *testing.T) {
+		ar := &runners.AssertRunner{StepCtx: core.ExecutionContext{
+			Step: core.Step{
+				ID: "checks",
+				Asserts: []types.AssertCheck{
+					{Expr: "steps.login.output.status_code", Should: "ShouldEqual", Expected: 200},
+					{Expr: "inputs.env", Should: "ShouldEqual", Expected: "prod"},
+				},
+			},
+			Logger:   logger,
+			EvalExpr: evalExpr,
+		}}
+
+		result, err := ar.Run(context.Background())
+		require.NoError(t, err)
+		output := result.Output.(map[string]any)
+		assert.Equal(t, true, output["passed"])
+	})
+
+	t.Run("must failure fails the step", func(t *testing.T) {
+		ar := &runners.AssertRunner{StepCtx: core.ExecutionContext{
+			Step: core.Step{
+				ID: "checks",
+				Asserts: []types.AssertCheck{
+					{Expr: "steps.login.output.status_code", Should: "ShouldEqual", Expected: 500},
+				},
+			},
+			Logger:   logger,
+			EvalExpr: evalExpr,
+		}}
+
+		result, err := ar.Run(context.Background())
+		require.Error(t, err)
+		output := result.Output.(map[string]any)
+		assert.Equal(t, false, output["passed"])
+	})
+
+	t.Run("should failure only warns", func(t *testing.T) {
+		notMust := false
+		ar := &runners.AssertRunner{StepCtx: core.ExecutionContext{
+			Step: core.Step{
+				ID: "checks",
+				Asserts: []types.AssertCheck{
+					{Expr: "steps.login.output.status_code", Should: "ShouldEqual", Expected: 500, Must: &notMust},
+				},
+			},
+			Logger:   logger,
+			EvalExpr: evalExpr,
+		}}
+
+		result, err := ar.Run(context.Background())
+		require.NoError(t, err)
+		output := result.Output.(map[string]any)
+		assert.Equal(t, true, output["passed"])
+	})
+
+	t.Run("negate inverts the result", func(t *testing.T) {
+		ar := &runners.AssertRunner{StepCtx: core.ExecutionContext{
+			Step: core.Step{
+				ID: "checks",
+				Asserts: []types.AssertCheck{
+					{Expr: "steps.login.output.status_code", Should: "ShouldEqual", Expected: 500, Negate: true},
+				},
+			},
+			Logger:   logger,
+			EvalExpr: evalExpr,
+		}}
+
+		result, err := ar.Run(context.Background())
+		require.NoError(t, err)
+		output := result.Output.(map[string]any)
+		assert.Equal(t, true, output["passed"])
+	})
+}
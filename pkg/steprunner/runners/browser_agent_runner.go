@@ -1,6 +1,7 @@
 package runners
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -10,6 +11,7 @@ import (
 	"github.com/arnavsurve/dropstep/pkg/log"
 	"github.com/arnavsurve/dropstep/pkg/steprunner"
 	"github.com/arnavsurve/dropstep/pkg/steprunner/runners/browseragent"
+	"github.com/arnavsurve/dropstep/pkg/summary"
 	"github.com/arnavsurve/dropstep/pkg/types"
 	"github.com/rs/zerolog"
 )
@@ -30,9 +32,9 @@ func init() {
 			ctx.Logger = nullLogger
 		}
 
-		agentRunner, err := browseragent.NewSubprocessAgentRunner(logger)
+		agentRunner, err := browseragent.NewAgentRunner(ctx.Step.BrowserConfig.Engine, logger)
 		if err != nil {
-			return nil, fmt.Errorf("initializing subprocess agent runner: %w", err)
+			return nil, fmt.Errorf("initializing browser_agent runner: %w", err)
 		}
 		return &BrowserAgentRunner{
 			Agent:   agentRunner,
@@ -115,6 +117,12 @@ func (bar *BrowserAgentRunner) Validate() error {
 		return fmt.Errorf("step %q: browser.max_steps must be greater than 0", step.ID)
 	}
 
+	switch step.BrowserConfig.Engine {
+	case "", "python", "native":
+	default:
+		return fmt.Errorf("step %q: browser.engine must be 'python' or 'native', got %q", step.ID, step.BrowserConfig.Engine)
+	}
+
 	if step.MaxFailures == nil {
 		// If MaxFailures is not defined, no need to validate
 		// Default value is handled in the Python subprocess
@@ -125,7 +133,7 @@ func (bar *BrowserAgentRunner) Validate() error {
 	return nil
 }
 
-func (bar *BrowserAgentRunner) Run() (*types.StepResult, error) {
+func (bar *BrowserAgentRunner) Run(ctx context.Context) (*types.StepResult, error) {
 	step := bar.StepCtx.Step
 	logger := bar.StepCtx.Logger
 	workflowDir := bar.StepCtx.WorkflowDir
@@ -193,13 +201,19 @@ func (bar *BrowserAgentRunner) Run() (*types.StepResult, error) {
 	}
 
 	agentOutputPath := fmt.Sprintf("output/%s_output.json", step.ID)
-	jsonData, runErr := bar.Agent.RunAgent(
+	jsonData, telemetry, runErr := bar.Agent.RunAgent(
+		ctx,
 		agentStep,
 		agentOutputPath,
 		outputSchemaJSONString,
 		finalTargetDownloadDir,
 		logger,
 		bar.StepCtx.APIKey,
+		bar.StepCtx.AddMask,
+		bar.StepCtx.AddPath,
+		bar.StepCtx.StepSummaryPath,
+		bar.StepCtx.NoProgress,
+		bar.StepCtx.Silent,
 	)
 
 	if runErr != nil {
@@ -209,10 +223,14 @@ func (bar *BrowserAgentRunner) Run() (*types.StepResult, error) {
 
 	logger.Info().Msg("Step completed")
 
+	bar.writeDefaultSummary(jsonData, agentStep.BrowserConfig.UploadFiles)
+
 	var outputData map[string]any
 	if err := json.Unmarshal(jsonData, &outputData); err != nil {
 		logger.Error().Err(err).Msg("Error parsing JSON output from agent")
-		return &types.StepResult{Output: string(jsonData), OutputFile: agentOutputPath}, nil
+		result := &types.StepResult{Output: string(jsonData), OutputFile: agentOutputPath}
+		applyTelemetry(result, telemetry)
+		return result, nil
 	}
 
 	prettyOutput, _ := json.MarshalIndent(outputData, "", "  ")
@@ -222,6 +240,42 @@ func (bar *BrowserAgentRunner) Run() (*types.StepResult, error) {
 		Output:     outputData,
 		OutputFile: agentOutputPath,
 	}
+	applyTelemetry(result, telemetry)
 
 	return result, nil
 }
+
+// applyTelemetry copies an AgentRunner's per-step counters onto result, so downstream sinks (e.g.
+// FileSink) can report per-action progress instead of just the agent's final JSON output.
+func applyTelemetry(result *types.StepResult, telemetry *browseragent.AgentTelemetry) {
+	if telemetry == nil {
+		return
+	}
+	result.ActionsRun = telemetry.ActionsRun
+	result.LLMTokens = telemetry.LLMTokens
+	result.PagesVisited = telemetry.PagesVisited
+	result.Screenshots = telemetry.Screenshots
+}
+
+// writeDefaultSummary fills in bar.StepCtx.StepSummaryPath with the built-in summary (the agent's
+// final JSON output plus a table of uploaded files) if the agent didn't write its own content to
+// that file.
+func (bar *BrowserAgentRunner) writeDefaultSummary(jsonData []byte, uploadFiles []types.FileToUpload) {
+	path := bar.StepCtx.StepSummaryPath
+	if path == "" {
+		return
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		return
+	}
+
+	uploadNames := make([]string, 0, len(uploadFiles))
+	for _, f := range uploadFiles {
+		uploadNames = append(uploadNames, f.Name)
+	}
+
+	body := summary.RenderAgentDefault(jsonData, uploadNames)
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		bar.StepCtx.Logger.Warn().Err(err).Str("path", path).Msg("Failed to write default step summary")
+	}
+}
@@ -1,9 +1,26 @@
 package browseragent
 
 import (
+	"context"
+
 	"github.com/arnavsurve/dropstep/pkg/types"
 )
 
 type AgentRunner interface {
-	RunAgent(step types.Step, rawOutputPath string, schemaContent string, targetDownloadDir string, logger types.Logger, apiKey string) ([]byte, error)
+	// ctx carries the step's deadline and is canceled on SIGINT/SIGTERM; implementations must
+	// terminate the agent subprocess promptly once ctx is done rather than letting it run on.
+	// addMask, if non-nil, is called for every secret the agent reveals via an "::add-mask::"
+	// workflow command on its stdout, so it can be registered with the run's redactor. addPath,
+	// if non-nil, is called for every directory the agent adds via an "::add-path::" workflow
+	// command, so later steps see it prepended to their subprocess PATH.
+	// summaryPath, if non-empty, is exposed to the agent subprocess as $DROPSTEP_STEP_SUMMARY
+	// (see pkg/summary) so it can contribute Markdown to the run's step summary report.
+	// noProgress and silent mirror ExecutionContext.NoProgress/Silent: a backend that renders a
+	// live progress bar should skip it when either is set (silent additionally suppressing its
+	// own non-error logging), but neither is an error to ignore for a backend that has no
+	// progress output to begin with.
+	//
+	// The returned AgentTelemetry is never nil; a backend with nothing to report (e.g. one that
+	// doesn't speak the NDJSON event protocol) returns a zero-valued one.
+	RunAgent(ctx context.Context, step types.Step, rawOutputPath string, schemaContent string, targetDownloadDir string, logger types.Logger, apiKey string, addMask func(secret string), addPath func(dir string), summaryPath string, noProgress bool, silent bool) ([]byte, *AgentTelemetry, error)
 }
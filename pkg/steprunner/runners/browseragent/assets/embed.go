@@ -19,6 +19,10 @@ const (
 	SettingsPyFile   = "settings.py"
 	InitPyFile       = "__init__.py"
 	RequirementsFile = "requirements.txt"
+	// RequirementsLockFile, if present alongside RequirementsFile, pins every package in it to an
+	// exact version, wheel URL, and SHA-256 (see pkg/pywheels). ensurePythonVenv prefers it when
+	// available so venv setup is reproducible and can run offline.
+	RequirementsLockFile = "requirements.lock.json"
 )
 
 func GetAgentScriptContent(filename string) ([]byte, error) {
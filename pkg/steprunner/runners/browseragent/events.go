@@ -0,0 +1,120 @@
+package browseragent
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// agentEventsEnvVar names the environment variable SubprocessAgentRunner uses to tell the Python
+// agent which inherited file descriptor to write its NDJSON event stream to (see ExtraFiles in
+// RunAgent). The agent is expected to open it append-only and write one JSON object per line.
+const agentEventsEnvVar = "DROPSTEP_EVENTS_FD"
+
+// agentEventsFD is the file descriptor number the events pipe is attached at. cmd.ExtraFiles[0]
+// always lands at fd 3, since fds 0-2 are stdin/stdout/stderr.
+const agentEventsFD = 3
+
+// agentControlEnvVar names the environment variable telling the agent which inherited file
+// descriptor to read host-initiated control messages from (currently just a graceful abort
+// request; see waitForAgent).
+const agentControlEnvVar = "DROPSTEP_CONTROL_FD"
+
+// agentControlFD is the file descriptor number the control pipe is attached at. cmd.ExtraFiles[1]
+// lands at fd 4, right after the events pipe at fd 3.
+const agentControlFD = 4
+
+// AgentEventVersion is the current version of the NDJSON event protocol. Events with a different
+// "v" are logged as unrecognized rather than rejected, so older and newer agents can still produce
+// best-effort telemetry against a host built for a different version.
+const AgentEventVersion = 1
+
+// Event types an agent may emit on its events fd.
+const (
+	EventActionStart = "action_start"
+	EventActionEnd   = "action_end"
+	EventNav         = "nav"
+	EventLLMCall     = "llm_call"
+	EventScreenshot  = "screenshot"
+	EventError       = "error"
+	EventFinal       = "final"
+)
+
+// AgentEvent is a single line of the NDJSON protocol an agent backend emits for per-action
+// progress, separate from its free-form stdout/stderr logs.
+type AgentEvent struct {
+	V       int            `json:"v"`
+	TS      float64        `json:"ts"`
+	Type    string         `json:"type"`
+	Payload map[string]any `json:"payload,omitempty"`
+}
+
+// AgentTelemetry accumulates the counters and artifacts an AgentRunner observed while running a
+// step, surfaced on the step's types.StepResult once the step completes.
+type AgentTelemetry struct {
+	ActionsRun   int
+	LLMTokens    int
+	PagesVisited int
+	Screenshots  []string
+}
+
+// parseAgentEvents reads NDJSON events from r until EOF, forwarding each as a typed logger call
+// (unless silent) and folding it into telemetry. onEvent, if non-nil, is additionally called for
+// every successfully parsed event regardless of silent (e.g. to drive a live progress bar).
+// Malformed lines are logged and skipped rather than aborting the stream, since a single corrupted
+// event shouldn't cost the rest of a step's telemetry.
+func parseAgentEvents(r io.Reader, logger types.Logger, telemetry *AgentTelemetry, silent bool, onEvent func(AgentEvent)) {
+	scanner := bufio.NewScanner(r)
+	// Agent events (e.g. a screenshot payload) can be considerably larger than bufio.Scanner's
+	// 64KiB default line limit.
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		var ev AgentEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			logger.Warn().Err(err).Str("line", string(line)).Msg("Malformed agent event, skipping")
+			continue
+		}
+		if ev.V != AgentEventVersion {
+			logger.Debug().Int("version", ev.V).Str("type", ev.Type).Msg("Unrecognized agent event version")
+		}
+
+		if !silent {
+			logEntry := logger.Info()
+			for k, v := range ev.Payload {
+				logEntry = logEntry.Interface(k, v)
+			}
+			logEntry.Str("event", ev.Type).Msg("Agent event")
+		}
+
+		if onEvent != nil {
+			onEvent(ev)
+		}
+
+		switch ev.Type {
+		case EventActionEnd:
+			telemetry.ActionsRun++
+		case EventNav:
+			telemetry.PagesVisited++
+		case EventLLMCall:
+			if tokens, ok := ev.Payload["tokens"].(float64); ok {
+				telemetry.LLMTokens += int(tokens)
+			}
+		case EventScreenshot:
+			if path, ok := ev.Payload["path"].(string); ok {
+				telemetry.Screenshots = append(telemetry.Screenshots, path)
+			}
+		case EventError:
+			logger.Error().Interface("payload", ev.Payload).Msg("Agent reported an error")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		logger.Warn().Err(err).Msg("Error reading agent event stream")
+	}
+}
@@ -0,0 +1,75 @@
+package browseragent
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/arnavsurve/dropstep/pkg/steprunner"
+)
+
+// progressReporter renders a single, continuously-updated line of live status for an in-flight
+// browser_agent step: steps_completed/max_steps, the current URL (from the most recent "nav"
+// event), and elapsed time. It's driven by parseAgentEvents and written to stderr so it never
+// interleaves with a step's structured log output on stdout.
+type progressReporter struct {
+	out        *os.File
+	stepID     string
+	maxSteps   int
+	start      time.Time
+	mu         sync.Mutex
+	stepsSoFar int
+	currentURL string
+}
+
+// newProgressReporter returns a reporter for stepID, or nil if progress shouldn't be shown:
+// noProgress/silent were requested, or out isn't an interactive terminal.
+func newProgressReporter(out *os.File, stepID string, maxSteps int, noProgress, silent bool) *progressReporter {
+	if noProgress || silent || !steprunner.IsTerminal(out) {
+		return nil
+	}
+	return &progressReporter{out: out, stepID: stepID, maxSteps: maxSteps, start: time.Now()}
+}
+
+// onEvent updates the reporter's state from a single agent event and redraws the line.
+func (p *progressReporter) onEvent(ev AgentEvent) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	switch ev.Type {
+	case EventActionEnd:
+		p.stepsSoFar++
+	case EventNav:
+		if url, ok := ev.Payload["url"].(string); ok {
+			p.currentURL = url
+		}
+	}
+	p.mu.Unlock()
+	p.render()
+}
+
+func (p *progressReporter) render() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	progress := fmt.Sprintf("%d", p.stepsSoFar)
+	if p.maxSteps > 0 {
+		progress = fmt.Sprintf("%d/%d", p.stepsSoFar, p.maxSteps)
+	}
+
+	line := fmt.Sprintf("\r[%s] step %s  %s  %s", progress, p.stepID, p.currentURL, time.Since(p.start).Round(time.Second))
+	fmt.Fprint(p.out, line+"\x1b[K") // \x1b[K clears to end of line so a shorter redraw doesn't leave stale characters
+}
+
+// done clears the progress line once the step has finished, so the next log line starts clean.
+func (p *progressReporter) done() {
+	if p == nil {
+		return
+	}
+	fmt.Fprint(p.out, "\r\x1b[K")
+}
@@ -0,0 +1,46 @@
+package browseragent
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// BackendFactory constructs an AgentRunner for a single backend (e.g. "python", "native").
+type BackendFactory func(logger types.Logger) (AgentRunner, error)
+
+// DefaultBackendEnvVar, if set, picks the backend used by steps that don't set
+// 'browser.engine' explicitly. Falls back to DefaultBackend when unset.
+const DefaultBackendEnvVar = "DROPSTEP_BROWSER_ENGINE"
+
+// DefaultBackend is used when a step sets neither 'browser.engine' nor DefaultBackendEnvVar.
+const DefaultBackend = "python"
+
+// backends stores each AgentRunner backend's factory function, keyed by the name steps select it
+// with via 'browser.engine'. Mirrors how pkg/steprunner's registry lets runners register
+// themselves by step type.
+var backends = map[string]BackendFactory{}
+
+// RegisterBackend is called from each backend's init() to make it selectable via 'browser.engine'.
+func RegisterBackend(name string, factory BackendFactory) {
+	backends[name] = factory
+}
+
+// NewAgentRunner resolves the backend named by engine (falling back to DefaultBackendEnvVar, then
+// DefaultBackend, when engine is empty) and constructs it.
+func NewAgentRunner(engine string, logger types.Logger) (AgentRunner, error) {
+	name := engine
+	if name == "" {
+		name = os.Getenv(DefaultBackendEnvVar)
+	}
+	if name == "" {
+		name = DefaultBackend
+	}
+
+	factory, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("no browser_agent backend registered for engine %q", name)
+	}
+	return factory(logger)
+}
@@ -3,6 +3,7 @@ package browseragent
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"errors"
 	"fmt"
@@ -12,30 +13,116 @@ import (
 	"path/filepath"
 	"strconv"
 	"sync"
+	"syscall"
+	"time"
 
+	"github.com/arnavsurve/dropstep/pkg/pywheels"
+	"github.com/arnavsurve/dropstep/pkg/steprunner"
 	"github.com/arnavsurve/dropstep/pkg/steprunner/runners/browseragent/assets"
+	"github.com/arnavsurve/dropstep/pkg/summary"
 	"github.com/arnavsurve/dropstep/pkg/types"
 )
 
 const (
 	venvDirName          = "dropstep_agent_venv"
 	requirementsHashFile = ".requirements_hash"
+	venvLockFile         = ".dropstep_agent_venv.lock"
+
+	// defaultShutdownGracePeriod is how long RunAgent waits after SIGTERM before escalating to
+	// SIGKILL when its ctx is canceled.
+	defaultShutdownGracePeriod = 10 * time.Second
+)
+
+// acquireVenvLock takes an exclusive, blocking flock(2) on baseCacheDir/venvLockFile, creating it
+// if needed, and returns a func that releases it. This is a portable (well, Unix; the rest of this
+// package already assumes Unix via syscall.Setpgid/Kill) advisory lock: cooperating processes that
+// also flock(2) the same path are blocked out, but nothing stops a process that ignores it.
+func acquireVenvLock(baseCacheDir string) (func(), error) {
+	f, err := os.OpenFile(filepath.Join(baseCacheDir, venvLockFile), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening venv lock file: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("locking venv lock file: %w", err)
+	}
+	return func() {
+		syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
+
+// chromiumSem bounds concurrent Chromium instances across every SubprocessAgentRunner in this
+// process; since steprunner.GetRunner constructs a fresh runner per step, the limit can't live on
+// the instance itself. Sized lazily from whichever RunAgent call reaches acquireChromiumSlot first.
+var (
+	chromiumSemOnce sync.Once
+	chromiumSem     chan struct{}
 )
 
+// acquireChromiumSlot blocks until a Chromium instance slot is free (a no-op if s.MaxConcurrent is
+// 0) and returns a func that releases it.
+func (s *SubprocessAgentRunner) acquireChromiumSlot() func() {
+	if s.MaxConcurrent <= 0 {
+		return func() {}
+	}
+	chromiumSemOnce.Do(func() {
+		chromiumSem = make(chan struct{}, s.MaxConcurrent)
+	})
+	chromiumSem <- struct{}{}
+	return func() { <-chromiumSem }
+}
+
 // ensurePythonVenv sets up the Python virtual environment for the agent.
 // It extracts requirements.txt, creates a venv if it doesn't exist or if requirements changed,
 // and installs dependencies. Returns the path to the venv's python executable.
+//
+// When an embedded requirements.lock.json is present alongside requirements.txt, it pins every
+// package to an exact version, wheel URL, and SHA-256; dependencies are installed from a local,
+// hash-verified wheel cache under <baseCacheDir>/wheels (see pkg/pywheels) rather than resolved
+// against PyPI on every run. Setting DROPSTEP_OFFLINE=1 makes a missing cached wheel an error
+// instead of a network fetch. Without a lockfile, this falls back to a plain `pip install -r
+// requirements.txt` against PyPI, as before.
 func ensurePythonVenv(baseCacheDir string, logger types.Logger) (string, string, error) {
 	venvPath := filepath.Join(baseCacheDir, venvDirName)
 	pythonInterpreter := filepath.Join(venvPath, "bin", "python")
 	pipExecutable := filepath.Join(venvPath, "bin", "pip")
 
+	// Parallel browser_agent steps (workflow.parallel > 1) can all reach this function for their
+	// very first run at the same time, each about to create/rebuild the same venvPath. Hold an
+	// OS-level advisory lock on baseCacheDir/<venvDirName>.lock for the rest of this function so
+	// only one of them actually sets the venv up; the rest block here and then find it already
+	// built when they wake up.
+	unlockVenv, err := acquireVenvLock(baseCacheDir)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to acquire venv setup lock: %w", err)
+	}
+	defer unlockVenv()
+
 	// Get embedded requirements.txt content
 	reqBytes, err := assets.GetAgentScriptContent(assets.RequirementsFile)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to get embedded requirements.txt: %w", err)
 	}
-	currentReqHash := fmt.Sprintf("%x", sha256.Sum256(reqBytes))
+
+	var lock *pywheels.Lockfile
+	lockBytes, lockErr := assets.GetAgentScriptContent(assets.RequirementsLockFile)
+	if lockErr == nil {
+		lock, err = pywheels.ParseLockfile(lockBytes)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to parse embedded %s: %w", assets.RequirementsLockFile, err)
+		}
+	} else {
+		logger.Debug().Msg("No embedded requirements.lock.json found, falling back to unpinned pip install")
+	}
+
+	// Hash whichever file actually governs installation, so changing either one triggers a venv
+	// rebuild.
+	hashInput := reqBytes
+	if lock != nil {
+		hashInput = lockBytes
+	}
+	currentReqHash := fmt.Sprintf("%x", sha256.Sum256(hashInput))
 
 	// Check if venv exists and if requirements have changed
 	storedReqHashPath := filepath.Join(venvPath, requirementsHashFile)
@@ -80,18 +167,37 @@ func ensurePythonVenv(baseCacheDir string, logger types.Logger) (string, string,
 		}
 		defer os.Remove(tempReqFile.Name()) // Clean up temp file
 
-		if _, err := tempReqFile.Write(reqBytes); err != nil {
+		var cmdPip *exec.Cmd
+		if lock != nil {
+			offline := os.Getenv("DROPSTEP_OFFLINE") == "1"
+			wheelCacheDir := filepath.Join(baseCacheDir, "wheels")
+			if err := pywheels.Ensure(context.Background(), wheelCacheDir, lock, offline, logger); err != nil {
+				tempReqFile.Close()
+				return "", "", fmt.Errorf("failed to populate wheel cache: %w", err)
+			}
+
+			if err := pywheels.WriteLockRequirements(tempReqFile, lock); err != nil {
+				tempReqFile.Close()
+				return "", "", fmt.Errorf("failed to write locked requirements: %w", err)
+			}
 			tempReqFile.Close()
-			return "", "", fmt.Errorf("failed to write to temporary requirements.txt: %w", err)
+
+			cmdPip = exec.Command(pipExecutable, "install", "--no-index", "--find-links="+wheelCacheDir, "--require-hashes", "-r", tempReqFile.Name())
+		} else {
+			if _, err := tempReqFile.Write(reqBytes); err != nil {
+				tempReqFile.Close()
+				return "", "", fmt.Errorf("failed to write to temporary requirements.txt: %w", err)
+			}
+			tempReqFile.Close() // Close before pip uses it
+
+			cmdPip = exec.Command(pipExecutable, "install", "-r", tempReqFile.Name())
 		}
-		tempReqFile.Close() // Close before pip uses it
 
-		cmdPip := exec.Command(pipExecutable, "install", "-r", tempReqFile.Name())
 		var stderrPip bytes.Buffer
 		cmdPip.Stderr = &stderrPip
 		logger.Debug().Str("command", cmdPip.String()).Msg("Executing subprocess call")
 		if err := cmdPip.Run(); err != nil {
-			return "", "", fmt.Errorf("failed to install requirements (pip install -r %s): %w. Stderr: %s", tempReqFile.Name(), err, stderrPip.String())
+			return "", "", fmt.Errorf("failed to install requirements (%s): %w. Stderr: %s", cmdPip.String(), err, stderrPip.String())
 		}
 		logger.Info().Msg("Python requirements installed successfully")
 
@@ -106,9 +212,33 @@ func ensurePythonVenv(baseCacheDir string, logger types.Logger) (string, string,
 	return venvPath, pythonInterpreter, nil
 }
 
+// maxConcurrentEnvVar, if set to a positive integer, bounds how many SubprocessAgentRunner.RunAgent
+// calls may have a Chromium instance running at once across the whole process, regardless of how
+// many workflow steps ask for one concurrently (see workflow.parallel). Unset or non-positive means
+// unlimited.
+const maxConcurrentEnvVar = "DROPSTEP_BROWSER_MAX_CONCURRENT"
+
 type SubprocessAgentRunner struct {
 	agentWorkDir   string
+	runsParentDir  string
 	venvPythonPath string
+
+	// ShutdownGracePeriod is how long to wait after sending SIGTERM to the agent subprocess before
+	// escalating to SIGKILL, when RunAgent's ctx is canceled. Defaults to
+	// defaultShutdownGracePeriod when zero.
+	ShutdownGracePeriod time.Duration
+
+	// MaxConcurrent caps how many Chromium instances this process will run at once across all
+	// SubprocessAgentRunner instances (steprunner.GetRunner constructs a fresh one per step, so
+	// this can't be enforced per-instance); 0 means unlimited. Defaults from
+	// DROPSTEP_BROWSER_MAX_CONCURRENT.
+	MaxConcurrent int
+}
+
+func init() {
+	RegisterBackend("python", func(logger types.Logger) (AgentRunner, error) {
+		return NewSubprocessAgentRunner(logger)
+	})
 }
 
 // NewSubprocessAgentRunner initializes the runner, ensuring Python environment is set up.
@@ -123,30 +253,60 @@ func NewSubprocessAgentRunner(logger types.Logger) (*SubprocessAgentRunner, erro
 		return nil, fmt.Errorf("failed to create app cache directory %s: %w", appCacheDir, err)
 	}
 
+	// runsParentDir holds each RunAgent call's ephemeral runTempDir (extracted scripts, etc.),
+	// kept separate from the long-lived venv/wheel cache above so cleanup of one run can never
+	// race with or clobber another's persistent state.
+	runsParentDir := filepath.Join(appCacheDir, "runs")
+	if err := os.MkdirAll(runsParentDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create agent runs directory %s: %w", runsParentDir, err)
+	}
+
 	venvBasePath, venvPython, err := ensurePythonVenv(appCacheDir, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to ensure python venv: %w", err)
 	}
 	_ = venvBasePath
 
+	maxConcurrent := 0
+	if raw := os.Getenv(maxConcurrentEnvVar); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n > 0 {
+			maxConcurrent = n
+		} else {
+			logger.Warn().Str("value", raw).Msg("Ignoring invalid " + maxConcurrentEnvVar)
+		}
+	}
+
 	return &SubprocessAgentRunner{
 		agentWorkDir:   appCacheDir,
+		runsParentDir:  runsParentDir,
 		venvPythonPath: venvPython,
+		MaxConcurrent:  maxConcurrent,
 	}, nil
 }
 
 func (s *SubprocessAgentRunner) RunAgent(
-	step types.Step, 
-	rawOutputPath string, 
-	schemaContent string, 
-	targetDownloadDir string, 
+	ctx context.Context,
+	step types.Step,
+	rawOutputPath string,
+	schemaContent string,
+	targetDownloadDir string,
 	logger types.Logger,
 	apiKey string,
-) ([]byte, error) {
-	// Create a temporary directory for this specific agent run to place scripts
-	runTempDir, err := os.MkdirTemp(s.agentWorkDir, "agentrun-*")
+	addMask func(secret string),
+	addPath func(dir string),
+	summaryPath string,
+	noProgress bool,
+	silent bool,
+) ([]byte, *AgentTelemetry, error) {
+	telemetry := &AgentTelemetry{}
+
+	// Create a temporary directory for this specific agent run to place scripts, under the
+	// process's runsParentDir rather than directly in agentWorkDir (which also holds the shared
+	// venv/wheel cache), so concurrent steps' os.MkdirTemp calls and eventual os.RemoveAll cleanup
+	// stay well clear of that persistent state.
+	runTempDir, err := os.MkdirTemp(s.runsParentDir, "agentrun-*")
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temporary run directory: %w", err)
+		return nil, telemetry, fmt.Errorf("failed to create temporary run directory: %w", err)
 	}
 	defer func() {
 		if err := os.RemoveAll(runTempDir); err != nil {
@@ -167,11 +327,11 @@ func (s *SubprocessAgentRunner) RunAgent(
 	for _, scriptName := range scriptsToExtract {
 		content, err := assets.GetAgentScriptContent(scriptName)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get embedded script %s: %w", scriptName, err)
+			return nil, telemetry, fmt.Errorf("failed to get embedded script %s: %w", scriptName, err)
 		}
 		destPath := filepath.Join(runTempDir, scriptName)
 		if err := os.WriteFile(destPath, content, 0755); err != nil {
-			return nil, fmt.Errorf("failed to write embedded script %s to %s: %w", scriptName, destPath, err)
+			return nil, telemetry, fmt.Errorf("failed to write embedded script %s to %s: %w", scriptName, destPath, err)
 		}
 	}
 
@@ -179,7 +339,7 @@ func (s *SubprocessAgentRunner) RunAgent(
 
 	outputPath, err := filepath.Abs(rawOutputPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get absolute path for output file %s: %v", rawOutputPath, err)
+		return nil, telemetry, fmt.Errorf("failed to get absolute path for output file %s: %v", rawOutputPath, err)
 	}
 	logger.Debug().Str("path", outputPath).Msg("Resolved path for agent output")
 
@@ -189,7 +349,7 @@ func (s *SubprocessAgentRunner) RunAgent(
 		for _, f := range step.UploadFiles {
 			absPath, err := filepath.Abs(f.Path)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get abs path for upload %s: %w", f.Path, err)
+				return nil, telemetry, fmt.Errorf("failed to get abs path for upload %s: %w", f.Path, err)
 			}
 			cmdArgs = append(cmdArgs, absPath)
 		}
@@ -213,51 +373,157 @@ func (s *SubprocessAgentRunner) RunAgent(
 		cmdArgs = append(cmdArgs, "--max-failures", strconv.Itoa(*step.MaxFailures))
 	}
 
+	releaseChromiumSlot := s.acquireChromiumSlot()
+	defer releaseChromiumSlot()
+
 	cmd := exec.Command(extractedRunScriptPath, cmdArgs...)
 	cmd.Env = append(os.Environ(),
 		"ANONYMIZED_TELEMETRY=false",
 		"OPENAI_API_KEY="+apiKey,
 		"DROPSTEP_VENV_PYTHON="+s.venvPythonPath,
 		"DROPSTEP_AGENT_PY_PATH="+filepath.Join(runTempDir, assets.MainPyFile),
+		fmt.Sprintf("%s=%d", agentEventsEnvVar, agentEventsFD),
+		fmt.Sprintf("%s=%d", agentControlEnvVar, agentControlFD),
 	)
+	if summaryPath != "" {
+		cmd.Env = append(cmd.Env, summary.EnvVar+"="+summaryPath)
+	}
+	// Run the agent in its own process group so a single SIGTERM/SIGKILL to the group (see
+	// waitForAgent) reaches every Chromium/Playwright child it spawned too, instead of orphaning
+	// them when only the immediate child is signaled.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
 
 	stdout, err := cmd.StdoutPipe()
 	if err != nil {
-		return nil, fmt.Errorf("error creating stdout pipe: %w", err)
+		return nil, telemetry, fmt.Errorf("error creating stdout pipe: %w", err)
 	}
 	stderr, err := cmd.StderrPipe()
 	if err != nil {
-		return nil, fmt.Errorf("error creating stderr pipe: %w", err)
+		return nil, telemetry, fmt.Errorf("error creating stderr pipe: %w", err)
+	}
+
+	// eventsR/eventsW form the NDJSON events pipe: eventsW is handed to the child as fd 3 (the
+	// first entry in ExtraFiles, since fds 0-2 are already stdin/stdout/stderr) and we read
+	// structured per-action progress from eventsR on this side.
+	eventsR, eventsW, err := os.Pipe()
+	if err != nil {
+		return nil, telemetry, fmt.Errorf("error creating agent events pipe: %w", err)
 	}
+	// controlR/controlW form the reverse-direction control pipe (fd 4): waitForAgent writes a
+	// graceful {"type":"abort"} into controlW before escalating to signals, so the agent gets a
+	// chance to persist partial output first.
+	controlR, controlW, err := os.Pipe()
+	if err != nil {
+		eventsR.Close()
+		eventsW.Close()
+		return nil, telemetry, fmt.Errorf("error creating agent control pipe: %w", err)
+	}
+	cmd.ExtraFiles = []*os.File{eventsW, controlR}
 
 	if err := cmd.Start(); err != nil {
-		return nil, fmt.Errorf("failed to start agent script %s: %w", extractedRunScriptPath, err)
+		eventsR.Close()
+		eventsW.Close()
+		controlR.Close()
+		controlW.Close()
+		return nil, telemetry, fmt.Errorf("failed to start agent script %s: %w", extractedRunScriptPath, err)
+	}
+	eventsW.Close()  // Parent's copy; the child keeps its own inherited fd 3 open.
+	controlR.Close() // Parent's copy; the child keeps its own inherited fd 4 open.
+	defer controlW.Close()
+
+	progress := newProgressReporter(os.Stderr, step.ID, 0, noProgress, silent)
+	if step.MaxSteps != nil {
+		progress = newProgressReporter(os.Stderr, step.ID, *step.MaxSteps, noProgress, silent)
 	}
+
 	var wg sync.WaitGroup
-	wg.Add(2)
-	go streamOutputStructured(stdout, &wg, "STDOUT", logger)
-	go streamOutputStructured(stderr, &wg, "STDERR", logger)
+	wg.Add(3)
+	go streamOutputStructured(stdout, &wg, "STDOUT", logger, addMask, addPath)
+	go streamOutputStructured(stderr, &wg, "STDERR", logger, addMask, addPath)
+	go func() {
+		defer wg.Done()
+		parseAgentEvents(eventsR, logger, telemetry, silent, progress.onEvent)
+		eventsR.Close()
+	}()
 
-	waitErr := cmd.Wait()
+	waitErr := s.waitForAgent(ctx, cmd, logger, controlW)
 	wg.Wait()
+	progress.done()
 
+	if ctx.Err() != nil {
+		return nil, telemetry, fmt.Errorf("agent script %s aborted: %w", extractedRunScriptPath, ctx.Err())
+	}
 	if waitErr != nil {
-		return nil, fmt.Errorf("agent script %s failed: %w", extractedRunScriptPath, waitErr)
+		return nil, telemetry, fmt.Errorf("agent script %s failed: %w", extractedRunScriptPath, waitErr)
 	}
 	jsonData, readFileErr := os.ReadFile(outputPath)
 	if readFileErr != nil {
-		return nil, fmt.Errorf("failed to read agent output file %s: %w", outputPath, readFileErr)
+		return nil, telemetry, fmt.Errorf("failed to read agent output file %s: %w", outputPath, readFileErr)
 	}
-	return jsonData, nil
+	return jsonData, telemetry, nil
 }
 
-func streamOutputStructured(r io.Reader, wg *sync.WaitGroup, source string, logger types.Logger) {
+// abortGracePeriod is how long waitForAgent gives the agent to react to a graceful abort message
+// on the control pipe before escalating to SIGTERM.
+const abortGracePeriod = 3 * time.Second
+
+// waitForAgent waits for cmd to exit, or for ctx to be canceled first, in which case it writes a
+// graceful {"type":"abort"} to controlW, then sends SIGTERM to the whole process group and
+// escalates to SIGKILL if it hasn't exited within the grace period.
+func (s *SubprocessAgentRunner) waitForAgent(ctx context.Context, cmd *exec.Cmd, logger types.Logger, controlW *os.File) error {
+	waitErrCh := make(chan error, 1)
+	go func() { waitErrCh <- cmd.Wait() }()
+
+	select {
+	case err := <-waitErrCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	logger.Warn().Msg("Context canceled, asking agent subprocess to abort gracefully")
+	if _, err := controlW.Write([]byte(`{"type":"abort"}` + "\n")); err != nil {
+		logger.Warn().Err(err).Msg("Failed to write abort message to agent control pipe")
+	}
+
+	select {
+	case err := <-waitErrCh:
+		return err
+	case <-time.After(abortGracePeriod):
+	}
+
+	logger.Warn().Msg("Agent subprocess did not abort gracefully, sending SIGTERM to its process group")
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM); err != nil {
+		logger.Warn().Err(err).Msg("Failed to send SIGTERM to agent process group")
+	}
+
+	grace := s.ShutdownGracePeriod
+	if grace <= 0 {
+		grace = defaultShutdownGracePeriod
+	}
+
+	select {
+	case err := <-waitErrCh:
+		return err
+	case <-time.After(grace):
+		logger.Warn().Msg("Agent process group did not exit after grace period, sending SIGKILL")
+		if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+			logger.Warn().Err(err).Msg("Failed to send SIGKILL to agent process group")
+		}
+		return <-waitErrCh
+	}
+}
+
+func streamOutputStructured(r io.Reader, wg *sync.WaitGroup, source string, logger types.Logger, addMask func(secret string), addPath func(dir string)) {
 	defer wg.Done()
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
+		line := scanner.Text()
+		if steprunner.HandleLine(line, logger, addMask, addPath, nil) {
+			continue
+		}
 		logger.Info().
 			Str("source", source).
-			Str("agent_line", scanner.Text()).
+			Str("agent_line", line).
 			Msg("Agent output")
 	}
 	if err := scanner.Err(); err != nil && err != io.EOF {
@@ -267,4 +533,3 @@ func streamOutputStructured(r io.Reader, wg *sync.WaitGroup, source string, logg
 		logger.Error().Err(err).Str("source", source).Msg("Unexpected error streaming agent output")
 	}
 }
-
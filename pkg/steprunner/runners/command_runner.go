@@ -0,0 +1,412 @@
+package runners
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/arnavsurve/dropstep/pkg/fileutil"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// gracePeriod is how long a CommandRunner waits after SIGTERM before escalating to SIGKILL when a
+// command is killed for running over its context deadline, mirroring
+// browseragent.SubprocessAgentRunner.waitForAgent's escalation.
+const gracePeriod = 10 * time.Second
+
+// CommandSpec is the fully-resolved description of a single process to execute, built by
+// ShellRunner/PythonRunner from a step's CommandBlock before it's handed to a CommandRunner.
+// Separating "what to run" from "how it actually gets run" is what lets a test substitute a fake
+// CommandRunner instead of spawning a real process.
+type CommandSpec struct {
+	Path string
+	Args []string
+	Env  []string
+	// Dir is the child's default working directory before Resources.Cwd (if set) overrides it.
+	Dir string
+	// WorkflowDir roots Resources.Cwd and Resources.Sandbox's read_only/read_write path resolution.
+	WorkflowDir string
+	Stdout      io.Writer
+	Stderr      io.Writer
+	Resources   *types.ResourceLimits
+}
+
+// CommandOutput reports how a CommandSpec's process exited.
+type CommandOutput struct {
+	ExitCode int
+	// WaitErr is the error cmd.Wait() returned, if any (including a non-zero exit, as
+	// *exec.ExitError); nil means the process exited zero. Reported separately from Run's own
+	// error return, which is reserved for failures to even start or set up the process.
+	WaitErr error
+}
+
+// CommandRunner executes a CommandSpec and waits for it to finish. ShellRunner and PythonRunner
+// run through this interface instead of calling os/exec directly, so resource limits, sandboxing,
+// and process-group teardown on timeout live in one place, and a test can substitute a fake
+// CommandRunner instead of spawning a real process.
+type CommandRunner interface {
+	Run(ctx context.Context, spec CommandSpec) (*CommandOutput, error)
+}
+
+// DefaultCommandRunner is the production CommandRunner. It execs the process directly (optionally
+// wrapped for nice/ionice/rlimits/sandboxing), running it in its own process group so a context
+// cancellation or deadline kills every child it spawned too, not just the direct one.
+type DefaultCommandRunner struct{}
+
+func (DefaultCommandRunner) Run(ctx context.Context, spec CommandSpec) (*CommandOutput, error) {
+	path, args, err := wrapCommand(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// #nosec G204
+	cmd := exec.Command(path, args...)
+	cmd.Env = spec.Env
+	cmd.Dir = spec.Dir
+	if spec.Resources != nil && spec.Resources.Cwd != "" {
+		resolvedCwd, err := fileutil.ResolvePathFromWorkflow(spec.WorkflowDir, spec.Resources.Cwd)
+		if err != nil {
+			return nil, fmt.Errorf("resolving resources.cwd: %w", err)
+		}
+		cmd.Dir = resolvedCwd
+	}
+	cmd.Stdout = spec.Stdout
+	cmd.Stderr = spec.Stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting command: %w", err)
+	}
+
+	waitErrCh := make(chan error, 1)
+	go func() { waitErrCh <- cmd.Wait() }()
+
+	select {
+	case waitErr := <-waitErrCh:
+		exitCode := 0
+		if cmd.ProcessState != nil {
+			exitCode = cmd.ProcessState.ExitCode()
+		}
+		return &CommandOutput{ExitCode: exitCode, WaitErr: waitErr}, nil
+	case <-ctx.Done():
+	}
+
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+	select {
+	case waitErr := <-waitErrCh:
+		return &CommandOutput{ExitCode: exitCodeOf(cmd), WaitErr: waitErr}, nil
+	case <-time.After(gracePeriod):
+		_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+		waitErr := <-waitErrCh
+		return &CommandOutput{ExitCode: exitCodeOf(cmd), WaitErr: waitErr}, nil
+	}
+}
+
+func exitCodeOf(cmd *exec.Cmd) int {
+	if cmd.ProcessState != nil {
+		return cmd.ProcessState.ExitCode()
+	}
+	return -1
+}
+
+// envScopeOf returns r.Env, or nil if r itself is nil, so callers can pass a possibly-nil
+// *types.ResourceLimits straight to scopeEnv.
+func envScopeOf(r *types.ResourceLimits) *types.EnvScope {
+	if r == nil {
+		return nil
+	}
+	return r.Env
+}
+
+// scopeEnv filters baseEnv (typically os.Environ()) according to scope. nil means inherit
+// everything unchanged.
+func scopeEnv(baseEnv []string, scope *types.EnvScope) []string {
+	if scope == nil {
+		return baseEnv
+	}
+
+	env := baseEnv
+	if len(scope.Allow) > 0 {
+		allow := make(map[string]bool, len(scope.Allow))
+		for _, k := range scope.Allow {
+			allow[k] = true
+		}
+		filtered := make([]string, 0, len(env))
+		for _, kv := range env {
+			if k, _, ok := strings.Cut(kv, "="); ok && allow[k] {
+				filtered = append(filtered, kv)
+			}
+		}
+		env = filtered
+	}
+	if len(scope.Deny) > 0 {
+		deny := make(map[string]bool, len(scope.Deny))
+		for _, k := range scope.Deny {
+			deny[k] = true
+		}
+		filtered := make([]string, 0, len(env))
+		for _, kv := range env {
+			if k, _, ok := strings.Cut(kv, "="); ok && !deny[k] {
+				filtered = append(filtered, kv)
+			}
+		}
+		env = filtered
+	}
+	return env
+}
+
+// prependPath rewrites env's "PATH" entry (appending one if env has none) so dirs, most-recently
+// added first, comes before the existing PATH. dirs is typically stepCtx.PathDirs(), the
+// directories added so far via earlier steps' "::add-path::" workflow commands; an empty dirs
+// returns env unchanged.
+func prependPath(env []string, dirs []string) []string {
+	if len(dirs) == 0 {
+		return env
+	}
+
+	prefix := strings.Join(reverseStrings(dirs), string(os.PathListSeparator)) + string(os.PathListSeparator)
+	for i, kv := range env {
+		if k, v, ok := strings.Cut(kv, "="); ok && k == "PATH" {
+			out := append([]string{}, env...)
+			out[i] = "PATH=" + prefix + v
+			return out
+		}
+	}
+	return append(append([]string{}, env...), "PATH="+prefix)
+}
+
+// reverseStrings returns a reversed copy of s, so the most recently added add-path directory ends
+// up first (and therefore highest-priority) in the rebuilt PATH.
+func reverseStrings(s []string) []string {
+	out := make([]string, len(s))
+	for i, v := range s {
+		out[len(s)-1-i] = v
+	}
+	return out
+}
+
+// wrapCommand composes spec's path/args through whichever of Resources' process wrappers are
+// configured, innermost first: rlimits, then sandbox, then ionice, then nice. A step that sets
+// none of these gets spec.Path/spec.Args back unchanged.
+func wrapCommand(spec CommandSpec) (string, []string, error) {
+	path, args := spec.Path, spec.Args
+	r := spec.Resources
+	if r == nil {
+		return path, args, nil
+	}
+
+	if r.MaxMemoryMB > 0 || r.MaxCPUSeconds > 0 {
+		path, args = wrapUlimit(path, args, r)
+	}
+
+	if r.Sandbox != nil {
+		var err error
+		path, args, err = wrapSandbox(path, args, r.Sandbox, spec.WorkflowDir)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if r.IONice != nil {
+		var err error
+		path, args, err = wrapIONice(path, args, *r.IONice)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	if r.Nice != nil {
+		var err error
+		path, args, err = wrapNice(path, args, *r.Nice)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+
+	return path, args, nil
+}
+
+// wrapUlimit prepends a `sh -c 'ulimit ...; exec "$@"' sh <path> <args...>` preamble. This is the
+// practical way to apply RLIMIT_AS/RLIMIT_CPU to an arbitrary target binary in Go: os/exec has no
+// hook to call syscall.Setrlimit on the child between fork and exec, so the rlimits are set by a
+// shell builtin (itself backed by setrlimit(2)) immediately before it execs the real command.
+func wrapUlimit(path string, args []string, r *types.ResourceLimits) (string, []string) {
+	var b strings.Builder
+	if r.MaxCPUSeconds > 0 {
+		fmt.Fprintf(&b, "ulimit -t %d; ", r.MaxCPUSeconds)
+	}
+	if r.MaxMemoryMB > 0 {
+		fmt.Fprintf(&b, "ulimit -v %d; ", r.MaxMemoryMB*1024)
+	}
+	b.WriteString(`exec "$@"`)
+
+	newArgs := append([]string{"-c", b.String(), "sh", path}, args...)
+	return "/bin/sh", newArgs
+}
+
+// wrapNice prepends `nice -n <n>`.
+func wrapNice(path string, args []string, nice int) (string, []string, error) {
+	bin, err := exec.LookPath("nice")
+	if err != nil {
+		return "", nil, fmt.Errorf("resources.nice is set but the \"nice\" command was not found in PATH: %w", err)
+	}
+	return bin, append([]string{"-n", strconv.Itoa(nice), path}, args...), nil
+}
+
+// wrapIONice prepends `ionice -c <class>`. ionice is Linux-only (util-linux); there's no portable
+// equivalent to fall back to elsewhere, so this errors rather than silently ignoring the setting.
+func wrapIONice(path string, args []string, class int) (string, []string, error) {
+	if runtime.GOOS != "linux" {
+		return "", nil, fmt.Errorf("resources.ionice is set but ionice is only available on Linux, not %s", runtime.GOOS)
+	}
+	bin, err := exec.LookPath("ionice")
+	if err != nil {
+		return "", nil, fmt.Errorf("resources.ionice is set but the \"ionice\" command was not found in PATH: %w", err)
+	}
+	return bin, append([]string{"-c", strconv.Itoa(class), path}, args...), nil
+}
+
+// wrapSandbox dispatches to the configured (or OS-default) sandbox backend. Each backend errors
+// honestly if its wrapper binary isn't found, rather than silently running the command unsandboxed.
+func wrapSandbox(path string, args []string, sb *types.SandboxConfig, workflowDir string) (string, []string, error) {
+	backend := sb.Backend
+	if backend == "" {
+		if runtime.GOOS == "darwin" {
+			backend = "sandbox-exec"
+		} else {
+			backend = "bwrap"
+		}
+	}
+
+	switch backend {
+	case "bwrap":
+		return wrapBwrap(path, args, sb, workflowDir)
+	case "firejail":
+		return wrapFirejail(path, args, sb, workflowDir)
+	case "sandbox-exec":
+		return wrapSandboxExec(path, args, sb, workflowDir)
+	default:
+		return "", nil, fmt.Errorf("resources.sandbox.backend %q is not one of bwrap, firejail, or sandbox-exec", backend)
+	}
+}
+
+func resolveSandboxPaths(workflowDir string, rel []string) ([]string, error) {
+	resolved := make([]string, 0, len(rel))
+	for _, p := range rel {
+		abs, err := fileutil.ResolvePathFromWorkflow(workflowDir, p)
+		if err != nil {
+			return nil, fmt.Errorf("resolving sandbox path %q: %w", p, err)
+		}
+		resolved = append(resolved, abs)
+	}
+	return resolved, nil
+}
+
+// wrapBwrap wraps with bubblewrap (https://github.com/containers/bubblewrap), the common sandbox
+// on Linux distros without firejail installed. /usr, /bin, and /lib are always bound read-only so
+// the command can still find its interpreter and shared libraries; workflowDir is always bound
+// read-write.
+func wrapBwrap(path string, args []string, sb *types.SandboxConfig, workflowDir string) (string, []string, error) {
+	bin, err := exec.LookPath("bwrap")
+	if err != nil {
+		return "", nil, fmt.Errorf("resources.sandbox.backend \"bwrap\" is set but was not found in PATH: %w", err)
+	}
+
+	readOnly, err := resolveSandboxPaths(workflowDir, sb.ReadOnly)
+	if err != nil {
+		return "", nil, err
+	}
+	readWrite, err := resolveSandboxPaths(workflowDir, sb.ReadWrite)
+	if err != nil {
+		return "", nil, err
+	}
+
+	bwArgs := []string{
+		"--ro-bind", "/usr", "/usr",
+		"--ro-bind", "/bin", "/bin",
+		"--ro-bind", "/lib", "/lib",
+		"--dev", "/dev",
+		"--proc", "/proc",
+		"--bind", workflowDir, workflowDir,
+		"--chdir", workflowDir,
+	}
+	for _, p := range readOnly {
+		bwArgs = append(bwArgs, "--ro-bind", p, p)
+	}
+	for _, p := range readWrite {
+		bwArgs = append(bwArgs, "--bind", p, p)
+	}
+	bwArgs = append(bwArgs, path)
+	bwArgs = append(bwArgs, args...)
+	return bin, bwArgs, nil
+}
+
+// wrapFirejail wraps with firejail (https://firejail.wordpress.com), the alternative Linux sandbox
+// some distros ship instead of bubblewrap.
+func wrapFirejail(path string, args []string, sb *types.SandboxConfig, workflowDir string) (string, []string, error) {
+	bin, err := exec.LookPath("firejail")
+	if err != nil {
+		return "", nil, fmt.Errorf("resources.sandbox.backend \"firejail\" is set but was not found in PATH: %w", err)
+	}
+
+	readOnly, err := resolveSandboxPaths(workflowDir, sb.ReadOnly)
+	if err != nil {
+		return "", nil, err
+	}
+	readWrite, err := resolveSandboxPaths(workflowDir, sb.ReadWrite)
+	if err != nil {
+		return "", nil, err
+	}
+
+	fjArgs := []string{"--quiet", "--private=" + workflowDir}
+	for _, p := range readOnly {
+		fjArgs = append(fjArgs, "--read-only="+p)
+	}
+	for _, p := range readWrite {
+		fjArgs = append(fjArgs, "--whitelist="+p)
+	}
+	fjArgs = append(fjArgs, "--", path)
+	fjArgs = append(fjArgs, args...)
+	return bin, fjArgs, nil
+}
+
+// wrapSandboxExec wraps with macOS's sandbox-exec, building a minimal inline Seatbelt profile: deny
+// all filesystem writes except to workflowDir and any ReadWrite paths, and allow reads everywhere
+// (ReadOnly is implicit under "allow default" below, so it's accepted for config-shape parity with
+// the Linux backends but doesn't add a separate rule).
+func wrapSandboxExec(path string, args []string, sb *types.SandboxConfig, workflowDir string) (string, []string, error) {
+	bin, err := exec.LookPath("sandbox-exec")
+	if err != nil {
+		return "", nil, fmt.Errorf("resources.sandbox.backend \"sandbox-exec\" is set but was not found in PATH: %w", err)
+	}
+
+	readWrite, err := resolveSandboxPaths(workflowDir, sb.ReadWrite)
+	if err != nil {
+		return "", nil, err
+	}
+	// ReadOnly paths are already reachable under "allow default" + the file-write deny below; they
+	// exist as a config knob for parity with bwrap/firejail, not because sandbox-exec needs an
+	// explicit allow-read rule per path.
+	if _, err := resolveSandboxPaths(workflowDir, sb.ReadOnly); err != nil {
+		return "", nil, err
+	}
+
+	var profile bytes.Buffer
+	profile.WriteString("(version 1)\n(allow default)\n(deny file-write*)\n")
+	fmt.Fprintf(&profile, "(allow file-write* (subpath %q))\n", workflowDir)
+	for _, p := range readWrite {
+		fmt.Fprintf(&profile, "(allow file-write* (subpath %q))\n", p)
+	}
+
+	sbArgs := []string{"-p", profile.String(), path}
+	sbArgs = append(sbArgs, args...)
+	return bin, sbArgs, nil
+}
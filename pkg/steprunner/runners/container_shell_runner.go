@@ -0,0 +1,361 @@
+package runners
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/arnavsurve/dropstep/pkg/argutil"
+	"github.com/arnavsurve/dropstep/pkg/fileutil"
+	"github.com/arnavsurve/dropstep/pkg/steprunner"
+	"github.com/arnavsurve/dropstep/pkg/summary"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// containerShellLang reuses scriptLanguage only for the bits validateCommandStepShape checks
+// (name, inline/path shape); its interpreter fields are unused since the interpreter runs inside
+// the container image, not on the host. Unlike shellLang, inlinePrefix is plain POSIX `set -e`
+// rather than `set -euo pipefail`: the inline script runs via the image's own /bin/sh, which on
+// most container base images (dash, busybox ash) rejects bash-only `-u`/`-o pipefail`.
+var containerShellLang = scriptLanguage{
+	name:         "container_shell",
+	inlineFlag:   "-c",
+	inlinePrefix: "set -e\n",
+}
+
+// defaultContainerEngineEnvVar, if set, picks the container CLI used by steps whose
+// `container.engine` is empty. Falls back to auto-detection (docker, then podman) when unset.
+const defaultContainerEngineEnvVar = "DROPSTEP_CONTAINER_ENGINE"
+
+// validPullPolicies are the values container.pull_policy accepts, passed straight through to
+// `docker/podman run --pull`.
+var validPullPolicies = map[string]bool{"": true, "always": true, "missing": true, "never": true}
+
+// ContainerShellRunner runs a step's `run.inline`/`run.path` shell script inside a container image
+// (see types.ContainerConfig) instead of directly on the host, by shelling out to `docker run` or
+// `podman run`. It reuses CommandBlock's inline/path semantics and the same DROPSTEP_OUTPUT/stream
+// event/assertion handling as ShellRunner, just with the actual script body wrapped in a container
+// invocation rather than run as the host process directly.
+type ContainerShellRunner struct {
+	StepCtx types.ExecutionContext
+
+	// CommandRunner executes the resolved `docker`/`podman run` invocation; nil defaults to
+	// DefaultCommandRunner at Run time. Exported so a test can inject a fake instead of spawning a
+	// real container, the same way ShellRunner's CommandRunner does.
+	CommandRunner CommandRunner
+}
+
+func init() {
+	steprunner.RegisterRunnerFactory("container_shell", func(ctx types.ExecutionContext) (steprunner.StepRunner, error) {
+		return &ContainerShellRunner{
+			StepCtx: ctx,
+		}, nil
+	})
+}
+
+func (cr *ContainerShellRunner) Validate() error {
+	step := cr.StepCtx.Step
+	if err := validateCommandStepShape(step, containerShellLang); err != nil {
+		return err
+	}
+
+	if step.Command.Container == nil {
+		return fmt.Errorf("container_shell step %q must define 'run.container'", step.ID)
+	}
+	c := step.Command.Container
+
+	if step.Command.Resources != nil {
+		return fmt.Errorf("container_shell step %q must not define 'resources': it wraps the host process, but container_shell runs the command inside 'run.container' instead", step.ID)
+	}
+
+	if step.Command.Interpreter != "" {
+		return fmt.Errorf("container_shell step %q must not define 'interpreter': the command always runs via the container image's own /bin/sh", step.ID)
+	}
+
+	if len(step.Command.Paths) > 0 {
+		return fmt.Errorf("container_shell step %q must not define 'paths': only a single 'inline'/'path' script runs per container", step.ID)
+	}
+	if step.Command.ContinueOnError {
+		return fmt.Errorf("container_shell step %q must not define 'continue_on_error': only a single 'inline'/'path' script runs per container", step.ID)
+	}
+
+	if c.Image == "" {
+		return fmt.Errorf("container_shell step %q: container.image is required", step.ID)
+	}
+	if !validPullPolicies[c.PullPolicy] {
+		return fmt.Errorf("container_shell step %q: container.pull_policy %q is not one of always, missing, or never", step.ID, c.PullPolicy)
+	}
+	if c.Resources != nil && c.Resources.MemoryMB < 0 {
+		return fmt.Errorf("container_shell step %q: container.resources.memory_mb must not be negative", step.ID)
+	}
+
+	if step.Command.Path != "" {
+		if _, err := resolveContainerMountHost(cr.StepCtx.WorkflowDir, step.Command.Path); err != nil {
+			return fmt.Errorf("container_shell step %q: run.path must resolve inside the workflow directory, since that's the only host path always mounted into the container: %w", step.ID, err)
+		}
+	}
+
+	// Unlike a host interpreter (see validateScriptStep), docker/podman often only exist on the
+	// machine that actually runs workflows, not wherever `dropstep lint`/`validate` is invoked from
+	// (e.g. a CI job without Docker-in-Docker); treating the engine's absence as a lint failure
+	// would reject container_shell steps that are otherwise perfectly valid. resolveContainerEngine
+	// still runs (and fails loudly) at Run time.
+
+	for i, m := range c.Mounts {
+		if m.Host == "" {
+			return fmt.Errorf("container_shell step %q: container.mounts[%d] is missing 'host'", step.ID, i)
+		}
+		if m.Container == "" {
+			return fmt.Errorf("container_shell step %q: container.mounts[%d] is missing 'container'", step.ID, i)
+		}
+		if _, err := resolveContainerMountHost(cr.StepCtx.WorkflowDir, m.Host); err != nil {
+			return fmt.Errorf("container_shell step %q: container.mounts[%d]: %w", step.ID, i, err)
+		}
+	}
+
+	return nil
+}
+
+func (cr *ContainerShellRunner) Run(ctx context.Context) (*types.StepResult, error) {
+	step := cr.StepCtx.Step
+	logger := cr.StepCtx.Logger
+	workflowDir := cr.StepCtx.WorkflowDir
+	cfg := step.Command.Container
+
+	engineBin, err := resolveContainerEngine(cfg.Engine)
+	if err != nil {
+		return nil, err
+	}
+
+	isInline := step.Command.Inline != ""
+	var scriptArgs []string
+	if isInline {
+		scriptArgs = []string{"/bin/sh", "-c", containerShellLang.inlinePrefix + step.Command.Inline}
+	} else {
+		resolvedPath, err := fileutil.ResolvePathFromWorkflow(workflowDir, step.Command.Path)
+		if err != nil {
+			return nil, fmt.Errorf("resolving script path: %w", err)
+		}
+		if _, err := os.Stat(resolvedPath); err != nil {
+			return nil, fmt.Errorf("script file not found at %q: %w", resolvedPath, err)
+		}
+		scriptArgs = []string{"/bin/sh", resolvedPath}
+	}
+
+	outputDir, err := os.MkdirTemp("", fmt.Sprintf("dropstep-output-%s-", step.ID))
+	if err != nil {
+		return nil, fmt.Errorf("creating output directory: %w", err)
+	}
+	defer os.RemoveAll(outputDir)
+	outputFilePath := filepath.Join(outputDir, "output.json")
+
+	containerEnv := []string{"DROPSTEP_OUTPUT=" + outputFilePath}
+	if cr.StepCtx.StepSummaryPath != "" {
+		containerEnv = append(containerEnv, summary.EnvVar+"="+cr.StepCtx.StepSummaryPath)
+	}
+	containerEnv = append(containerEnv, cr.StepCtx.SecretMountEnv...)
+	containerEnv = append(containerEnv, matrixEnv(cr.StepCtx.MatrixVars)...)
+	containerEnv = append(containerEnv, sortedEnvPairs(cfg.Env)...)
+
+	dockerArgs, err := buildContainerRunArgs(cfg, workflowDir, outputDir, containerEnv, secretMountDirs(cr.StepCtx.SecretMountEnv), scriptArgs, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	reporter := steprunner.NewProgressReporter(os.Stderr, cr.StepCtx.NoProgress, cr.StepCtx.Silent)
+	stdoutWriter, waitStream := newStreamingStdout(&stdoutBuf, logger, reporter)
+
+	commandRunner := cr.CommandRunner
+	if commandRunner == nil {
+		commandRunner = DefaultCommandRunner{}
+	}
+
+	logger.Info().Str("image", cfg.Image).Str("engine", engineBin).Msg("Starting containerized script execution")
+
+	out, err := commandRunner.Run(ctx, CommandSpec{
+		Path:   engineBin,
+		Args:   dockerArgs,
+		Env:    os.Environ(),
+		Stdout: stdoutWriter,
+		Stderr: &stderrBuf,
+	})
+	streamEffects := waitStream()
+	if err != nil {
+		return nil, fmt.Errorf("executing containerized script: %w", err)
+	}
+
+	return finalizeCommandResult(cr.StepCtx, "container_shell", out, streamEffects, &stdoutBuf, &stderrBuf, outputFilePath)
+}
+
+// buildContainerRunArgs builds the full `run` argv for docker/podman: the workflow directory is
+// always bound read-write at the same absolute path it has on the host, so paths in the step's
+// script need no translation; outputDir (DROPSTEP_OUTPUT's own directory, freshly created per run
+// by Run) is bound read-write the same way rather than the host's whole temp directory, so the
+// container doesn't get write access to every other process' temp files; secretDirs (see
+// secretMountDirs) are bound read-only, since pkg/secretmount may stage its files somewhere other
+// than the host temp dir (e.g. $XDG_RUNTIME_DIR on Linux); cfg.Mounts add any further host paths
+// the step asked for. cfg.ExtraArgs is merged in via argutil.MergeArgs right after the flags built
+// from the rest of cfg, so a flag in ExtraArgs that names one of them (e.g. "--network=host")
+// overrides dropstep's own default instead of being passed to the container engine twice; logger
+// gets a debug line per flag ExtraArgs overrode this way.
+func buildContainerRunArgs(cfg *types.ContainerConfig, workflowDir, outputDir string, env []string, secretDirs []string, scriptArgs []string, logger types.Logger) ([]string, error) {
+	workdir := cfg.Workdir
+	if workdir == "" {
+		workdir = workflowDir
+	}
+
+	network := cfg.Network
+	if network == "" {
+		network = "none"
+	}
+
+	pullPolicy := cfg.PullPolicy
+	if pullPolicy == "" {
+		pullPolicy = "missing"
+	}
+
+	defaultFlags := []string{
+		"--pull", pullPolicy,
+		"--network", network,
+		"--workdir", workdir,
+	}
+	if cfg.User != "" {
+		defaultFlags = append(defaultFlags, "--user", cfg.User)
+	}
+	if cfg.Resources != nil {
+		if cfg.Resources.CPUs != "" {
+			defaultFlags = append(defaultFlags, "--cpus", cfg.Resources.CPUs)
+		}
+		if cfg.Resources.MemoryMB > 0 {
+			defaultFlags = append(defaultFlags, "--memory", fmt.Sprintf("%dm", cfg.Resources.MemoryMB))
+		}
+	}
+
+	flags, suppressed := argutil.MergeArgs(defaultFlags, cfg.ExtraArgs)
+	for _, name := range suppressed {
+		logger.Debug().Str("flag", name).Msg("container.extra_args overrides dropstep's own default for this flag")
+	}
+
+	args := []string{"run", "--rm", "-i"}
+	args = append(args, flags...)
+	args = append(args, "-v", workflowDir+":"+workflowDir)
+	args = append(args, "-v", outputDir+":"+outputDir)
+	for _, dir := range secretDirs {
+		args = append(args, "-v", dir+":"+dir+":ro")
+	}
+	for _, kv := range env {
+		args = append(args, "-e", kv)
+	}
+	for _, m := range cfg.Mounts {
+		hostPath, err := resolveContainerMountHost(workflowDir, m.Host)
+		if err != nil {
+			return nil, err
+		}
+		bind := hostPath + ":" + m.Container
+		if m.ReadOnly {
+			bind += ":ro"
+		}
+		args = append(args, "-v", bind)
+	}
+
+	args = append(args, cfg.Image)
+	args = append(args, scriptArgs...)
+	return args, nil
+}
+
+// secretMountDirs returns the distinct directories holding the files secretMountEnv's
+// "DROPSTEP_SECRET_<NAME>=<path>" entries point at (see pkg/secretmount.Stage), so Run can bind
+// each one into the container read-only; DROPSTEP_OUTPUT's own directory is bound separately and
+// unconditionally by Run, since it exists whether or not the step has secret_mounts.
+func secretMountDirs(secretMountEnv []string) []string {
+	seen := make(map[string]bool, len(secretMountEnv))
+	var dirs []string
+	for _, kv := range secretMountEnv {
+		_, path, ok := strings.Cut(kv, "=")
+		if !ok || path == "" {
+			continue
+		}
+		dir := filepath.Dir(path)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// resolveContainerMountHost resolves host (relative to workflowDir, like other step-declared
+// paths) and rejects it if it resolves outside workflowDir: a container_shell step's mounts are
+// meant to expose more of the workflow's own tree, not an arbitrary host path, unlike
+// resources.sandbox's ReadOnly/ReadWrite (which sandboxes an otherwise-unrestricted host process
+// that already has full filesystem access). This check is lexical only, same as every other
+// workflow-relative path in this codebase (see fileutil.ResolvePathFromWorkflow's callers): it
+// doesn't follow symlinks, so a symlink planted inside workflowDir can still point the mount
+// elsewhere. Closing that gap would require a workflow author to already be untrusted with write
+// access to their own workflow directory, a threat model nothing else here defends against either.
+func resolveContainerMountHost(workflowDir, host string) (string, error) {
+	resolved, err := fileutil.ResolvePathFromWorkflow(workflowDir, host)
+	if err != nil {
+		return "", fmt.Errorf("resolving mount host path %q: %w", host, err)
+	}
+	absWorkflowDir, err := filepath.Abs(workflowDir)
+	if err != nil {
+		return "", fmt.Errorf("resolving workflow directory: %w", err)
+	}
+	absResolved, err := filepath.Abs(resolved)
+	if err != nil {
+		return "", fmt.Errorf("resolving mount host path %q: %w", host, err)
+	}
+	rel, err := filepath.Rel(absWorkflowDir, absResolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("mount host path %q escapes the workflow directory", host)
+	}
+	return absResolved, nil
+}
+
+// resolveContainerEngine picks the container CLI binary: engine if set, else
+// defaultContainerEngineEnvVar, else whichever of docker/podman is found first in PATH.
+func resolveContainerEngine(engine string) (string, error) {
+	name := engine
+	if name == "" {
+		name = os.Getenv(defaultContainerEngineEnvVar)
+	}
+	if name != "" {
+		bin, err := exec.LookPath(name)
+		if err != nil {
+			return "", fmt.Errorf("container.engine %q was not found in PATH: %w", name, err)
+		}
+		return bin, nil
+	}
+
+	for _, candidate := range []string{"docker", "podman"} {
+		if bin, err := exec.LookPath(candidate); err == nil {
+			return bin, nil
+		}
+	}
+	return "", fmt.Errorf("no container engine found in PATH: install docker or podman, or set container.engine/%s", defaultContainerEngineEnvVar)
+}
+
+// sortedEnvPairs renders env as "KEY=VALUE" entries sorted by key, for deterministic argv output.
+func sortedEnvPairs(env map[string]string) []string {
+	if len(env) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+env[k])
+	}
+	return pairs
+}
@@ -0,0 +1,234 @@
+package runners_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/core"
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/arnavsurve/dropstep/pkg/steprunner/runners"
+	"github.com/arnavsurve/dropstep/pkg/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContainerShellRunner_Validate mirrors TestShellRunner_Validate's negative cases (the two
+// runners share validateCommandStepShape), plus cases specific to container_shell's own schema:
+// a missing 'run.container' block, a missing image, an invalid pull_policy, and a mount that
+// escapes the workflow directory.
+func TestContainerShellRunner_Validate(t *testing.T) {
+	validContainer := &types.ContainerConfig{Image: "alpine:3"}
+
+	workflowDir := t.TempDir()
+	scriptPath := filepath.Join(workflowDir, "script.sh")
+	require.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/sh\necho hello\n"), 0755))
+
+	tests := []struct {
+		name        string
+		step        core.Step
+		workflowDir string
+		shouldError bool
+		errorMsg    string
+	}{
+		{
+			name: "Valid container_shell command - inline",
+			step: core.Step{
+				ID: "valid_step",
+				Command: &core.CommandBlock{
+					Inline:    "echo 'hello'",
+					Container: validContainer,
+				},
+			},
+			shouldError: false,
+		},
+		{
+			name:        "Valid container_shell command - path",
+			workflowDir: workflowDir,
+			step: core.Step{
+				ID: "valid_step",
+				Command: &core.CommandBlock{
+					Path:      scriptPath,
+					Container: validContainer,
+				},
+			},
+			shouldError: false,
+		},
+		{
+			name:        "Invalid - both inline and path",
+			workflowDir: workflowDir,
+			step: core.Step{
+				ID: "invalid_step",
+				Command: &core.CommandBlock{
+					Inline:    "echo 'hello'",
+					Path:      scriptPath,
+					Container: validContainer,
+				},
+			},
+			shouldError: true,
+			errorMsg:    "must only define one of 'inline', 'path', or 'paths'",
+		},
+		{
+			name: "Invalid - no command",
+			step: core.Step{
+				ID:      "invalid_step",
+				Command: &core.CommandBlock{Container: validContainer},
+			},
+			shouldError: true,
+			errorMsg:    "must define one of 'inline', 'path', or 'paths'",
+		},
+		{
+			name: "Invalid - missing command block",
+			step: core.Step{
+				ID: "invalid_step",
+			},
+			shouldError: true,
+			errorMsg:    "must define 'run'",
+		},
+		{
+			name: "Invalid - has prompt",
+			step: core.Step{
+				ID: "invalid_step",
+				BrowserConfig: core.BrowserConfig{
+					Prompt: "Some prompt",
+				},
+				Command: &core.CommandBlock{Inline: "echo 'hello'", Container: validContainer},
+			},
+			shouldError: true,
+			errorMsg:    "must not define 'browser.prompt'",
+		},
+		{
+			name: "Invalid - has HTTP call",
+			step: core.Step{
+				ID:      "invalid_step",
+				Call:    &core.HTTPCall{Url: "https://example.com"},
+				Command: &core.CommandBlock{Inline: "echo 'hello'", Container: validContainer},
+			},
+			shouldError: true,
+			errorMsg:    "must not define 'call'",
+		},
+		{
+			name: "Invalid - has max failures",
+			step: core.Step{
+				ID:          "invalid_step",
+				MaxFailures: func() *int { i := 3; return &i }(),
+				Command:     &core.CommandBlock{Inline: "echo 'hello'", Container: validContainer},
+			},
+			shouldError: true,
+			errorMsg:    "must not define 'max_failures'",
+		},
+		{
+			name: "Invalid - missing container block",
+			step: core.Step{
+				ID:      "invalid_step",
+				Command: &core.CommandBlock{Inline: "echo 'hello'"},
+			},
+			shouldError: true,
+			errorMsg:    "must define 'run.container'",
+		},
+		{
+			name: "Invalid - missing image",
+			step: core.Step{
+				ID:      "invalid_step",
+				Command: &core.CommandBlock{Inline: "echo 'hello'", Container: &types.ContainerConfig{}},
+			},
+			shouldError: true,
+			errorMsg:    "container.image is required",
+		},
+		{
+			name: "Invalid - invalid pull_policy",
+			step: core.Step{
+				ID: "invalid_step",
+				Command: &core.CommandBlock{
+					Inline:    "echo 'hello'",
+					Container: &types.ContainerConfig{Image: "alpine:3", PullPolicy: "eventually"},
+				},
+			},
+			shouldError: true,
+			errorMsg:    "container.pull_policy \"eventually\" is not one of always, missing, or never",
+		},
+		{
+			name: "Invalid - has interpreter",
+			step: core.Step{
+				ID: "invalid_step",
+				Command: &core.CommandBlock{
+					Inline:      "echo 'hello'",
+					Interpreter: "bash",
+					Container:   validContainer,
+				},
+			},
+			shouldError: true,
+			errorMsg:    "must not define 'interpreter'",
+		},
+		{
+			name:        "Invalid - path escapes workflow directory",
+			workflowDir: t.TempDir(),
+			step: core.Step{
+				ID: "invalid_step",
+				Command: &core.CommandBlock{
+					Path:      "../outside.sh",
+					Container: validContainer,
+				},
+			},
+			shouldError: true,
+			errorMsg:    "run.path must resolve inside the workflow directory",
+		},
+		{
+			name: "Invalid - negative memory_mb",
+			step: core.Step{
+				ID: "invalid_step",
+				Command: &core.CommandBlock{
+					Inline: "echo 'hello'",
+					Container: &types.ContainerConfig{
+						Image:     "alpine:3",
+						Resources: &types.ContainerResources{MemoryMB: -1},
+					},
+				},
+			},
+			shouldError: true,
+			errorMsg:    "container.resources.memory_mb must not be negative",
+		},
+		{
+			name:        "Invalid - mount escapes workflow directory",
+			workflowDir: t.TempDir(),
+			step: core.Step{
+				ID: "invalid_step",
+				Command: &core.CommandBlock{
+					Inline: "echo 'hello'",
+					Container: &types.ContainerConfig{
+						Image: "alpine:3",
+						Mounts: []types.ContainerMount{
+							{Host: "../outside", Container: "/mnt/outside"},
+						},
+					},
+				},
+			},
+			shouldError: true,
+			errorMsg:    "escapes the workflow directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			baseZerologInstance := zerolog.New(io.Discard)
+			logger := log.NewZerologAdapter(baseZerologInstance)
+			ctx := core.ExecutionContext{
+				Step:        tt.step,
+				Logger:      logger,
+				WorkflowDir: tt.workflowDir,
+			}
+
+			cr := &runners.ContainerShellRunner{StepCtx: ctx}
+			err := cr.Validate()
+
+			if tt.shouldError {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errorMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package runners
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// navigateJSONPath walks body by path's dot-separated segments (e.g. "data.items.0.id"),
+// treating a purely numeric segment as a slice index. This mirrors the path grammar
+// pkg/core/jsonpath.go uses for "{{ steps.id.output.path }}" references, reimplemented here
+// rather than imported from pkg/core: pkg/core already imports pkg/steprunner to resolve step
+// runners, so importing pkg/core back from here would cycle.
+func navigateJSONPath(body any, path string) (any, bool) {
+	cur := body
+	if path == "" {
+		return cur, true
+	}
+	for _, seg := range strings.Split(path, ".") {
+		if idx, err := strconv.Atoi(seg); err == nil {
+			arr, ok := cur.([]any)
+			if !ok || idx < 0 || idx >= len(arr) {
+				return nil, false
+			}
+			cur = arr[idx]
+			continue
+		}
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		v, ok := m[seg]
+		if !ok {
+			return nil, false
+		}
+		cur = v
+	}
+	return cur, true
+}
+
+// evaluateHTTPAssert checks assert against statusCode, headers, and the parsed response body,
+// returning one types.AssertionFailure per check that didn't hold.
+func evaluateHTTPAssert(assert *types.HTTPAssert, statusCode int, headers map[string]string, body any) []types.AssertionFailure {
+	var failures []types.AssertionFailure
+
+	if len(assert.StatusIn) > 0 {
+		ok := false
+		for _, s := range assert.StatusIn {
+			if s == statusCode {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			failures = append(failures, types.AssertionFailure{
+				Path:     "status_code",
+				Should:   "ShouldBeIn",
+				Expected: assert.StatusIn,
+				Actual:   statusCode,
+				Reason:   fmt.Sprintf("status_code %d is not one of %v", statusCode, assert.StatusIn),
+			})
+		}
+	}
+
+	for name, want := range assert.HeaderMatches {
+		got, ok := lookupHeader(headers, name)
+		if !ok || got != want {
+			failures = append(failures, types.AssertionFailure{
+				Path:     "header:" + name,
+				Should:   "ShouldEqual",
+				Expected: want,
+				Actual:   got,
+				Reason:   fmt.Sprintf("header %q was %q, want %q", name, got, want),
+			})
+		}
+	}
+
+	for _, jp := range assert.JSONPath {
+		actual, found := navigateJSONPath(body, jp.Path)
+		if !found {
+			failures = append(failures, types.AssertionFailure{
+				Path:   jp.Path,
+				Should: "ShouldExist",
+				Reason: fmt.Sprintf("jsonpath %q did not resolve against the response body", jp.Path),
+			})
+			continue
+		}
+
+		if jp.Matches != "" {
+			re, err := regexp.Compile(jp.Matches)
+			if err != nil {
+				failures = append(failures, types.AssertionFailure{
+					Path: jp.Path, Should: "ShouldMatch", Expected: jp.Matches, Actual: actual,
+					Reason: fmt.Sprintf("invalid regex %q: %v", jp.Matches, err),
+				})
+				continue
+			}
+			if !re.MatchString(fmt.Sprintf("%v", actual)) {
+				failures = append(failures, types.AssertionFailure{
+					Path: jp.Path, Should: "ShouldMatch", Expected: jp.Matches, Actual: actual,
+					Reason: fmt.Sprintf("jsonpath %q value %v did not match %q", jp.Path, actual, jp.Matches),
+				})
+			}
+			continue
+		}
+
+		if !jsonValuesEqual(actual, jp.Expected) {
+			failures = append(failures, types.AssertionFailure{
+				Path: jp.Path, Should: "ShouldEqual", Expected: jp.Expected, Actual: actual,
+				Reason: fmt.Sprintf("jsonpath %q was %v, want %v", jp.Path, actual, jp.Expected),
+			})
+		}
+	}
+
+	return failures
+}
+
+// jsonValuesEqual compares actual (as decoded from a JSON response body, so numbers are always
+// float64) against expected (as decoded from workflow YAML, so a whole number is an int), treating
+// numeric values as equal if they have the same numeric value regardless of Go type. Every other
+// type compares via its formatted string, same as before.
+func jsonValuesEqual(actual, expected any) bool {
+	af, aok := toFloat64(actual)
+	ef, eok := toFloat64(expected)
+	if aok && eok {
+		return af == ef
+	}
+	return fmt.Sprintf("%v", actual) == fmt.Sprintf("%v", expected)
+}
+
+func toFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// lookupHeader finds name in headers case-insensitively, matching how net/http.Header itself
+// canonicalizes header names.
+func lookupHeader(headers map[string]string, name string) (string, bool) {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// extractHTTPValues resolves each of extract's dotted jsonpath expressions against body, omitting
+// any name whose path doesn't resolve rather than erroring.
+func extractHTTPValues(extract map[string]string, body any) map[string]any {
+	if len(extract) == 0 {
+		return nil
+	}
+	out := make(map[string]any, len(extract))
+	for name, path := range extract {
+		if v, ok := navigateJSONPath(body, path); ok {
+			out[name] = v
+		}
+	}
+	return out
+}
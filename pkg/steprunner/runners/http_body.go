@@ -0,0 +1,188 @@
+package runners
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/arnavsurve/dropstep/pkg/fileutil"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+var validBodyTypes = map[string]bool{"": true, "json": true, "form": true, "multipart": true, "raw": true}
+
+// httpRequestBody is a request body ready to attach to an *http.Request. build returns a fresh
+// io.Reader each call, so a retried attempt (or a multipart body's streamed file parts) re-reads
+// from the source rather than replaying an already-drained reader. ContentType, if non-empty, is
+// set on the request unless the step already set its own Content-Type header.
+type httpRequestBody struct {
+	ContentType string
+	build       func() (io.Reader, error)
+	// Preview is a small representation of the body for debug logging; empty for a multipart body,
+	// whose file parts are streamed rather than buffered and so have nothing cheap to preview.
+	Preview string
+}
+
+// buildHTTPRequestBody encodes callDetails' body fields per its BodyType, resolving any Files
+// entries against workflowDir. Returns nil, nil if the call has no body to send.
+func buildHTTPRequestBody(callDetails *types.HTTPCall, workflowDir string) (*httpRequestBody, error) {
+	switch callDetails.BodyType {
+	case "", "json":
+		return buildJSONBody(callDetails)
+	case "form":
+		return buildFormBody(callDetails)
+	case "raw":
+		return buildRawBody(callDetails)
+	case "multipart":
+		return buildMultipartBody(callDetails, workflowDir)
+	default:
+		return nil, fmt.Errorf("unknown body_type %q", callDetails.BodyType)
+	}
+}
+
+func buildJSONBody(callDetails *types.HTTPCall) (*httpRequestBody, error) {
+	if callDetails.Body == nil {
+		return nil, nil
+	}
+	jsonBody, err := json.Marshal(callDetails.Body)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request body to JSON: %w", err)
+	}
+	return bytesBody(jsonBody, "application/json"), nil
+}
+
+func buildFormBody(callDetails *types.HTTPCall) (*httpRequestBody, error) {
+	if callDetails.Body == nil {
+		return nil, nil
+	}
+	values := url.Values{}
+	for k, v := range callDetails.Body {
+		for _, s := range formValueStrings(v) {
+			values.Add(k, s)
+		}
+	}
+	encoded := values.Encode()
+	return bytesBody([]byte(encoded), "application/x-www-form-urlencoded"), nil
+}
+
+// formValueStrings flattens a YAML-decoded field value into the string(s) a form/multipart field
+// should send: a []any (e.g. "tags: [a, b]") becomes one string per element, sent as repeated
+// fields with the same name, matching how net/url.Values/multipart.Writer already represent a
+// repeated form field. Anything else is rendered with its default string form.
+func formValueStrings(v any) []string {
+	if list, ok := v.([]any); ok {
+		out := make([]string, len(list))
+		for i, item := range list {
+			out[i] = fmt.Sprintf("%v", item)
+		}
+		return out
+	}
+	return []string{fmt.Sprintf("%v", v)}
+}
+
+func buildRawBody(callDetails *types.HTTPCall) (*httpRequestBody, error) {
+	if callDetails.RawBody == "" {
+		return nil, nil
+	}
+	return bytesBody([]byte(callDetails.RawBody), ""), nil
+}
+
+// buildMultipartBody streams each Files entry straight from disk into its form part via an
+// io.Pipe, rather than reading the whole file into memory first, so a large upload doesn't blow up
+// the step's memory use. The multipart.Writer (and its boundary) must be recreated for every
+// attempt, since a pipe reader can only be drained once.
+func buildMultipartBody(callDetails *types.HTTPCall, workflowDir string) (*httpRequestBody, error) {
+	if len(callDetails.Body) == 0 && len(callDetails.Files) == 0 {
+		return nil, nil
+	}
+
+	// Resolve file paths up front so a bad path fails validation/the first attempt immediately,
+	// rather than partway through streaming.
+	resolvedFiles := make(map[string]string, len(callDetails.Files))
+	for field, path := range callDetails.Files {
+		resolved, err := fileutil.ResolvePathFromWorkflow(workflowDir, path)
+		if err != nil {
+			return nil, fmt.Errorf("resolving files.%s path %q: %w", field, path, err)
+		}
+		resolvedFiles[field] = resolved
+	}
+
+	// The boundary is part of the Content-Type header, so it must be fixed before the request is
+	// built; constructing one throwaway multipart.Writer here (writing nothing to it) is the
+	// standard way to get a boundary to advertise before the real, per-attempt writer is created.
+	boundaryWriter := multipart.NewWriter(io.Discard)
+	contentType := boundaryWriter.FormDataContentType()
+	boundary := boundaryWriter.Boundary()
+
+	build := func() (io.Reader, error) {
+		pr, pw := io.Pipe()
+		mw := multipart.NewWriter(pw)
+		if err := mw.SetBoundary(boundary); err != nil {
+			return nil, fmt.Errorf("setting multipart boundary: %w", err)
+		}
+
+		go func() {
+			err := writeMultipartParts(mw, callDetails.Body, resolvedFiles)
+			if err == nil {
+				err = mw.Close()
+			}
+			pw.CloseWithError(err)
+		}()
+
+		return pr, nil
+	}
+
+	return &httpRequestBody{ContentType: contentType, build: build, Preview: "(multipart body, not previewed)"}, nil
+}
+
+func writeMultipartParts(mw *multipart.Writer, fields map[string]any, files map[string]string) error {
+	for name, value := range fields {
+		for _, s := range formValueStrings(value) {
+			if err := mw.WriteField(name, s); err != nil {
+				return fmt.Errorf("writing multipart field %q: %w", name, err)
+			}
+		}
+	}
+
+	for field, path := range files {
+		if err := writeMultipartFile(mw, field, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeMultipartFile(mw *multipart.Writer, field, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening file %q for multipart field %q: %w", path, field, err)
+	}
+	defer f.Close()
+
+	part, err := mw.CreateFormFile(field, filepath.Base(path))
+	if err != nil {
+		return fmt.Errorf("creating multipart part for field %q: %w", field, err)
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return fmt.Errorf("streaming file %q into multipart field %q: %w", path, field, err)
+	}
+	return nil
+}
+
+func bytesBody(data []byte, contentType string) *httpRequestBody {
+	preview := string(data)
+	if len(preview) > 256 {
+		preview = preview[:256] + "..."
+	}
+	return &httpRequestBody{
+		ContentType: contentType,
+		build:       func() (io.Reader, error) { return bytes.NewReader(data), nil },
+		Preview:     preview,
+	}
+}
@@ -1,22 +1,52 @@
 package runners
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/arnavsurve/dropstep/pkg/fileutil"
 	"github.com/arnavsurve/dropstep/pkg/steprunner"
+	"github.com/arnavsurve/dropstep/pkg/tracing"
 	"github.com/arnavsurve/dropstep/pkg/types"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const defaultHttpTimeout = 30 * time.Second
 
+// Defaults applied to an HTTPCall's Retry block for any field left unset; see types.HTTPRetryPolicy.
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 500 * time.Millisecond
+	defaultRetryMaxBackoff     = 30 * time.Second
+	defaultRetryMultiplier     = 2.0
+)
+
+var defaultRetryOn = []string{"5xx", "429", "network", "timeout"}
+
+var validRetryOnValues = map[string]bool{"5xx": true, "429": true, "network": true, "timeout": true}
+
+// errMaxBodyBytesExceeded marks a doOneAttempt failure caused by call.max_body_bytes, a fixed
+// local policy rather than a transport problem: retrying the identical request can never make an
+// already-received response smaller, so it's excluded from retry_on's "network" matching below.
+var errMaxBodyBytesExceeded = errors.New("response body exceeds max_body_bytes")
+
 type HttpRunner struct {
 	StepCtx types.ExecutionContext
 }
@@ -80,10 +110,84 @@ func (hr *HttpRunner) Validate() error {
 		return fmt.Errorf("http step %q must not define 'max_failures'", step.ID)
 	}
 
+	if !validBodyTypes[step.Call.BodyType] {
+		return fmt.Errorf("http step %q: 'call.body_type' has unknown value %q (expected one of json, form, multipart, raw)", step.ID, step.Call.BodyType)
+	}
+	if step.Call.BodyType == "raw" && step.Call.Body != nil {
+		return fmt.Errorf("http step %q: 'call.body' must not be set when 'call.body_type' is 'raw' (use 'call.raw_body')", step.ID)
+	}
+	if step.Call.BodyType != "raw" && step.Call.RawBody != "" {
+		return fmt.Errorf("http step %q: 'call.raw_body' is only valid when 'call.body_type' is 'raw'", step.ID)
+	}
+	if step.Call.BodyType != "multipart" && len(step.Call.Files) > 0 {
+		return fmt.Errorf("http step %q: 'call.files' is only valid when 'call.body_type' is 'multipart'", step.ID)
+	}
+	bodySendingMethod := map[string]bool{"POST": true, "PUT": true, "PATCH": true}
+	if !bodySendingMethod[strings.ToUpper(step.Call.Method)] {
+		if step.Call.Body != nil || step.Call.RawBody != "" || len(step.Call.Files) > 0 {
+			return fmt.Errorf("http step %q: 'call.body'/'call.raw_body'/'call.files' require 'call.method' to be POST, PUT, or PATCH", step.ID)
+		}
+	}
+
+	if step.Call.MaxBodyBytes < 0 {
+		return fmt.Errorf("http step %q: 'call.max_body_bytes' must not be negative", step.ID)
+	}
+	if step.Call.SaveTo != "" {
+		if len(step.Call.Extract) > 0 {
+			return fmt.Errorf("http step %q: 'call.extract' is not supported when 'call.save_to' is set, since the body is streamed to disk rather than parsed", step.ID)
+		}
+		if step.Call.Assert != nil && len(step.Call.Assert.JSONPath) > 0 {
+			return fmt.Errorf("http step %q: 'call.assert.jsonpath' is not supported when 'call.save_to' is set, since the body is streamed to disk rather than parsed", step.ID)
+		}
+	}
+
+	if retry := step.Call.Retry; retry != nil {
+		if retry.MaxAttempts < 0 {
+			return fmt.Errorf("http step %q: 'call.retry.max_attempts' must not be negative", step.ID)
+		}
+		if retry.InitialBackoff != "" {
+			if _, err := time.ParseDuration(retry.InitialBackoff); err != nil {
+				return fmt.Errorf("http step %q: invalid 'call.retry.initial_backoff' %q: %w", step.ID, retry.InitialBackoff, err)
+			}
+		}
+		if retry.MaxBackoff != "" {
+			if _, err := time.ParseDuration(retry.MaxBackoff); err != nil {
+				return fmt.Errorf("http step %q: invalid 'call.retry.max_backoff' %q: %w", step.ID, retry.MaxBackoff, err)
+			}
+		}
+		if retry.Multiplier < 0 {
+			return fmt.Errorf("http step %q: 'call.retry.multiplier' must not be negative", step.ID)
+		}
+		for _, r := range retry.RetryOn {
+			if !validRetryOnValues[r] {
+				return fmt.Errorf("http step %q: 'call.retry.retry_on' has unknown value %q (expected one of 5xx, 429, network, timeout)", step.ID, r)
+			}
+		}
+	}
+
+	if assert := step.Call.Assert; assert != nil {
+		for i, jp := range assert.JSONPath {
+			if jp.Path == "" {
+				return fmt.Errorf("http step %q: 'call.assert.jsonpath[%d].path' is required", step.ID, i)
+			}
+			if jp.Expected != nil && jp.Matches != "" {
+				return fmt.Errorf("http step %q: 'call.assert.jsonpath[%d]' must not set both 'expected' and 'matches'", step.ID, i)
+			}
+			if jp.Expected == nil && jp.Matches == "" {
+				return fmt.Errorf("http step %q: 'call.assert.jsonpath[%d]' must set one of 'expected' or 'matches'", step.ID, i)
+			}
+			if jp.Matches != "" {
+				if _, err := regexp.Compile(jp.Matches); err != nil {
+					return fmt.Errorf("http step %q: invalid 'call.assert.jsonpath[%d].matches' %q: %w", step.ID, i, jp.Matches, err)
+				}
+			}
+		}
+	}
+
 	return nil
 }
 
-func (hr *HttpRunner) Run() (*types.StepResult, error) {
+func (hr *HttpRunner) Run(ctx context.Context) (*types.StepResult, error) {
 	step := hr.StepCtx.Step
 	logger := hr.StepCtx.Logger
 
@@ -91,37 +195,145 @@ func (hr *HttpRunner) Run() (*types.StepResult, error) {
 	method := strings.ToUpper(callDetails.Method)
 	url := callDetails.Url
 
-	var reqBody io.Reader
-	var reqBodyBytes []byte
-	if callDetails.Body != nil && (method == "POST" || method == "PUT" || method == "PATCH") {
-		jsonBody, err := json.Marshal(callDetails.Body)
+	var reqBody *httpRequestBody
+	if method == "POST" || method == "PUT" || method == "PATCH" {
+		body, err := buildHTTPRequestBody(callDetails, hr.StepCtx.WorkflowDir)
 		if err != nil {
-			return nil, fmt.Errorf("marshaling request body to JSON: %w", err)
+			return nil, fmt.Errorf("building request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
-		reqBodyBytes = jsonBody
+		reqBody = body
 	}
 
-	// Prepare request
-	timeout := defaultHttpTimeout
-	if step.Timeout != "" {
-		parsedDuration, err := time.ParseDuration(step.Timeout)
+	var saveToPath string
+	if callDetails.SaveTo != "" {
+		resolved, err := fileutil.ResolvePathFromWorkflow(hr.StepCtx.WorkflowDir, callDetails.SaveTo)
 		if err != nil {
-			logger.Warn().Err(err).Str("timeout", step.Timeout).Msg("Failed to parse timeout duration, using default")
-		} else {
-			timeout = parsedDuration
+			return nil, fmt.Errorf("resolving call.save_to path %q: %w", callDetails.SaveTo, err)
+		}
+		saveToPath = resolved
+	}
+
+	maxAttempts, initialBackoff, maxBackoff, multiplier, jitter, retryOn := retryPolicy(callDetails.Retry)
+
+	var result *types.StepResult
+	var statusCode int
+	var retryAfter time.Duration
+	var runErr error
+	attempt := 1
+
+	for ; attempt <= maxAttempts; attempt++ {
+		result, statusCode, retryAfter, runErr = hr.doOneAttempt(ctx, method, url, callDetails, reqBody, saveToPath, logger, attempt, maxAttempts)
+
+		if errors.Is(runErr, errMaxBodyBytesExceeded) || !shouldRetry(statusCode, runErr, retryOn) || attempt == maxAttempts {
+			break
+		}
+
+		backoff := computeBackoff(attempt, initialBackoff, maxBackoff, multiplier, jitter)
+		if retryAfter > 0 {
+			// A server-supplied Retry-After still honors max_backoff: a misbehaving upstream
+			// asking for an hour-long wait shouldn't bypass the policy's own safety cap.
+			backoff = retryAfter
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
 		}
+
+		logger.Warn().
+			Int("attempt", attempt).
+			Int("max_attempts", maxAttempts).
+			Int("status_code", statusCode).
+			Err(runErr).
+			Dur("backoff", backoff).
+			Msg("HTTP request failed, retrying after backoff")
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("HTTP request failed: %w", ctx.Err())
+		}
+	}
+
+	// A transport-level error (no response at all) is still a hard failure once retries are
+	// exhausted. A retryable status code (e.g. a persistent 5xx) is not: that matches this runner's
+	// existing non-2xx handling, which has always returned the response as the step's result and
+	// left pass/fail to the step's own assertions rather than erroring on status code alone.
+	if runErr != nil {
+		return nil, fmt.Errorf("HTTP request failed after %d attempt(s): %w", attempt, runErr)
+	}
+
+	output, _ := result.Output.(map[string]any)
+
+	if len(callDetails.Extract) > 0 && output != nil {
+		if extracted := extractHTTPValues(callDetails.Extract, output["body"]); extracted != nil {
+			output["extracted"] = extracted
+		}
+	}
+
+	if callDetails.Assert != nil {
+		respHeaders, _ := output["headers"].(map[string]string)
+		failures := evaluateHTTPAssert(callDetails.Assert, statusCode, respHeaders, output["body"])
+		for _, f := range failures {
+			logger.Error().
+				Str("assertion_path", f.Path).
+				Str("assertion_should", f.Should).
+				Interface("assertion_expected", f.Expected).
+				Interface("assertion_actual", f.Actual).
+				Msg(f.Reason)
+		}
+		result.AssertionFailures = failures
+		if len(failures) > 0 {
+			return result, fmt.Errorf("step %q failed %d assertion(s)", step.ID, len(failures))
+		}
+		logger.Info().Msg("All assertions passed")
+	}
+
+	return result, nil
+}
+
+// doOneAttempt fires a single HTTP request and returns its StepResult, the response's status code
+// (0 if the request never got a response), any Retry-After delay the response asked for, and the
+// transport-level error (nil if a response was received, even a non-2xx one).
+func (hr *HttpRunner) doOneAttempt(
+	ctx context.Context,
+	method, url string,
+	callDetails *types.HTTPCall,
+	body *httpRequestBody,
+	saveToPath string,
+	logger types.Logger,
+	attempt, maxAttempts int,
+) (*types.StepResult, int, time.Duration, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		r, err := body.build()
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("building request body: %w", err)
+		}
+		bodyReader = r
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	// Cap the request at defaultHttpTimeout unless the caller's ctx (the step/workflow `timeout:`,
+	// enforced by core.WorkflowEngine) already carries an earlier deadline, in which case
+	// WithTimeout keeps whichever deadline comes first. Deriving from ctx on every attempt means
+	// step.Timeout bounds total elapsed time across all attempts, not just this one.
+	attemptCtx, cancel := context.WithTimeout(ctx, defaultHttpTimeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
+	attemptCtx, span := tracing.Tracer().Start(attemptCtx, "http.request", trace.WithAttributes(
+		attribute.String("http.method", method),
+		attribute.String("http.url", redactedURLForSpan(url)),
+	))
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(attemptCtx, method, url, bodyReader)
 	if err != nil {
-		return nil, fmt.Errorf("creating HTTP request: %w", err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, 0, 0, fmt.Errorf("creating HTTP request: %w", err)
 	}
 
-	// Set headers
+	// Injects the current span's W3C traceparent (and any baggage) into the outbound request, so a
+	// traced backend's own spans nest under this one instead of starting a disconnected trace.
+	otel.GetTextMapPropagator().Inject(attemptCtx, propagation.HeaderCarrier(req.Header))
+
 	hasContentType := false
 	for key, value := range callDetails.Headers {
 		req.Header.Set(key, value)
@@ -129,8 +341,8 @@ func (hr *HttpRunner) Run() (*types.StepResult, error) {
 			hasContentType = true
 		}
 	}
-	if reqBody != nil && !hasContentType {
-		req.Header.Set("Content-Type", "application/json")
+	if body != nil && body.ContentType != "" && !hasContentType {
+		req.Header.Set("Content-Type", body.ContentType)
 	}
 	req.Header.Set("User-Agent", "Dropstep-Http-Client/1.0")
 
@@ -140,29 +352,73 @@ func (hr *HttpRunner) Run() (*types.StepResult, error) {
 		Str("method", method).
 		Str("url", url).
 		Interface("headers", callDetails.Headers).
+		Int("attempt", attempt).
+		Int("max_attempts", maxAttempts).
 		Msg("Making HTTP request")
-	if len(reqBodyBytes) > 0 {
-		// Log a preview of the body if it's small, or just its presence
-		bodyLog := string(reqBodyBytes)
-		if len(bodyLog) > 256 {
-			bodyLog = bodyLog[:256] + "..."
-		}
-		logger.Debug().Str("body_preview", bodyLog).Msg("Request body (redacted)")
+	if body != nil && body.Preview != "" {
+		logger.Debug().Str("body_preview", body.Preview).Msg("Request body (redacted)")
 	}
 
 	// Execute request
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("HTTP request failed: %w", err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, 0, 0, fmt.Errorf("HTTP request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Read response body
-	respBodyBytes, err := io.ReadAll(resp.Body)
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, fmt.Sprintf("HTTP %d", resp.StatusCode))
+	}
+
+	output := make(map[string]any)
+	output["status_code"] = resp.StatusCode
+
+	respHeaders := make(map[string]string)
+	for k, v := range resp.Header {
+		respHeaders[k] = strings.Join(v, ", ")
+	}
+	output["headers"] = respHeaders
+
+	retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"))
+
+	if saveToPath != "" {
+		bodySize, err := saveResponseBody(resp.Body, saveToPath)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			return nil, resp.StatusCode, 0, fmt.Errorf("saving response body to %q: %w", saveToPath, err)
+		}
+		span.SetAttributes(attribute.Int64("http.response_content_length", bodySize))
+		output["body_size"] = bodySize
+
+		logger.Info().
+			Int("status_code", resp.StatusCode).
+			Interface("response_headers", resp.Header).
+			Int64("body_size", bodySize).
+			Str("saved_to", saveToPath).
+			Msg("Received HTTP response, saved body to file")
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			logger.Warn().Int("status_code", resp.StatusCode).Msg("Received non-success HTTP response (non-2xx)")
+		}
+
+		return &types.StepResult{Output: output, OutputFile: saveToPath}, resp.StatusCode, retryAfter, nil
+	}
+
+	// Read response body, capped at call.max_body_bytes (if set) so a huge response doesn't get
+	// fully buffered into memory just to then fail on the size check.
+	respBodyBytes, exceededCap, err := readBodyCapped(resp.Body, callDetails.MaxBodyBytes)
 	if err != nil {
-		return nil, fmt.Errorf("reading response body: %w", err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, resp.StatusCode, 0, fmt.Errorf("reading response body: %w", err)
 	}
+	if exceededCap {
+		err := fmt.Errorf("%w: response body exceeds call.max_body_bytes (%d); set call.save_to to stream it to a file instead", errMaxBodyBytesExceeded, callDetails.MaxBodyBytes)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, resp.StatusCode, 0, err
+	}
+	span.SetAttributes(attribute.Int("http.response_content_length", len(respBodyBytes)))
 
 	logger.Info().
 		Int("status_code", resp.StatusCode).
@@ -180,15 +436,6 @@ func (hr *HttpRunner) Run() (*types.StepResult, error) {
 		logger.Warn().Int("status_code", resp.StatusCode).Msg("Received non-success HTTP response (non-2xx)")
 	}
 
-	output := make(map[string]any)
-	output["status_code"] = resp.StatusCode
-
-	respHeaders := make(map[string]string)
-	for k, v := range resp.Header {
-		respHeaders[k] = strings.Join(v, ", ")
-	}
-	output["headers"] = respHeaders
-
 	var responseOutputBody any
 	var parsedJsonAttempt any
 
@@ -201,10 +448,186 @@ func (hr *HttpRunner) Run() (*types.StepResult, error) {
 			responseOutputBody = base64.StdEncoding.EncodeToString(respBodyBytes)
 			logger.Warn().
 				Int("body_size_bytes", len(respBodyBytes)).
-				Msg("Response body was not valid JSON nor UTF-8 string, storing as base64.")
+				Msg("Response body was not valid JSON nor UTF-8 string, storing as base64. Set call.save_to to avoid this for large binary responses.")
 		}
 	}
 	output["body"] = responseOutputBody
 
-	return &types.StepResult{Output: output}, nil
+	return &types.StepResult{Output: output}, resp.StatusCode, retryAfter, nil
+}
+
+// readBodyCapped reads body in full, unless maxBytes > 0 and the body turns out to be larger, in
+// which case it returns exceededCap=true without an error (the caller decides how to report that).
+// maxBytes <= 0 means no cap.
+func readBodyCapped(body io.Reader, maxBytes int64) (data []byte, exceededCap bool, err error) {
+	if maxBytes <= 0 {
+		data, err = io.ReadAll(body)
+		return data, false, err
+	}
+	data, err = io.ReadAll(io.LimitReader(body, maxBytes+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, true, nil
+	}
+	return data, false, nil
+}
+
+// saveResponseBody streams body into a ".download" temp file next to path, creating its parent
+// directory if needed, and only renames it into place once fully written. This way a body that's
+// cut short partway through (a dropped connection, a full disk) never leaves a truncated file
+// sitting at the user-visible path.
+func saveResponseBody(body io.Reader, path string) (int64, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return 0, fmt.Errorf("creating directory for %q: %w", path, err)
+	}
+
+	tmpPath := path + ".download"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return 0, fmt.Errorf("creating file %q: %w", tmpPath, err)
+	}
+
+	n, copyErr := io.Copy(f, body)
+	closeErr := f.Close()
+	if copyErr != nil {
+		os.Remove(tmpPath)
+		return 0, copyErr
+	}
+	if closeErr != nil {
+		os.Remove(tmpPath)
+		return 0, closeErr
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return 0, fmt.Errorf("renaming %q to %q: %w", tmpPath, path, err)
+	}
+
+	return n, nil
+}
+
+// retryPolicy resolves policy's fields against their defaults, or returns a single-attempt policy
+// (no retrying) if policy is nil.
+func retryPolicy(policy *types.HTTPRetryPolicy) (maxAttempts int, initialBackoff, maxBackoff time.Duration, multiplier float64, jitter bool, retryOn []string) {
+	if policy == nil {
+		return 1, 0, 0, 0, false, nil
+	}
+
+	maxAttempts = policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultRetryMaxAttempts
+	}
+
+	initialBackoff = defaultRetryInitialBackoff
+	if policy.InitialBackoff != "" {
+		// Already validated by Validate(); a parse failure here would mean the step ran without
+		// going through validation first.
+		if d, err := time.ParseDuration(policy.InitialBackoff); err == nil {
+			initialBackoff = d
+		}
+	}
+
+	maxBackoff = defaultRetryMaxBackoff
+	if policy.MaxBackoff != "" {
+		if d, err := time.ParseDuration(policy.MaxBackoff); err == nil {
+			maxBackoff = d
+		}
+	}
+
+	multiplier = policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = defaultRetryMultiplier
+	}
+
+	retryOn = policy.RetryOn
+	if len(retryOn) == 0 {
+		retryOn = defaultRetryOn
+	}
+
+	return maxAttempts, initialBackoff, maxBackoff, multiplier, policy.Jitter, retryOn
+}
+
+// shouldRetry reports whether a failed attempt (statusCode == 0 on a transport error, runErr set;
+// otherwise a response was received) is retryable under retryOn.
+func shouldRetry(statusCode int, runErr error, retryOn []string) bool {
+	for _, r := range retryOn {
+		switch r {
+		case "5xx":
+			if statusCode >= 500 && statusCode < 600 {
+				return true
+			}
+		case "429":
+			if statusCode == 429 {
+				return true
+			}
+		case "timeout":
+			if errors.Is(runErr, context.DeadlineExceeded) {
+				return true
+			}
+		case "network":
+			// A deliberately cancelled run (ctx.Err() == context.Canceled, e.g. a sibling step
+			// failing and cancelling the whole workflow) is terminal, same as a timeout, not a
+			// transient network blip worth retrying.
+			if runErr != nil && !errors.Is(runErr, context.DeadlineExceeded) && !errors.Is(runErr, context.Canceled) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// computeBackoff returns initialBackoff * multiplier^(attempt-1), capped at maxBackoff, with up to
+// +/-50% uniform jitter applied if jitter is set.
+func computeBackoff(attempt int, initialBackoff, maxBackoff time.Duration, multiplier float64, jitter bool) time.Duration {
+	backoff := float64(initialBackoff)
+	for i := 1; i < attempt; i++ {
+		backoff *= multiplier
+	}
+	if backoff > float64(maxBackoff) {
+		backoff = float64(maxBackoff)
+	}
+
+	if jitter {
+		backoff = backoff*0.5 + rand.Float64()*backoff*0.5
+	}
+
+	return time.Duration(backoff)
+}
+
+// redactedURLForSpan strips url's query string before it's attached to a trace span: unlike log
+// output, which passes through the router's Redactor, span attributes go straight to the OTLP
+// exporter, and a query string is the most common place a templated secret (e.g. an API key) ends
+// up in a URL. An unparseable url is returned unchanged rather than dropped, since Validate already
+// rejects an empty call.url and a malformed one will fail at request-creation time regardless.
+func redactedURLForSpan(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.RawQuery == "" {
+		return rawURL
+	}
+	parsed.RawQuery = ""
+	return parsed.String()
+}
+
+// parseRetryAfter parses an HTTP Retry-After header value, either delta-seconds ("120") or an
+// HTTP-date, returning ok=false if header is empty or neither format applies.
+func parseRetryAfter(header string) (time.Duration, bool) {
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		if secs < 0 {
+			secs = 0
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
 }
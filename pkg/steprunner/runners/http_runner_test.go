@@ -0,0 +1,396 @@
+package runners_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/arnavsurve/dropstep/pkg/core"
+	"github.com/arnavsurve/dropstep/pkg/log"
+	"github.com/arnavsurve/dropstep/pkg/steprunner/runners"
+	"github.com/arnavsurve/dropstep/pkg/types"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHttpRunner_Run_RetriesOnRetryableStatusThenSucceeds(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	step := core.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method: "GET",
+			Url:    srv.URL,
+			Retry: &types.HTTPRetryPolicy{
+				MaxAttempts:    5,
+				InitialBackoff: "1ms",
+				MaxBackoff:     "5ms",
+				Multiplier:     2,
+			},
+		},
+	}
+
+	hr := &runners.HttpRunner{StepCtx: types.ExecutionContext{
+		Step:   step,
+		Logger: log.NewZerologAdapter(zerolog.New(io.Discard)),
+	}}
+
+	result, err := hr.Run(context.Background())
+	require.NoError(t, err)
+
+	output, ok := result.Output.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusOK, output["status_code"])
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestHttpRunner_Run_ExhaustsRetriesWithoutErroringOnStatusOnly(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	step := core.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method: "GET",
+			Url:    srv.URL,
+			Retry: &types.HTTPRetryPolicy{
+				MaxAttempts:    2,
+				InitialBackoff: "1ms",
+				MaxBackoff:     "2ms",
+			},
+		},
+	}
+
+	hr := &runners.HttpRunner{StepCtx: types.ExecutionContext{
+		Step:   step,
+		Logger: log.NewZerologAdapter(zerolog.New(io.Discard)),
+	}}
+
+	result, err := hr.Run(context.Background())
+	require.NoError(t, err, "a persistent non-2xx status should not make Run return an error, matching non-retry behavior")
+
+	output, ok := result.Output.(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusServiceUnavailable, output["status_code"])
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts), "should have made exactly max_attempts attempts")
+}
+
+func TestHttpRunner_Run_DoesNotRetryStatusOutsideRetryOn(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	step := core.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method: "GET",
+			Url:    srv.URL,
+			Retry: &types.HTTPRetryPolicy{
+				MaxAttempts: 3,
+				RetryOn:     []string{"5xx"},
+			},
+		},
+	}
+
+	hr := &runners.HttpRunner{StepCtx: types.ExecutionContext{
+		Step:   step,
+		Logger: log.NewZerologAdapter(zerolog.New(io.Discard)),
+	}}
+
+	result, err := hr.Run(context.Background())
+	require.NoError(t, err)
+
+	output := result.Output.(map[string]any)
+	assert.Equal(t, http.StatusNotFound, output["status_code"])
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts), "a 404 isn't in retry_on, so only one attempt should be made")
+}
+
+func TestHttpRunner_Run_AssertFailureReportsFailuresAndExtractsValues(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "abc123")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"data":{"items":[{"id":"first"},{"id":"second"}]}}`))
+	}))
+	defer srv.Close()
+
+	step := core.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method: "GET",
+			Url:    srv.URL,
+			Assert: &types.HTTPAssert{
+				StatusIn:      []int{201},
+				HeaderMatches: map[string]string{"X-Request-Id": "abc123"},
+				JSONPath: []types.HTTPJSONPathCheck{
+					{Path: "data.items.0.id", Expected: "first"},
+				},
+			},
+			Extract: map[string]string{"first_id": "data.items.0.id"},
+		},
+	}
+
+	hr := &runners.HttpRunner{StepCtx: types.ExecutionContext{
+		Step:   step,
+		Logger: log.NewZerologAdapter(zerolog.New(io.Discard)),
+	}}
+
+	result, err := hr.Run(context.Background())
+	require.Error(t, err, "status_in check should have failed")
+	require.Len(t, result.AssertionFailures, 1)
+	assert.Equal(t, "status_code", result.AssertionFailures[0].Path)
+
+	output := result.Output.(map[string]any)
+	extracted := output["extracted"].(map[string]any)
+	assert.Equal(t, "first", extracted["first_id"])
+}
+
+func TestHttpRunner_Run_AssertPassesWhenAllChecksHold(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer srv.Close()
+
+	step := core.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method: "GET",
+			Url:    srv.URL,
+			Assert: &types.HTTPAssert{
+				StatusIn: []int{200, 201},
+				JSONPath: []types.HTTPJSONPathCheck{
+					{Path: "status", Matches: "^ok$"},
+				},
+			},
+		},
+	}
+
+	hr := &runners.HttpRunner{StepCtx: types.ExecutionContext{
+		Step:   step,
+		Logger: log.NewZerologAdapter(zerolog.New(io.Discard)),
+	}}
+
+	result, err := hr.Run(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, result.AssertionFailures)
+}
+
+func TestHttpRunner_Run_FormBody(t *testing.T) {
+	var gotContentType string
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	step := core.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method:   "POST",
+			Url:      srv.URL,
+			BodyType: "form",
+			Body:     map[string]any{"username": "alice"},
+		},
+	}
+
+	hr := &runners.HttpRunner{StepCtx: types.ExecutionContext{
+		Step:   step,
+		Logger: log.NewZerologAdapter(zerolog.New(io.Discard)),
+	}}
+
+	_, err := hr.Run(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, "application/x-www-form-urlencoded", gotContentType)
+	values, err := url.ParseQuery(gotBody)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", values.Get("username"))
+}
+
+func TestHttpRunner_Run_RawBody(t *testing.T) {
+	var gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	step := core.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method:   "POST",
+			Url:      srv.URL,
+			BodyType: "raw",
+			RawBody:  "<xml>hi</xml>",
+			Headers:  map[string]string{"Content-Type": "application/xml"},
+		},
+	}
+
+	hr := &runners.HttpRunner{StepCtx: types.ExecutionContext{
+		Step:   step,
+		Logger: log.NewZerologAdapter(zerolog.New(io.Discard)),
+	}}
+
+	_, err := hr.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "<xml>hi</xml>", gotBody)
+}
+
+func TestHttpRunner_Run_MultipartBodyStreamsFileAndFields(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "upload.txt")
+	require.NoError(t, os.WriteFile(filePath, []byte("file contents"), 0644))
+
+	var gotFieldValue, gotFileContents string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		mr := multipart.NewReader(r.Body, params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			b, _ := io.ReadAll(part)
+			if part.FormName() == "note" {
+				gotFieldValue = string(b)
+			} else if part.FormName() == "upload" {
+				gotFileContents = string(b)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	step := core.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method:   "POST",
+			Url:      srv.URL,
+			BodyType: "multipart",
+			Body:     map[string]any{"note": "hello"},
+			Files:    map[string]string{"upload": "upload.txt"},
+		},
+	}
+
+	hr := &runners.HttpRunner{StepCtx: types.ExecutionContext{
+		Step:        step,
+		Logger:      log.NewZerologAdapter(zerolog.New(io.Discard)),
+		WorkflowDir: dir,
+	}}
+
+	_, err := hr.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "hello", gotFieldValue)
+	assert.Equal(t, "file contents", gotFileContents)
+}
+
+func TestHttpRunner_Run_SaveToStreamsBodyToFileAndOmitsItFromOutput(t *testing.T) {
+	dir := t.TempDir()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("the response body"))
+	}))
+	defer srv.Close()
+
+	step := core.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method: "GET",
+			Url:    srv.URL,
+			SaveTo: "download.bin",
+		},
+	}
+
+	hr := &runners.HttpRunner{StepCtx: types.ExecutionContext{
+		Step:        step,
+		Logger:      log.NewZerologAdapter(zerolog.New(io.Discard)),
+		WorkflowDir: dir,
+	}}
+
+	result, err := hr.Run(context.Background())
+	require.NoError(t, err)
+
+	wantPath := filepath.Join(dir, "download.bin")
+	assert.Equal(t, wantPath, result.OutputFile)
+
+	saved, err := os.ReadFile(wantPath)
+	require.NoError(t, err)
+	assert.Equal(t, "the response body", string(saved))
+
+	output := result.Output.(map[string]any)
+	assert.Equal(t, http.StatusOK, output["status_code"])
+	assert.Equal(t, int64(len("the response body")), output["body_size"])
+	_, hasBody := output["body"]
+	assert.False(t, hasBody, "body should not be buffered into Output when save_to is set")
+}
+
+func TestHttpRunner_Run_MaxBodyBytesExceededFailsWithoutSaveTo(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("this response is too long"))
+	}))
+	defer srv.Close()
+
+	step := core.Step{
+		ID:   "call",
+		Uses: "http",
+		Call: &types.HTTPCall{
+			Method:       "GET",
+			Url:          srv.URL,
+			MaxBodyBytes: 5,
+			Retry:        &types.HTTPRetryPolicy{MaxAttempts: 3, InitialBackoff: "1ms", MaxBackoff: "2ms"},
+		},
+	}
+
+	hr := &runners.HttpRunner{StepCtx: types.ExecutionContext{
+		Step:   step,
+		Logger: log.NewZerologAdapter(zerolog.New(io.Discard)),
+	}}
+
+	_, err := hr.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "max_body_bytes")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts), "exceeding max_body_bytes is a deterministic local failure, not worth retrying")
+}
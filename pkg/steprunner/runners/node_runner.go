@@ -0,0 +1,42 @@
+package runners
+
+import (
+	"context"
+
+	"github.com/arnavsurve/dropstep/pkg/steprunner"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// nodeLang leaves versionSubstring empty: `node --version` prints a bare "vX.Y.Z" with nothing
+// identifying the binary as node, so Validate only confirms the interpreter runs at all.
+var nodeLang = scriptLanguage{
+	name:               "node",
+	defaultInterpreter: "node",
+	inlineFlag:         "-e",
+}
+
+// NodeRunner runs a step's `run.inline`/`run.path` script through node, the same way ShellRunner
+// and PythonRunner run theirs through bash/python3.
+type NodeRunner struct {
+	StepCtx types.ExecutionContext
+
+	// CommandRunner executes the resolved command; nil defaults to DefaultCommandRunner at Run
+	// time. Exported so a test can inject a fake instead of spawning a real process.
+	CommandRunner CommandRunner
+}
+
+func init() {
+	steprunner.RegisterRunnerFactory("node", func(ctx types.ExecutionContext) (steprunner.StepRunner, error) {
+		return &NodeRunner{
+			StepCtx: ctx,
+		}, nil
+	})
+}
+
+func (nr *NodeRunner) Validate() error {
+	return validateScriptStep(nr.StepCtx, nodeLang)
+}
+
+func (nr *NodeRunner) Run(ctx context.Context) (*types.StepResult, error) {
+	return runScript(ctx, nr.StepCtx, nr.CommandRunner, nodeLang)
+}
@@ -0,0 +1,41 @@
+package runners
+
+import (
+	"context"
+
+	"github.com/arnavsurve/dropstep/pkg/steprunner"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+var rubyLang = scriptLanguage{
+	name:               "ruby",
+	defaultInterpreter: "ruby",
+	versionSubstring:   "ruby",
+	inlineFlag:         "-e",
+}
+
+// RubyRunner runs a step's `run.inline`/`run.path` script through ruby, the same way ShellRunner
+// and PythonRunner run theirs through bash/python3.
+type RubyRunner struct {
+	StepCtx types.ExecutionContext
+
+	// CommandRunner executes the resolved command; nil defaults to DefaultCommandRunner at Run
+	// time. Exported so a test can inject a fake instead of spawning a real process.
+	CommandRunner CommandRunner
+}
+
+func init() {
+	steprunner.RegisterRunnerFactory("ruby", func(ctx types.ExecutionContext) (steprunner.StepRunner, error) {
+		return &RubyRunner{
+			StepCtx: ctx,
+		}, nil
+	})
+}
+
+func (rr *RubyRunner) Validate() error {
+	return validateScriptStep(rr.StepCtx, rubyLang)
+}
+
+func (rr *RubyRunner) Run(ctx context.Context) (*types.StepResult, error) {
+	return runScript(ctx, rr.StepCtx, rr.CommandRunner, rubyLang)
+}
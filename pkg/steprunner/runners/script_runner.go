@@ -0,0 +1,564 @@
+package runners
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/arnavsurve/dropstep/pkg/assertions"
+	"github.com/arnavsurve/dropstep/pkg/fileutil"
+	"github.com/arnavsurve/dropstep/pkg/steprunner"
+	"github.com/arnavsurve/dropstep/pkg/summary"
+	"github.com/arnavsurve/dropstep/pkg/types"
+	"github.com/google/uuid"
+)
+
+// scriptLanguage configures the shared validateScriptStep/runScript logic for one interpreter-based
+// language. ShellRunner, PythonRunner, NodeRunner, and RubyRunner are each a thin struct that
+// supplies one of these and delegates; see those files for the registered `uses:` name each one
+// answers to.
+type scriptLanguage struct {
+	// name identifies the language in error and log messages, e.g. "python", "shell".
+	name string
+	// defaultInterpreter is the binary run when the step doesn't set `run.interpreter`.
+	defaultInterpreter string
+	// versionSubstring, if non-empty, must appear (case-insensitively) in the interpreter's
+	// `--version` output for Validate to accept it, e.g. "python" in "Python 3.11.4". Empty skips
+	// that check: node's --version output is just a bare "vX.Y.Z" with nothing to match against,
+	// so for node running the probe at all is the only signal Validate has.
+	versionSubstring string
+	// inlineFlag runs the next argv entry as a script body, e.g. "-c" for python/bash, "-e" for
+	// node/ruby.
+	inlineFlag string
+	// inlinePrefix is prepended to every inline script before it runs. Only shell sets this, to
+	// turn on `set -euo pipefail` so an inline script doesn't silently swallow a failing command.
+	inlinePrefix string
+}
+
+// validateCommandStepShape checks the parts of a Validate that every CommandBlock-driven runner
+// shares, interpreter-based or not: the step must be script-only (no browser/HTTP/max_failures
+// fields), define exactly one of `run.inline`, `run.path`, or `run.paths`, and have well-formed
+// secret_mounts. ContainerShellRunner calls this directly instead of validateScriptStep, since its
+// interpreter runs inside the container image rather than on the host and so can't be probed the
+// same way; it separately rejects `paths`/`continue_on_error`, since only a single script ever
+// runs per container.
+func validateCommandStepShape(step types.Step, lang scriptLanguage) error {
+	if step.BrowserConfig.Prompt != "" {
+		return fmt.Errorf("%s step %q must not define 'browser.prompt'", lang.name, step.ID)
+	}
+	if step.BrowserConfig.UploadFiles != nil {
+		return fmt.Errorf("%s step %q must not define 'browser.upload_files'", lang.name, step.ID)
+	}
+	if step.BrowserConfig.TargetDownloadDir != "" {
+		return fmt.Errorf("%s step %q must not define 'browser.download_dir'", lang.name, step.ID)
+	}
+	if step.BrowserConfig.OutputSchemaFile != "" {
+		return fmt.Errorf("%s step %q must not define 'browser.output_schema'", lang.name, step.ID)
+	}
+	if step.Call != nil {
+		return fmt.Errorf("%s step %q must not define 'call'", lang.name, step.ID)
+	}
+	if step.BrowserConfig.AllowedDomains != nil {
+		return fmt.Errorf("%s step %q must not define 'browser.allowed_domains'", lang.name, step.ID)
+	}
+	if step.BrowserConfig.MaxSteps != nil {
+		return fmt.Errorf("%s step %q must not define 'browser.max_steps'", lang.name, step.ID)
+	}
+	if step.MaxFailures != nil {
+		return fmt.Errorf("%s step %q must not define 'max_failures'", lang.name, step.ID)
+	}
+
+	if step.Command == nil {
+		return fmt.Errorf("%s step %q must define 'run'", lang.name, step.ID)
+	} else {
+		formsSet := 0
+		if step.Command.Inline != "" {
+			formsSet++
+		}
+		if step.Command.Path != "" {
+			formsSet++
+		}
+		if len(step.Command.Paths) > 0 {
+			formsSet++
+		}
+		if formsSet > 1 {
+			return fmt.Errorf("%s step %q must only define one of 'inline', 'path', or 'paths'", lang.name, step.ID)
+		}
+		if formsSet == 0 {
+			return fmt.Errorf("%s step %q must define one of 'inline', 'path', or 'paths'", lang.name, step.ID)
+		}
+	}
+
+	for i, m := range step.SecretMounts {
+		if m.Name == "" {
+			return fmt.Errorf("%s step %q: secret_mounts[%d] is missing 'name'", lang.name, step.ID, i)
+		}
+		if m.InputRef == "" {
+			return fmt.Errorf("%s step %q: secret_mounts[%d] is missing 'input_ref'", lang.name, step.ID, i)
+		}
+	}
+
+	return nil
+}
+
+// validateScriptStep is the shared Validate body for every interpreter-based runner: the step must
+// be script-only (no browser/HTTP/max_failures fields), define exactly one of `run.inline`,
+// `run.path`, or `run.paths`, have well-formed secret_mounts, resolve to a real, runnable
+// interpreter binary, and (for `path`/`paths`) resolve to at least one real script file.
+func validateScriptStep(stepCtx types.ExecutionContext, lang scriptLanguage) error {
+	step := stepCtx.Step
+	if err := validateCommandStepShape(step, lang); err != nil {
+		return err
+	}
+
+	if step.Command.Inline != "" {
+		if step.Command.ContinueOnError {
+			return fmt.Errorf("%s step %q must not define 'continue_on_error' with 'inline': there's only one script to continue past", lang.name, step.ID)
+		}
+	} else {
+		files, err := resolveCommandScriptPaths(stepCtx.WorkflowDir, step.Command.Path, step.Command.Paths)
+		if err != nil {
+			return fmt.Errorf("%s step %q: %w", lang.name, step.ID, err)
+		}
+		if step.Command.ContinueOnError && len(step.Command.Paths) == 0 && len(files) <= 1 {
+			return fmt.Errorf("%s step %q must not define 'continue_on_error' with a single-file 'path': there's only one script to continue past", lang.name, step.ID)
+		}
+	}
+
+	interpreter := lang.defaultInterpreter
+	if step.Command.Interpreter != "" {
+		interpreter = step.Command.Interpreter
+	}
+
+	// #nosec G204
+	cmd := exec.Command(interpreter, "--version")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("interpreter %q is not a valid command: %w. Make sure it's in your PATH", interpreter, err)
+	}
+
+	if lang.versionSubstring != "" && !strings.Contains(strings.ToLower(out.String()), lang.versionSubstring) {
+		return fmt.Errorf("command %q does not appear to be a %s interpreter. Output: %s", interpreter, lang.name, out.String())
+	}
+
+	return nil
+}
+
+// runScript is the shared Run body for every interpreter-based runner: resolve the script (inline,
+// a single path on disk, or multiple via a glob/`paths`), run it through commandRunner with the
+// step's scoped env, and turn its stdout/stderr/exit code into a types.StepResult the same way
+// regardless of language. A path or pattern that resolves to more than one file delegates to
+// runMultiScript instead; a single match runs exactly the way it always has.
+func runScript(ctx context.Context, stepCtx types.ExecutionContext, commandRunner CommandRunner, lang scriptLanguage) (*types.StepResult, error) {
+	step := stepCtx.Step
+	logger := stepCtx.Logger
+	workflowDir := stepCtx.WorkflowDir
+
+	interpreter := lang.defaultInterpreter
+	if step.Command.Interpreter != "" {
+		interpreter = step.Command.Interpreter
+	}
+
+	isInline := step.Command.Inline != ""
+	if !isInline {
+		files, err := resolveCommandScriptPaths(workflowDir, step.Command.Path, step.Command.Paths)
+		if err != nil {
+			return nil, err
+		}
+		if len(step.Command.Paths) > 0 || len(files) > 1 {
+			return runMultiScript(ctx, stepCtx, commandRunner, lang, interpreter, files)
+		}
+		step.Command.Path = files[0]
+	}
+
+	var path string
+	var args []string
+	if isInline {
+		path, args = inlineScriptCommand(stepCtx, interpreter, lang)
+	} else {
+		path, args = interpreter, []string{step.Command.Path}
+	}
+
+	outputFilePath := filepath.Join(os.TempDir(), fmt.Sprintf("dropstep-output-%s-%s", step.ID, uuid.New().String()))
+	defer os.Remove(outputFilePath)
+
+	var resources *types.ResourceLimits
+	if step.Command.Resources != nil {
+		resources = step.Command.Resources
+	}
+	env := append(scopeEnv(os.Environ(), envScopeOf(resources)), "DROPSTEP_OUTPUT="+outputFilePath)
+	if stepCtx.PathDirs != nil {
+		env = prependPath(env, stepCtx.PathDirs())
+	}
+	if stepCtx.StepSummaryPath != "" {
+		env = append(env, summary.EnvVar+"="+stepCtx.StepSummaryPath)
+	}
+	env = append(env, stepCtx.SecretMountEnv...)
+	env = append(env, matrixEnv(stepCtx.MatrixVars)...)
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	reporter := steprunner.NewProgressReporter(os.Stderr, stepCtx.NoProgress, stepCtx.Silent)
+	stdoutWriter, waitStream := newStreamingStdout(&stdoutBuf, logger, reporter)
+
+	if commandRunner == nil {
+		commandRunner = DefaultCommandRunner{}
+	}
+
+	logger.Info().Str(lang.name, interpreter).Msgf("Starting %s script execution", lang.name)
+
+	out, err := commandRunner.Run(ctx, CommandSpec{
+		Path:        path,
+		Args:        args,
+		Env:         env,
+		WorkflowDir: workflowDir,
+		Stdout:      stdoutWriter,
+		Stderr:      &stderrBuf,
+		Resources:   resources,
+	})
+	streamEffects := waitStream()
+	if err != nil {
+		return nil, fmt.Errorf("executing script: %w", err)
+	}
+
+	return finalizeCommandResult(stepCtx, lang.name, out, streamEffects, &stdoutBuf, &stderrBuf, outputFilePath)
+}
+
+// resolveCommandScriptPaths expands a step's run.path/run.paths into the concrete, sorted list of
+// script files it runs. If paths is non-empty it's used verbatim, each entry resolved relative to
+// workflowDir like any other workflow path; otherwise path is resolved the same way and expanded
+// as a glob pattern (filepath.Glob: `*`/`?`/character classes, no doublestar `**`). Both forms
+// error if they resolve to nothing, so an empty match is caught here - at validation time via
+// validateScriptStep, and again at run time - rather than surfacing as a confusing "file not
+// found" deeper in the runner.
+func resolveCommandScriptPaths(workflowDir, path string, paths []string) ([]string, error) {
+	if len(paths) > 0 {
+		resolved := make([]string, 0, len(paths))
+		for _, p := range paths {
+			rp, err := fileutil.ResolvePathFromWorkflow(workflowDir, p)
+			if err != nil {
+				return nil, fmt.Errorf("resolving path %q: %w", p, err)
+			}
+			if _, err := os.Stat(rp); err != nil {
+				return nil, fmt.Errorf("script file not found at %q: %w", rp, err)
+			}
+			resolved = append(resolved, rp)
+		}
+		return resolved, nil
+	}
+
+	resolvedPattern, err := fileutil.ResolvePathFromWorkflow(workflowDir, path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving path %q: %w", path, err)
+	}
+	matches, err := filepath.Glob(resolvedPattern)
+	if err != nil {
+		return nil, fmt.Errorf("expanding path pattern %q: %w", path, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("path %q matched no files", path)
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// runMultiScript runs every file in files (already resolved and sorted into lexical order by
+// resolveCommandScriptPaths) through interpreter in sequence, one CommandRunner.Run per file,
+// aggregating them into the step's result as `result.output.scripts`, an ordered list of
+// {path, stdout, stderr, exit_code, json?} (json is set only when that script's stdout parses as
+// JSON). A single CommandOutput's worth of finalizeCommandResult logic doesn't fit multiple
+// processes, so this builds the types.StepResult directly instead of delegating to it. Unless
+// step.Command.ContinueOnError is set, a script exiting non-zero stops the remaining ones, the
+// same fail-fast behavior a hand-written `set -e` shell script gives; either way the step reports
+// failure overall if any script did.
+func runMultiScript(ctx context.Context, stepCtx types.ExecutionContext, commandRunner CommandRunner, lang scriptLanguage, interpreter string, files []string) (*types.StepResult, error) {
+	step := stepCtx.Step
+	logger := stepCtx.Logger
+
+	if commandRunner == nil {
+		commandRunner = DefaultCommandRunner{}
+	}
+
+	var resources *types.ResourceLimits
+	if step.Command.Resources != nil {
+		resources = step.Command.Resources
+	}
+
+	scripts := make([]any, 0, len(files))
+	var outputs map[string]string
+	var artifacts map[string]types.ArtifactResult
+	failedCount := 0
+	var lastStdout, lastStderr string
+	var lastExitCode int
+
+	for _, file := range files {
+		var stdoutBuf, stderrBuf bytes.Buffer
+		reporter := steprunner.NewProgressReporter(os.Stderr, stepCtx.NoProgress, stepCtx.Silent)
+		stdoutWriter, waitStream := newStreamingStdout(&stdoutBuf, logger, reporter)
+
+		outputFilePath := filepath.Join(os.TempDir(), fmt.Sprintf("dropstep-output-%s-%s", step.ID, uuid.New().String()))
+		env := append(scopeEnv(os.Environ(), envScopeOf(resources)), "DROPSTEP_OUTPUT="+outputFilePath)
+		if stepCtx.PathDirs != nil {
+			env = prependPath(env, stepCtx.PathDirs())
+		}
+		if stepCtx.StepSummaryPath != "" {
+			env = append(env, summary.EnvVar+"="+stepCtx.StepSummaryPath)
+		}
+		env = append(env, stepCtx.SecretMountEnv...)
+		env = append(env, matrixEnv(stepCtx.MatrixVars)...)
+
+		logger.Info().Str(lang.name, interpreter).Str("script", file).Msgf("Starting %s script execution", lang.name)
+
+		out, err := commandRunner.Run(ctx, CommandSpec{
+			Path:        interpreter,
+			Args:        []string{file},
+			Env:         env,
+			WorkflowDir: stepCtx.WorkflowDir,
+			Stdout:      stdoutWriter,
+			Stderr:      &stderrBuf,
+			Resources:   resources,
+		})
+		streamEffects := waitStream()
+		fileOutputs, ferr := steprunner.ReadOutputFile(outputFilePath)
+		os.Remove(outputFilePath)
+		if ferr != nil {
+			logger.Warn().Err(ferr).Str("script", file).Msg("Failed to read DROPSTEP_OUTPUT file")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("executing script %q: %w", file, err)
+		}
+
+		stderrEffects := steprunner.LogBuffer(strings.NewReader(stderrBuf.String()), "STDERR", logger, lang.name+"_line", stepCtx.AddMask, stepCtx.AddPath)
+		stdoutEffects := steprunner.LogBuffer(strings.NewReader(stdoutBuf.String()), "STDOUT", logger, lang.name+"_line", stepCtx.AddMask, stepCtx.AddPath)
+		outputs = mergeOutputs(outputs, stderrEffects.Outputs, stdoutEffects.Outputs, streamEffects.Outputs, fileOutputs)
+		for name, res := range streamEffects.Artifacts {
+			if artifacts == nil {
+				artifacts = make(map[string]types.ArtifactResult, len(streamEffects.Artifacts))
+			}
+			artifacts[name] = res
+		}
+
+		stdout := strings.TrimSpace(stdoutBuf.String())
+		stderr := stderrBuf.String()
+		entry := map[string]any{
+			"path":      file,
+			"stdout":    stdout,
+			"stderr":    stderr,
+			"exit_code": out.ExitCode,
+		}
+		var parsed any
+		if json.Unmarshal([]byte(stdout), &parsed) == nil {
+			entry["json"] = parsed
+		}
+		scripts = append(scripts, entry)
+		lastStdout, lastStderr, lastExitCode = stdout, stderr, out.ExitCode
+
+		if out.WaitErr != nil {
+			failedCount++
+			if !step.Command.ContinueOnError {
+				break
+			}
+		}
+	}
+
+	// Mirrors finalizeCommandResult's single-script behavior: a `via: stdout` artifact captures the
+	// last script's stdout, the same script whose output the top-level stdout/stderr/exit_code above
+	// reflect.
+	if err := writeStdoutArtifacts(step.Artifacts, stepCtx.WorkflowDir, []byte(lastStdout)); err != nil {
+		return nil, err
+	}
+
+	output := map[string]any{"scripts": scripts}
+
+	// scripts is a []any (not []map[string]any) so an assertion path like "result.scripts.#.exit_code"
+	// can use assertions.navigate's "#" wildcard to inspect every script's result at once; there's no
+	// indexing into a single element (see assertions.navigate), so the top-level
+	// stdout/stderr/exit_code here reflect only the last script that ran, the same way a bash
+	// pipeline's own $? only reflects its last command.
+	if len(step.Assertions) > 0 {
+		failures := assertions.Evaluate(step.Assertions, output, lastStdout, lastStderr, lastExitCode)
+		for _, f := range failures {
+			logger.Error().
+				Str("assertion_path", f.Path).
+				Str("assertion_should", f.Should).
+				Interface("assertion_expected", f.Expected).
+				Interface("assertion_actual", f.Actual).
+				Msg(f.Reason)
+		}
+		result := &types.StepResult{Output: output, Outputs: outputs, Artifacts: artifacts, AssertionFailures: failures}
+		if len(failures) > 0 {
+			return result, fmt.Errorf("step %q failed %d assertion(s)", step.ID, len(failures))
+		}
+		logger.Info().Msg("All assertions passed")
+		return result, nil
+	}
+
+	result := &types.StepResult{Output: output, Outputs: outputs, Artifacts: artifacts}
+	if failedCount > 0 {
+		return result, fmt.Errorf("%s step %q: %d of %d script(s) failed", lang.name, step.ID, failedCount, len(scripts))
+	}
+
+	logger.Info().Msg("All scripts executed successfully")
+	return result, nil
+}
+
+// finalizeCommandResult turns a finished CommandOutput into a types.StepResult: it merges outputs
+// collected from stdout/stderr workflow commands, stream events, and the DROPSTEP_OUTPUT file;
+// writes any `via: stdout` artifacts; promotes JSON stdout to a structured Output; and either
+// evaluates the step's assertions or falls back to exit-code success/failure. Shared by every
+// CommandRunner-based runner (the interpreter-based ones via runScript, and ContainerShellRunner)
+// since none of this differs once a CommandOutput exists, regardless of what process produced it.
+// label identifies the runner in log/error messages, e.g. "shell", "container_shell".
+func finalizeCommandResult(stepCtx types.ExecutionContext, label string, out *CommandOutput, streamEffects *steprunner.StreamEffects, stdoutBuf, stderrBuf *bytes.Buffer, outputFilePath string) (*types.StepResult, error) {
+	step := stepCtx.Step
+	logger := stepCtx.Logger
+	workflowDir := stepCtx.WorkflowDir
+
+	waitErr := out.WaitErr
+
+	stderrEffects := steprunner.LogBuffer(strings.NewReader(stderrBuf.String()), "STDERR", logger, label+"_line", stepCtx.AddMask, stepCtx.AddPath)
+	stdoutEffects := steprunner.LogBuffer(strings.NewReader(stdoutBuf.String()), "STDOUT", logger, label+"_line", stepCtx.AddMask, stepCtx.AddPath)
+
+	outputs := mergeOutputs(stderrEffects.Outputs, stdoutEffects.Outputs, streamEffects.Outputs)
+	fileOutputs, err := steprunner.ReadOutputFile(outputFilePath)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Failed to read DROPSTEP_OUTPUT file")
+	} else {
+		outputs = mergeOutputs(outputs, fileOutputs)
+	}
+
+	exitCode := out.ExitCode
+
+	if err := writeStdoutArtifacts(step.Artifacts, workflowDir, stdoutBuf.Bytes()); err != nil {
+		return nil, err
+	}
+
+	stdout := strings.TrimSpace(stdoutBuf.String())
+	var structuredOutput map[string]any
+	var output any = stdout
+	hasStdoutArtifact := false
+	for _, spec := range step.Artifacts {
+		if spec.Via == "stdout" {
+			hasStdoutArtifact = true
+			break
+		}
+	}
+	if !hasStdoutArtifact {
+		if err := json.Unmarshal([]byte(stdout), &structuredOutput); err == nil {
+			output = structuredOutput
+		}
+	}
+
+	// A step that declares assertions is judged solely on whether they hold, not on its exit
+	// code, so a nonzero exit that the assertions don't care about doesn't fail the step (and a
+	// zero exit that fails an assertion does).
+	if len(step.Assertions) > 0 {
+		failures := assertions.Evaluate(step.Assertions, output, stdout, stderrBuf.String(), exitCode)
+		for _, f := range failures {
+			logger.Error().
+				Str("assertion_path", f.Path).
+				Str("assertion_should", f.Should).
+				Interface("assertion_expected", f.Expected).
+				Interface("assertion_actual", f.Actual).
+				Msg(f.Reason)
+		}
+		result := &types.StepResult{Output: output, Outputs: outputs, Artifacts: streamEffects.Artifacts, AssertionFailures: failures}
+		if len(failures) > 0 {
+			return result, fmt.Errorf("step %q failed %d assertion(s)", step.ID, len(failures))
+		}
+		logger.Info().Msg("All assertions passed")
+		return result, nil
+	}
+
+	if waitErr != nil {
+		if exitErr, ok := waitErr.(*exec.ExitError); ok {
+			logger.Error().Int("exit_code", exitErr.ExitCode()).Msg("Script exited with non-zero code")
+		}
+		return nil, fmt.Errorf("%s script failed: %w", label, waitErr)
+	}
+
+	logger.Info().Msg("Script executed successfully")
+
+	if structuredOutput != nil {
+		logger.Debug().Msg("Output was valid JSON, promoting to structured output.")
+	} else {
+		logger.Debug().Msg("Output was not JSON, treating as raw string output.")
+	}
+	return &types.StepResult{Output: output, Outputs: outputs, Artifacts: streamEffects.Artifacts}, nil
+}
+
+// inlineScriptCommand builds the argv that runs a step's inline script body through interpreter,
+// applying lang's inline flag and prefix (e.g. shell's "set -euo pipefail\n").
+func inlineScriptCommand(stepCtx types.ExecutionContext, interpreter string, lang scriptLanguage) (string, []string) {
+	logger := stepCtx.Logger
+	inlineScript := stepCtx.Step.Command.Inline
+	if len(inlineScript) > 1000 {
+		logger.Warn().Msgf("Long script in 'inline' - consider passing a script file as 'path' for maintainability.")
+	}
+	return interpreter, []string{lang.inlineFlag, lang.inlinePrefix + inlineScript}
+}
+
+// writeStdoutArtifacts writes stdout to every artifact spec in artifacts that declares `via:
+// stdout`, so the engine's later, generic artifact-archiving step finds an ordinary file on disk
+// at spec.Path just like it would for `via: file`/`via: dir`. Shared by every script runner.
+func writeStdoutArtifacts(artifactSpecs []types.ArtifactSpec, workflowDir string, stdout []byte) error {
+	for _, spec := range artifactSpecs {
+		if spec.Via != "stdout" {
+			continue
+		}
+		destPath, err := fileutil.ResolvePathFromWorkflow(workflowDir, spec.Path)
+		if err != nil {
+			return fmt.Errorf("resolving artifact %q destination: %w", spec.Name, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
+			return fmt.Errorf("creating directory for artifact %q: %w", spec.Name, err)
+		}
+		if err := os.WriteFile(destPath, stdout, 0644); err != nil {
+			return fmt.Errorf("writing artifact %q: %w", spec.Name, err)
+		}
+	}
+	return nil
+}
+
+// mergeOutputs combines outputs collected from multiple sources (stdout, stderr, the
+// DROPSTEP_OUTPUT file), with later maps taking precedence on key conflicts.
+func mergeOutputs(sources ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, src := range sources {
+		for k, v := range src {
+			merged[k] = v
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// matrixEnv renders a step's matrix coordinates (see types.ExecutionContext.MatrixVars) as
+// "DROPSTEP_MATRIX_<NAME>=<value>" env entries, sorted by name for deterministic ordering. Shared
+// by every script runner.
+func matrixEnv(vars map[string]string) []string {
+	if len(vars) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	env := make([]string, 0, len(names))
+	for _, name := range names {
+		env = append(env, "DROPSTEP_MATRIX_"+strings.ToUpper(name)+"="+vars[name])
+	}
+	return env
+}
@@ -0,0 +1,51 @@
+package runners
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"github.com/arnavsurve/dropstep/pkg/steprunner"
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// maxStreamLineSize bounds a single buffered stdout line the scanner in newStreamingStdout will
+// hold in memory; a line over this size is treated as plain output rather than a stream event.
+const maxStreamLineSize = 1024 * 1024
+
+// newStreamingStdout returns a writer to use as CommandSpec.Stdout that both accumulates raw bytes
+// into buf (for the existing post-exit JSON/assertion handling, unchanged) and, concurrently, scans
+// completed lines for steprunner.StreamEvent sentinels, applying each one in real time via
+// steprunner.ApplyStreamEvent as the process runs rather than waiting for it to exit. A "progress"
+// event drives reporter's live display as well as the usual log line; reporter may be nil if the
+// caller isn't tracking progress. The returned wait func must be called once the CommandRunner has
+// returned; it closes the pipe, blocks until the scanning goroutine has drained it, clears
+// reporter's display, and returns the accumulated effects.
+func newStreamingStdout(buf *bytes.Buffer, logger types.Logger, reporter steprunner.ProgressReporter) (io.Writer, func() *steprunner.StreamEffects) {
+	pr, pw := io.Pipe()
+	effects := &steprunner.StreamEffects{}
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		scanner := bufio.NewScanner(pr)
+		scanner.Buffer(make([]byte, 4096), maxStreamLineSize)
+		for scanner.Scan() {
+			if event, ok := steprunner.ParseStreamEvent(scanner.Text()); ok {
+				steprunner.ApplyStreamEvent(event, logger, effects, reporter)
+			}
+		}
+		// Drain and discard whatever's left if the scanner gave up (e.g. a line over
+		// maxStreamLineSize); buf already has the raw bytes via the MultiWriter below.
+		io.Copy(io.Discard, pr)
+	}()
+
+	return io.MultiWriter(buf, pw), func() *steprunner.StreamEffects {
+		pw.Close()
+		<-done
+		if reporter != nil {
+			reporter.Finish()
+		}
+		return effects
+	}
+}
@@ -7,13 +7,27 @@ import (
 	"github.com/arnavsurve/dropstep/pkg/types"
 )
 
-// LogBuffer is a shared helper to stream reader content to a structured logger
-func LogBuffer(r io.Reader, source string, logger types.Logger, logKey string) {
+// LogBuffer streams reader content to a structured logger, one event per line. Lines recognized as
+// a workflow command (see ParseWorkflowCommand) are stripped and applied as a side effect instead
+// of being logged verbatim: "::add-mask::" registers a secret via addMask (may be nil if the caller
+// has no redactor to register against), "::add-path::" registers a directory via addPath (may be
+// nil if the caller has no accumulator to register against), "::group::"/"::endgroup::" emit paired
+// events a console sink can use to indent, "::notice::"/"::warning::"/"::error::"/"::debug::" become
+// leveled annotated events, and "::set-output::" is accumulated into the returned CommandEffects for
+// the caller to attach to its StepResult.
+func LogBuffer(r io.Reader, source string, logger types.Logger, logKey string, addMask func(string), addPath func(string)) *CommandEffects {
+	effects := &CommandEffects{Outputs: make(map[string]string)}
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
+		line := scanner.Text()
+		if cmd, ok := ParseWorkflowCommand(line); ok {
+			handleWorkflowCommand(cmd, logger, addMask, addPath, effects)
+			continue
+		}
 		logger.Info().
 			Str("source", source).
-			Str(logKey, scanner.Text()).
+			Str(logKey, line).
 			Msg("Script output")
 	}
+	return effects
 }
@@ -0,0 +1,114 @@
+package steprunner
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// StreamSentinel prefixes a stdout line carrying a JSON-encoded StreamEvent from a shell/python
+// step, as opposed to one of the GitHub Actions-style "::name::" directives ParseWorkflowCommand
+// handles. The two protocols are kept separate: "::name::" lines are value-only and processed
+// after the fact from the step's captured stdout, while StreamEvent lines carry structured
+// payloads and are processed as they arrive, letting a long-running script report partial progress
+// and results in real time instead of an all-or-nothing JSON blob at the end.
+const StreamSentinel = "##dropstep##"
+
+// StreamEvent is a single structured telemetry event, one JSON object per stdout line.
+type StreamEvent struct {
+	Type string `json:"type"` // "log", "progress", "artifact", or "output"
+
+	// log
+	Level   string `json:"level,omitempty"` // debug, info, warn, error; defaults to info
+	Message string `json:"message,omitempty"`
+
+	// progress
+	Percent *int `json:"percent,omitempty"`
+
+	// artifact
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+
+	// output: either a single key/value pair, a whole map to merge, or both
+	Key    string            `json:"key,omitempty"`
+	Value  string            `json:"value,omitempty"`
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// StreamEffects accumulates what ApplyStreamEvent can't apply itself and must hand back to the
+// runner: outputs to merge into the step's structured Output, and artifacts registered dynamically
+// (in addition to any declared statically in step.artifacts).
+type StreamEffects struct {
+	Outputs   map[string]string
+	Artifacts map[string]types.ArtifactResult
+}
+
+// ParseStreamEvent parses line as a StreamEvent if it carries StreamSentinel and valid JSON after
+// it; ok is false (the caller should treat line as plain output) otherwise.
+func ParseStreamEvent(line string) (event StreamEvent, ok bool) {
+	rest, found := strings.CutPrefix(line, StreamSentinel)
+	if !found {
+		return StreamEvent{}, false
+	}
+	if err := json.Unmarshal([]byte(rest), &event); err != nil {
+		return StreamEvent{}, false
+	}
+	return event, true
+}
+
+// ApplyStreamEvent applies event's side effect: a "log" event is routed through logger at its
+// level, a "progress" event is logged as a structured progress line and, if reporter is non-nil,
+// also drives its live display (see ProgressReporter), an "artifact" event is recorded into
+// effects.Artifacts, and an "output" event merges into effects.Outputs. effects must be non-nil;
+// reporter may be nil if the caller isn't tracking progress.
+func ApplyStreamEvent(event StreamEvent, logger types.Logger, effects *StreamEffects, reporter ProgressReporter) {
+	switch event.Type {
+	case "log":
+		logAtLevel(logger, event.Level).Msg(event.Message)
+	case "progress":
+		ev := logger.Info().Str("progress_message", event.Message)
+		if event.Percent != nil {
+			ev = ev.Int("progress_percent", *event.Percent)
+		}
+		ev.Msg("Step progress")
+		if reporter != nil && event.Percent != nil {
+			reporter.Start(100, event.Message)
+			reporter.Update(int64(*event.Percent))
+		}
+	case "artifact":
+		if event.Name == "" || event.Path == "" {
+			logger.Warn().Str("event_type", event.Type).Msg("Ignoring artifact stream event missing 'name' or 'path'")
+			return
+		}
+		if effects.Artifacts == nil {
+			effects.Artifacts = make(map[string]types.ArtifactResult)
+		}
+		effects.Artifacts[event.Name] = types.ArtifactResult{Path: event.Path}
+	case "output":
+		if effects.Outputs == nil {
+			effects.Outputs = make(map[string]string)
+		}
+		if event.Key != "" {
+			effects.Outputs[event.Key] = event.Value
+		}
+		for k, v := range event.Values {
+			effects.Outputs[k] = v
+		}
+	default:
+		logger.Debug().Str("event_type", event.Type).Msg("Ignoring unrecognized stream event type")
+	}
+}
+
+func logAtLevel(logger types.Logger, level string) types.Event {
+	switch level {
+	case "debug":
+		return logger.Debug()
+	case "warn", "warning":
+		return logger.Warn()
+	case "error":
+		return logger.Error()
+	default:
+		return logger.Info()
+	}
+}
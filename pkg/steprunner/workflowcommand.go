@@ -0,0 +1,167 @@
+package steprunner
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/arnavsurve/dropstep/pkg/types"
+)
+
+// workflowCommandRegex matches GitHub Actions-style inline workflow commands emitted on a step's
+// stdout, e.g. "::add-mask::supersecret" or "::error file=main.go,line=12::something broke".
+var workflowCommandRegex = regexp.MustCompile(`^::([a-zA-Z0-9_-]+)(?:\s+([^:]*))?::(.*)$`)
+
+// WorkflowCommand is a single directive parsed from a line of step output.
+type WorkflowCommand struct {
+	Name       string
+	Parameters map[string]string
+	Value      string
+}
+
+// CommandEffects accumulates the side effects of workflow commands parsed from a step's output
+// that the runner can't apply itself and must hand back to its caller: outputs to expose to later
+// steps as {{ steps.<id>.outputs.<name> }}.
+type CommandEffects struct {
+	Outputs map[string]string
+}
+
+// ParseWorkflowCommand parses a single line as a workflow command. It accepts both the short form
+// ("::name::value") and the parameterized form ("::name key1=val1,key2=val2::value"); ok is false
+// if line isn't a recognized directive.
+func ParseWorkflowCommand(line string) (cmd WorkflowCommand, ok bool) {
+	matches := workflowCommandRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return WorkflowCommand{}, false
+	}
+
+	cmd = WorkflowCommand{
+		Name:       matches[1],
+		Parameters: map[string]string{},
+		Value:      unescapeCommandData(matches[3]),
+	}
+
+	for _, pair := range strings.Split(matches[2], ",") {
+		if pair == "" {
+			continue
+		}
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		cmd.Parameters[strings.TrimSpace(kv[0])] = unescapeCommandData(strings.TrimSpace(kv[1]))
+	}
+
+	return cmd, true
+}
+
+func unescapeCommandData(s string) string {
+	return strings.NewReplacer("%0D", "\r", "%0A", "\n", "%25", "%").Replace(s)
+}
+
+// handleWorkflowCommand applies a parsed command's effect: registering a secret mask, extending
+// PATH for later steps, opening or closing a console log group, emitting a leveled annotation, or
+// recording a step output. addMask/addPath may be nil if the caller has no redactor/accumulator to
+// register against, in which case "::add-mask::"/"::add-path::" are no-ops beyond being stripped
+// from the printed output.
+func handleWorkflowCommand(cmd WorkflowCommand, logger types.Logger, addMask func(string), addPath func(string), effects *CommandEffects) {
+	switch cmd.Name {
+	case "add-mask":
+		if addMask != nil && cmd.Value != "" {
+			addMask(cmd.Value)
+		}
+	case "add-path":
+		if addPath != nil && cmd.Value != "" {
+			addPath(cmd.Value)
+		}
+	case "set-output":
+		name := cmd.Parameters["name"]
+		if name != "" && effects != nil {
+			effects.Outputs[name] = cmd.Value
+		}
+	case "group":
+		logger.Info().Str("group_start", cmd.Value).Msg(cmd.Value)
+	case "endgroup":
+		logger.Info().Str("group_end", "true").Msg("")
+	case "debug":
+		logger.Debug().Msg(cmd.Value)
+	case "notice":
+		annotateEvent(logger.Info(), cmd).Msg(cmd.Value)
+	case "warning":
+		annotateEvent(logger.Warn(), cmd).Msg(cmd.Value)
+	case "error":
+		annotateEvent(logger.Error(), cmd).Msg(cmd.Value)
+	default:
+		// Unrecognized directive name; log it verbatim so it isn't silently swallowed.
+		logger.Debug().Str("command", cmd.Name).Msg("Ignoring unrecognized workflow command")
+	}
+}
+
+// annotateEvent attaches the well-known notice/warning/error annotation parameters (file, line,
+// col, title) to event as string fields, for whichever ones the directive actually supplied.
+func annotateEvent(event types.Event, cmd WorkflowCommand) types.Event {
+	for _, key := range []string{"file", "line", "col", "endLine", "endColumn", "title"} {
+		if v, ok := cmd.Parameters[key]; ok {
+			event = event.Str(key, v)
+		}
+	}
+	return event
+}
+
+// HandleLine processes a single line of step output as a potential workflow command, for callers
+// that stream output themselves rather than going through LogBuffer (e.g. the agent runner, which
+// scans stdout/stderr concurrently via goroutines). It returns true if line was a recognized
+// command and has been applied as a side effect; false means the caller should log it normally.
+func HandleLine(line string, logger types.Logger, addMask func(string), addPath func(string), effects *CommandEffects) bool {
+	cmd, ok := ParseWorkflowCommand(line)
+	if !ok {
+		return false
+	}
+	handleWorkflowCommand(cmd, logger, addMask, addPath, effects)
+	return true
+}
+
+// ReadOutputFile parses the file-based form of "::set-output::", where a step writes one or more
+// "name<<DELIMITER\nvalue\nDELIMITER" blocks to the file named by the DROPSTEP_OUTPUT environment
+// variable, letting multi-line values be set safely without needing to escape newlines. It returns
+// an empty, non-nil map if path is empty or the file doesn't exist (the step may not have used it).
+func ReadOutputFile(path string) (map[string]string, error) {
+	outputs := make(map[string]string)
+	if path == "" {
+		return outputs, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return outputs, nil
+		}
+		return nil, fmt.Errorf("opening output file %q: %w", path, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		header := scanner.Text()
+		name, delimiter, ok := strings.Cut(header, "<<")
+		if !ok || name == "" || delimiter == "" {
+			continue
+		}
+
+		var lines []string
+		for scanner.Scan() {
+			if scanner.Text() == delimiter {
+				break
+			}
+			lines = append(lines, scanner.Text())
+		}
+		outputs[name] = strings.Join(lines, "\n")
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading output file %q: %w", path, err)
+	}
+
+	return outputs, nil
+}
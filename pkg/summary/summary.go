@@ -0,0 +1,190 @@
+// Package summary builds the per-run Markdown "step summary" report: a single shareable file
+// combining run metadata, a table of contents, each step's own contributed Markdown (written via
+// the $DROPSTEP_STEP_SUMMARY env var every runner exposes to its subprocess), and any warnings or
+// errors the step raised while it ran.
+package summary
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EnvVar is the environment variable every step runner sets for its subprocess, pointing at a
+// per-step scratch file the step can write Markdown into to contribute a section to the run's
+// report.
+const EnvVar = "DROPSTEP_STEP_SUMMARY"
+
+// StepSummaryPath returns the per-step scratch file a runner should point EnvVar at before
+// starting its subprocess.
+func StepSummaryPath(baseDir, runID, stepID string) string {
+	return filepath.Join(baseDir, runID, stepID+"-summary.md")
+}
+
+// ReportPath returns the path of the run-level Markdown report.
+func ReportPath(baseDir, runID string) string {
+	return filepath.Join(baseDir, runID, "summary.md")
+}
+
+// Meta is the run-wide metadata rendered at the top of the report.
+type Meta struct {
+	WorkflowName string
+	// Inputs holds the workflow's resolved input values, already redacted by the caller.
+	Inputs    map[string]string
+	StartedAt time.Time
+	// Providers lists the distinct provider types (e.g. "openai") used by the run's browser_agent
+	// steps.
+	Providers []string
+}
+
+// StepEntry is one step's contribution to the report.
+type StepEntry struct {
+	StepID   string
+	Uses     string
+	Status   string // "ok", "failed", or "skipped" (cache hit)
+	Duration time.Duration
+	// Body is the Markdown read from the step's DROPSTEP_STEP_SUMMARY file, if it wrote one.
+	Body string
+	// Issues holds the WARN/ERROR/FATAL log lines raised while the step ran, as captured by
+	// sinks.SummarySink.
+	Issues []string
+}
+
+// Report accumulates a run's Meta and StepEntries and renders them to Markdown. Report is not
+// safe for concurrent use; the engine runs steps sequentially.
+type Report struct {
+	Meta  Meta
+	Steps []StepEntry
+}
+
+func NewReport(meta Meta) *Report {
+	return &Report{Meta: meta}
+}
+
+func (r *Report) AddStep(entry StepEntry) {
+	r.Steps = append(r.Steps, entry)
+}
+
+// Flush renders the report so far and writes it to ReportPath(baseDir, runID), overwriting any
+// previous version. The engine calls this after every step completes, so a run killed partway
+// through still leaves a readable summary of the steps that did finish. A full rewrite (rather
+// than a true append) is necessary because the table of contents at the top of the file grows
+// with every step.
+func (r *Report) Flush(baseDir, runID string) error {
+	path := ReportPath(baseDir, runID)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("creating summary directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(r.Render()), 0644); err != nil {
+		return fmt.Errorf("writing summary report %q: %w", path, err)
+	}
+	return nil
+}
+
+// Render builds the full Markdown document: a table of contents, run metadata, then one section
+// per step.
+func (r *Report) Render() string {
+	var b strings.Builder
+
+	title := r.Meta.WorkflowName
+	if title == "" {
+		title = "Workflow run"
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+
+	b.WriteString("## Table of Contents\n\n")
+	for _, s := range r.Steps {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", s.StepID, anchor(s.StepID))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("## Run metadata\n\n")
+	fmt.Fprintf(&b, "- **Started:** %s\n", r.Meta.StartedAt.Format(time.RFC3339))
+	if len(r.Meta.Providers) > 0 {
+		fmt.Fprintf(&b, "- **Providers used:** %s\n", strings.Join(r.Meta.Providers, ", "))
+	}
+	if len(r.Meta.Inputs) > 0 {
+		b.WriteString("- **Inputs:**\n")
+		keys := make([]string, 0, len(r.Meta.Inputs))
+		for k := range r.Meta.Inputs {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&b, "  - `%s`: %s\n", k, r.Meta.Inputs[k])
+		}
+	}
+	b.WriteString("\n")
+
+	for _, s := range r.Steps {
+		fmt.Fprintf(&b, "## %s\n\n", s.StepID)
+		fmt.Fprintf(&b, "**Status:** %s &middot; **Type:** `%s` &middot; **Duration:** %s\n\n",
+			statusBadge(s.Status), s.Uses, s.Duration.Round(time.Millisecond))
+
+		if len(s.Issues) > 0 {
+			b.WriteString("### Issues\n\n")
+			for _, issue := range s.Issues {
+				fmt.Fprintf(&b, "- %s\n", issue)
+			}
+			b.WriteString("\n")
+		}
+
+		if s.Body != "" {
+			b.WriteString(strings.TrimRight(s.Body, "\n"))
+			b.WriteString("\n\n")
+		}
+	}
+
+	return b.String()
+}
+
+func statusBadge(status string) string {
+	switch status {
+	case "ok":
+		return "✅ ok"
+	case "failed":
+		return "❌ failed"
+	case "skipped":
+		return "⏭️ skipped (cache hit)"
+	default:
+		return status
+	}
+}
+
+// RenderAgentDefault builds the browser_agent runner's fallback step summary, used when the agent
+// itself didn't write anything to its DROPSTEP_STEP_SUMMARY file: the final structured output as a
+// fenced JSON block, plus a table of any uploaded files.
+func RenderAgentDefault(outputJSON []byte, uploadNames []string) string {
+	var b strings.Builder
+
+	b.WriteString("```json\n")
+	b.Write(outputJSON)
+	b.WriteString("\n```\n")
+
+	if len(uploadNames) > 0 {
+		b.WriteString("\n| Uploaded file |\n| --- |\n")
+		for _, name := range uploadNames {
+			fmt.Fprintf(&b, "| %s |\n", name)
+		}
+	}
+
+	return b.String()
+}
+
+// anchor approximates GitHub's Markdown heading-to-anchor slugification closely enough for a
+// report meant to be read in a browser or a Markdown previewer.
+func anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ' || r == '_':
+			b.WriteRune('-')
+		case r == '-' || (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
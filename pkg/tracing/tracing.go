@@ -0,0 +1,74 @@
+// Package tracing wires up OpenTelemetry for a workflow run: a root span per
+// core.WorkflowEngine.ExecuteWorkflow call, a child span per step, and (for HttpRunner) an outbound
+// span with W3C traceparent propagation into the request. Init's Config.OTLPEndpoint is the only
+// knob: empty leaves the global TracerProvider at its otel-default no-op implementation, so a user
+// who never configures a collector pays no cost beyond the otel API's own negligible overhead.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Config configures the OTLP exporter Init registers as the global TracerProvider.
+type Config struct {
+	// OTLPEndpoint is the collector's OTLP/HTTP endpoint (host:port, no scheme), e.g.
+	// "localhost:4318". Empty disables exporting entirely; Init then leaves the global
+	// TracerProvider untouched (otel's own no-op default).
+	OTLPEndpoint string
+	// Insecure disables TLS when talking to OTLPEndpoint. Most local collectors run without TLS.
+	Insecure bool
+	// ServiceName identifies this process's spans in the collector's backend.
+	ServiceName string
+}
+
+// Init registers an OTLP-exporting TracerProvider as the global default and returns a shutdown
+// func the caller must invoke before exiting (flushes any spans still buffered). If cfg.OTLPEndpoint
+// is empty, Init does nothing and returns a no-op shutdown func.
+func Init(ctx context.Context, cfg Config) (shutdown func(context.Context) error, err error) {
+	if cfg.OTLPEndpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.OTLPEndpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP exporter for endpoint %q: %w", cfg.OTLPEndpoint, err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "dropstep"
+	}
+	res, err := resource.New(ctx, resource.WithAttributes(semconv.ServiceName(serviceName)))
+	if err != nil {
+		return nil, fmt.Errorf("building OTel resource: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return tp.Shutdown, nil
+}
+
+// Tracer is the single otel.Tracer the engine and every runner instrument spans against; resolved
+// lazily against whatever global TracerProvider Init (or nothing, for the no-op default) installed.
+func Tracer() trace.Tracer {
+	return otel.Tracer("github.com/arnavsurve/dropstep")
+}
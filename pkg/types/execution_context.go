@@ -1,11 +1,65 @@
 package types
 
+import "context"
+
 // ExecutionContext contains the context needed for step execution
 type ExecutionContext struct {
 	Step        Step
 	Logger      Logger // Assuming Logger is defined in types.log_types.go
 	WorkflowDir string
 	APIKey      string
+
+	// Ctx carries the step's OpenTelemetry span (a child of the workflow's root span; see
+	// pkg/tracing and core.WorkflowEngine.executeStep) plus whatever deadline/cancellation the
+	// engine derived from the workflow/step `timeout:`. A runner that makes its own outbound calls
+	// (e.g. HttpRunner) should start any child spans against this rather than context.Background(),
+	// so they show up nested under the step's span. Nil only in tests that construct
+	// ExecutionContext directly without going through the engine.
+	Ctx context.Context
+
+	// AddMask, if set, registers a value discovered at runtime (e.g. via a step's
+	// "::add-mask::" workflow command) to be redacted from all subsequent log output for the
+	// rest of the run. It is nil when the caller has no redactor to register against.
+	AddMask func(secret string)
+
+	// AddPath, if set, registers a directory discovered at runtime (e.g. via a step's
+	// "::add-path::" workflow command) to be prepended to every later step's subprocess PATH for
+	// the rest of the run. PathDirs returns what's been registered so far, for a runner building
+	// its own subprocess's PATH. Both nil when the caller has no accumulator to register against.
+	AddPath  func(dir string)
+	PathDirs func() []string
+
+	// EvalExpr, if set, resolves a dotted reference (inputs.*, steps.<id>.output.*,
+	// steps.<id>.status, env.*) or a full pkg/expr boolean/arithmetic expression against the run's
+	// inputs and every step result so far, the same namespace `{{ }}` templates and `if:`/`unless:`
+	// already read from. Used by the `assert` step runner, which needs a typed value back rather
+	// than templating's stringified substitution. Nil in tests that construct ExecutionContext
+	// directly without going through the engine.
+	EvalExpr func(expr string) (any, error)
+
+	// StepSummaryPath, if set, is the per-step scratch file the runner should point the
+	// $DROPSTEP_STEP_SUMMARY env var at before starting its subprocess (see pkg/summary). Empty
+	// disables step summaries for this step.
+	StepSummaryPath string
+
+	// SecretMountEnv holds one "DROPSTEP_SECRET_<NAME>=<path>" entry per entry in
+	// Step.SecretMounts, already staged to a private file by pkg/secretmount. A subprocess-based
+	// runner (e.g. shell) should append these to its child process's environment rather than
+	// interpolating the underlying secret values into argv or command text. Empty if the step has
+	// no SecretMounts.
+	SecretMountEnv []string
+
+	// NoProgress disables a runner's live progress bar (e.g. --no-progress), even when running in
+	// a TTY. Silent additionally suppresses its own non-error informational output. Runners that
+	// don't render a progress bar or extra output can ignore both.
+	NoProgress bool
+	Silent     bool
+
+	// MatrixVars holds this invocation's matrix coordinates (e.g. {"region": "us"}) when Step is
+	// one cell of a `matrix:` expansion; nil otherwise. A shell/python runner exposes each entry to
+	// its subprocess as DROPSTEP_MATRIX_<NAME>=<value> (uppercased), on top of the {{ matrix.<key>
+	// }} templating already resolved into Step's fields before the runner ever sees them.
+	MatrixVars map[string]string
 }
 
 // Step represents a workflow step
@@ -15,16 +69,277 @@ type Step struct {
 	Provider      string        `yaml:"provider,omitempty"`
 	Command       *CommandBlock `yaml:"run,omitempty"`
 	Call          *HTTPCall     `yaml:"call,omitempty"`
+	Workflow      *WorkflowCall `yaml:"workflow,omitempty"`
 	BrowserConfig BrowserConfig `yaml:"browser,omitempty"`
 	MaxFailures   *int          `yaml:"max_failures,omitempty"`
 	Timeout       string        `yaml:"timeout,omitempty"`
+	// DependsOn lists step IDs whose results factor into this step's cache digest. When the
+	// workflow sets `parallel: N` above 1, it also orders execution: the engine won't start this
+	// step until every entry here has completed, and otherwise runs independent steps concurrently.
+	DependsOn []string `yaml:"depends_on,omitempty"`
+	Cache     *bool    `yaml:"cache,omitempty"` // if false, always re-run this step; defaults to true
+
+	// If, when set, is a boolean expression (see pkg/expr) evaluated against inputs, prior step
+	// outputs, and env vars after variable resolution. A false result skips the step without
+	// running its handler; DependsOn should still be used to order the skip relative to the steps
+	// it reads from.
+	If string `yaml:"if,omitempty"`
+	// Unless is If's inverse: the step is skipped when this expression evaluates true, rather than
+	// when it evaluates false. If and Unless may both be set; either one resolving to "skip" skips
+	// the step.
+	Unless string `yaml:"unless,omitempty"`
+
+	// On restricts which trigger events (e.g. "schedule", "webhook", "manual") include this step
+	// in the plan; see pkg/planner. Empty means the step runs under every trigger the workflow
+	// itself accepts.
+	On []string `yaml:"on,omitempty"`
+
+	// Artifacts lists files/directories this step produces, to be archived and made available to
+	// later steps via their artifact_deps.
+	Artifacts []ArtifactSpec `yaml:"artifacts,omitempty"`
+	// ArtifactDeps lists artifacts produced by earlier steps to extract into this step's working
+	// directory before it runs. Named artifact_deps rather than `uses:`, since that key is already
+	// the step type selector above.
+	ArtifactDeps []ArtifactUse `yaml:"artifact_deps,omitempty"`
+
+	// SecretMounts stages named secrets to private files instead of interpolating them into
+	// Command.Inline/argv or the browser_agent prompt, so they never appear in a process listing
+	// or in logs the redactor hasn't seen yet. See pkg/secretmount.
+	SecretMounts []SecretMount `yaml:"secret_mounts,omitempty"`
+
+	// Assertions declares checks against a shell/python step's result, evaluated after the
+	// process exits; see pkg/assertions and Assertion. Only shell and python steps support this.
+	Assertions []Assertion `yaml:"assertions,omitempty"`
+
+	// Matrix, if set, expands this single step declaration into one execution per entry of the
+	// cartesian product of its value lists (e.g. {"region": ["us","eu"], "shard": ["0","1"]} runs
+	// 4 times), each with {{ matrix.<key> }} available for templating in Command.Inline/Path and
+	// a DROPSTEP_MATRIX_<KEY> env var exposed to the subprocess. The engine aggregates every
+	// cell's StepResult into one entry in stepResults under this step's ID; see
+	// StepResult.MatrixResults. Nil/empty runs the step once, exactly as before this field existed.
+	Matrix map[string][]string `yaml:"matrix,omitempty"`
+	// Parallelism caps how many matrix cells run at once; <= 0 (the default) runs every cell
+	// concurrently. Ignored when Matrix is empty.
+	Parallelism int `yaml:"parallelism,omitempty"`
+	// FailFast cancels any matrix cells still running as soon as one fails; nil or true is the
+	// default, matching how the engine already cancels sibling steps on a workflow's `parallel: N`
+	// path. Set false to let every cell run to completion regardless of earlier failures. Ignored
+	// when Matrix is empty.
+	FailFast *bool `yaml:"fail_fast,omitempty"`
+
+	// Asserts declares the checks a `uses: assert` step runs; see AssertCheck. Rejected on every
+	// other step type, the same way BrowserConfig.Prompt is rejected outside `uses: browser_agent`.
+	Asserts []AssertCheck `yaml:"asserts,omitempty"`
+}
+
+// SecretMount declares a workflow input whose resolved value should be staged to a private file
+// rather than templated directly into a step's fields. A shell step can read the value from the
+// file at DROPSTEP_SECRET_<NAME>; a browser_agent step can point an agent at it with
+// `{{ secretPath "name" }}` in its prompt.
+type SecretMount struct {
+	Name string `yaml:"name"`
+	// InputRef is the name of the workflow input (typically one with `secret: true`) whose
+	// resolved value is staged.
+	InputRef string `yaml:"input_ref"`
+	// MountPath, if set, overrides the default path (a file named Name inside a private, per-run
+	// staging directory).
+	MountPath string `yaml:"mount_path,omitempty"`
+}
+
+// ArtifactSpec declares a named artifact a step produces.
+type ArtifactSpec struct {
+	Name string `yaml:"name"`
+	Path string `yaml:"path"` // file, directory, or glob, resolved relative to the workflow file
+	// Via selects how Path is populated before archiving: "file" (the default) and "dir" both just
+	// expect Path to already exist on disk when the step finishes (a directory is tar-gzipped
+	// automatically); "stdout" has a shell/python step write its own captured stdout to Path
+	// instead of promoting it to the step's structured Output.
+	Via string `yaml:"via,omitempty"`
+	// Retention is advisory today (e.g. "7d", "forever"); nothing prunes archives on a schedule yet.
+	Retention string `yaml:"retention,omitempty"`
+}
+
+// ArtifactUse references a named artifact produced by an earlier step.
+type ArtifactUse struct {
+	Name string `yaml:"name"`
+	Step string `yaml:"step"` // ID of the step that produced the artifact
 }
 
 // CommandBlock represents a shell or python script to run
 type CommandBlock struct {
-	Path        string `yaml:"path"`
-	Inline      string `yaml:"inline"`
-	Interpreter string `yaml:"interpreter,omitempty"`
+	// Path runs a script already on disk, resolved relative to the workflow file like other
+	// workflow-relative paths. It may be a glob pattern (matched with filepath.Glob, so `*`/`?`/
+	// character classes but no doublestar `**`); if it matches more than one file, every match
+	// runs in lexical order and the step's result aggregates them instead of returning one plain
+	// stdout. See Paths for an explicit list instead of a pattern.
+	Path   string `yaml:"path"`
+	Inline string `yaml:"inline"`
+	// Paths is an alternative to Path: an explicit, ordered list of scripts to run instead of a
+	// single path or glob pattern. Mutually exclusive with both Inline and Path.
+	Paths       []string `yaml:"paths,omitempty"`
+	Interpreter string   `yaml:"interpreter,omitempty"`
+	// ContinueOnError, for a step that runs more than one script (Path matching multiple files, or
+	// Paths), lets every script run even after one of them exits non-zero, rather than stopping at
+	// the first failure. The step still fails overall if any script did. Rejected for a
+	// single-script step, where there's nothing left to continue past.
+	ContinueOnError bool `yaml:"continue_on_error,omitempty"`
+	// Remote, if set, dispatches this step through pkg/steprunner/remote instead of running it on
+	// the local host, the same content-addressed mechanism as a workflow's execution.remote block
+	// but scoped to this one step. Takes precedence over execution.remote when both are set.
+	Remote *CommandRemoteConfig `yaml:"remote,omitempty"`
+	// Resources constrains how the process itself runs (env exposure, cwd, scheduling priority,
+	// memory/CPU ceilings, and filesystem sandboxing). Nil means none of that: the child inherits
+	// the full parent environment with no rlimits or sandbox, exactly as before this field existed.
+	Resources *ResourceLimits `yaml:"resources,omitempty"`
+	// Container, if set, runs this command inside a container image via `container_shell` instead
+	// of directly on the host (see ContainerConfig). Mutually relevant only to steps that use
+	// `uses: container_shell`; ShellRunner and the other interpreter-based runners ignore it.
+	Container *ContainerConfig `yaml:"container,omitempty"`
+}
+
+// ResourceLimits constrains how a shell/python step's process actually runs. It's deliberately
+// separate from a command's own `timeout:` (see Step.Timeout), which already bounds the step via a
+// context deadline; ResourceLimits.Sandbox and the env/rlimit knobs here are about what the process
+// can see and consume while it runs, not how long it's allowed to run.
+type ResourceLimits struct {
+	// Env scopes which parent environment variables the child inherits; nil means inherit
+	// everything, unchanged from before this field existed.
+	Env *EnvScope `yaml:"env,omitempty"`
+	// Cwd overrides the child's working directory, resolved relative to the workflow file like
+	// other paths. Empty leaves the working directory as the runner already sets it today.
+	Cwd string `yaml:"cwd,omitempty"`
+	// Nice sets the child's scheduling priority (-20 highest .. 19 lowest), applied via the `nice`
+	// command since os/exec has no per-child hook to call setpriority(2) between fork and exec.
+	Nice *int `yaml:"nice,omitempty"`
+	// IONice sets the child's I/O scheduling class (0=none, 1=realtime, 2=best-effort, 3=idle) via
+	// the `ionice` command. Linux-only; set on another platform is an error rather than a silent
+	// no-op.
+	IONice *int `yaml:"ionice,omitempty"`
+	// MaxMemoryMB caps the child's address space in megabytes (RLIMIT_AS, applied via `ulimit -v`).
+	// 0 means unlimited.
+	MaxMemoryMB int64 `yaml:"max_memory_mb,omitempty"`
+	// MaxCPUSeconds caps the child's consumed CPU time in seconds (RLIMIT_CPU, applied via
+	// `ulimit -t`). 0 means unlimited.
+	MaxCPUSeconds int64 `yaml:"max_cpu_seconds,omitempty"`
+	// Sandbox, if set, runs the command inside an OS filesystem sandbox rooted at the workflow
+	// directory instead of running it directly.
+	Sandbox *SandboxConfig `yaml:"sandbox,omitempty"`
+}
+
+// EnvScope allow/deny-lists which parent environment variables a child process inherits. Allow, if
+// non-empty, is applied first (only these survive); Deny is then applied on top of that (or on the
+// full parent environment, if Allow is empty), stripping out any of these names. That lets Deny be
+// used alone to carve a couple of names (e.g. a credential) out of an otherwise fully-inherited
+// environment, or layered under Allow to carve exceptions out of a strict allow-list.
+type EnvScope struct {
+	Allow []string `yaml:"allow,omitempty"`
+	Deny  []string `yaml:"deny,omitempty"`
+}
+
+// SandboxConfig wraps a command's process in a filesystem sandbox. Backend selects the wrapper:
+// "bwrap" or "firejail" on Linux, "sandbox-exec" on macOS; empty auto-selects based on the host OS.
+// The workflow directory is always made available read-write inside the sandbox (steps need to
+// write their own outputs there); ReadOnly and ReadWrite list additional host paths, resolved
+// relative to the workflow file, to expose alongside it.
+type SandboxConfig struct {
+	Backend   string   `yaml:"backend,omitempty"`
+	ReadOnly  []string `yaml:"read_only,omitempty"`
+	ReadWrite []string `yaml:"read_write,omitempty"`
+}
+
+// ContainerConfig selects and configures the container a `container_shell` step runs its command
+// in. Image is the only required field; everything else has a secure-by-default value so a
+// workflow author has to opt into anything riskier than "no network, current user, workflow dir
+// mounted read-write."
+type ContainerConfig struct {
+	// Image is the container image to run the command in, e.g. "python:3.12-slim".
+	Image string `yaml:"image"`
+	// Workdir overrides the in-container working directory. Empty defaults to the workflow
+	// directory's own path, mounted at the same path inside the container (see ContainerShellRunner),
+	// so relative paths in the command behave the same as they would outside a container.
+	Workdir string `yaml:"workdir,omitempty"`
+	// Env sets additional environment variables inside the container, on top of the ones every
+	// script runner sets (DROPSTEP_OUTPUT, secret mounts, matrix vars, etc.).
+	Env map[string]string `yaml:"env,omitempty"`
+	// Mounts lists additional host paths to bind into the container, beyond the workflow
+	// directory (always mounted read-write). Host is resolved relative to the workflow file like
+	// other paths, and must not resolve outside the workflow directory.
+	Mounts []ContainerMount `yaml:"mounts,omitempty"`
+	// Network sets the container's network mode, passed straight through to `docker/podman run
+	// --network`. Empty defaults to "none": a container step has to opt into network access
+	// explicitly, rather than getting it by default the way a host shell step already does.
+	Network string `yaml:"network,omitempty"`
+	// User sets the in-container user, passed straight through to `docker/podman run --user`
+	// (e.g. "1000:1000"). Empty runs as the image's own default user.
+	User string `yaml:"user,omitempty"`
+	// PullPolicy is passed straight through to `docker/podman run --pull`: "always", "missing", or
+	// "never". Empty defaults to "missing" (docker/podman's own default): pull only if the image
+	// isn't already present locally.
+	PullPolicy string `yaml:"pull_policy,omitempty"`
+	// Engine picks the container CLI to shell out to: "docker" or "podman". Empty falls back to
+	// DROPSTEP_CONTAINER_ENGINE, then auto-detects whichever of the two is found first in PATH.
+	Engine string `yaml:"engine,omitempty"`
+	// Resources caps the container's own CPU/memory, passed straight through to `docker/podman run
+	// --cpus`/`--memory`. This is the container_shell equivalent of CommandBlock.Resources'
+	// MaxMemoryMB/MaxCPUSeconds for a host process; the two are mutually exclusive (see
+	// ContainerShellRunner.Validate) since a container_shell step's command never runs on the host.
+	Resources *ContainerResources `yaml:"resources,omitempty"`
+	// ExtraArgs passes additional flags straight through to `docker/podman run`, inserted right
+	// after the flags ContainerShellRunner builds from the rest of this struct (--pull, --network,
+	// --workdir, --user, --cpus, --memory). A flag here that names the same thing as one of those
+	// (e.g. "--network=host") suppresses dropstep's own default instead of being passed twice; see
+	// argutil.MergeArgs.
+	ExtraArgs []string `yaml:"extra_args,omitempty"`
+}
+
+// ContainerResources limits what a container_shell step's container may consume, passed straight
+// through to the container engine rather than enforced by dropstep itself.
+type ContainerResources struct {
+	// CPUs caps the number of CPUs the container may use, e.g. "1.5" (docker/podman run --cpus).
+	// Empty leaves it unlimited.
+	CPUs string `yaml:"cpus,omitempty"`
+	// MemoryMB caps the container's memory in megabytes (docker/podman run --memory). 0 leaves it
+	// unlimited.
+	MemoryMB int64 `yaml:"memory_mb,omitempty"`
+}
+
+// ContainerMount binds a host path into a container_shell step's container, alongside the
+// workflow directory that's always mounted.
+type ContainerMount struct {
+	Host      string `yaml:"host"`
+	Container string `yaml:"container"`
+	ReadOnly  bool   `yaml:"read_only,omitempty"`
+}
+
+// CommandRemoteConfig is a CommandBlock's per-step equivalent of the workflow-level
+// execution.remote block (see core.RemoteExecutionConfig); the two are kept as separate types
+// since CommandBlock lives in this package and Workflow lives in pkg/core.
+type CommandRemoteConfig struct {
+	Endpoint     string            `yaml:"endpoint"`
+	InstanceName string            `yaml:"instance_name,omitempty"`
+	TLS          bool              `yaml:"tls,omitempty"`
+	Platform     map[string]string `yaml:"platform,omitempty"`
+	// CacheOnly makes a content-addressed cache miss an error instead of falling back to running
+	// the step locally; see pkg/steprunner/remote's package doc for why that's the fallback today.
+	CacheOnly bool `yaml:"cache_only,omitempty"`
+	// Timeout bounds this step's remote dispatch attempt (e.g. "5m"), separately from the step's
+	// own `timeout:`. Parsed with time.ParseDuration. Empty means no separate deadline.
+	Timeout string `yaml:"timeout,omitempty"`
+	// OutputFiles and OutputDirectories declare the workflow-relative paths a remote worker would
+	// be expected to produce, mirroring REv2's Action.output_files/output_directories. They
+	// participate in the step's action digest (so declaring a different set of outputs is a cache
+	// miss even with identical inputs), but aren't fetched from anywhere yet: see
+	// pkg/steprunner/remote's package doc for why actually dispatching to a worker isn't
+	// implemented yet.
+	OutputFiles       []string `yaml:"output_files,omitempty"`
+	OutputDirectories []string `yaml:"output_directories,omitempty"`
+	// SkipCacheLookup bypasses the content-addressed cache read, forcing the step to (fall back to)
+	// running even if a cached result already exists for its action digest. The result is still
+	// written to the cache afterward unless DoNotCache is also set.
+	SkipCacheLookup bool `yaml:"skip_cache_lookup,omitempty"`
+	// DoNotCache suppresses writing this step's result to the cache after it runs, e.g. for a step
+	// whose output is only valid for this one run (a timestamp, a random ID).
+	DoNotCache bool `yaml:"do_not_cache,omitempty"`
 }
 
 // HTTPCall represents an HTTP request
@@ -32,7 +347,105 @@ type HTTPCall struct {
 	Method  string            `yaml:"method"`
 	Url     string            `yaml:"url"`
 	Headers map[string]string `yaml:"headers"`
-	Body    map[string]any    `yaml:"body"`
+	// Body is the request body, whose meaning depends on BodyType: form fields for "form", fields
+	// for the non-file parts of a "multipart" body, or the document to marshal to JSON for "json"
+	// (the default). Ignored when BodyType is "raw" (use RawBody instead).
+	Body map[string]any `yaml:"body"`
+	// BodyType selects how Body/RawBody/Files are encoded into the request: "json" (the default,
+	// today's only behavior), "form" (application/x-www-form-urlencoded, encoding Body's values),
+	// "multipart" (multipart/form-data, combining Body's values with Files), or "raw" (RawBody sent
+	// verbatim, with Content-Type left to Headers).
+	BodyType string `yaml:"body_type,omitempty"`
+	// RawBody is the literal request body for BodyType "raw". Ignored otherwise.
+	RawBody string `yaml:"raw_body,omitempty"`
+	// Files maps a multipart field name to a local file path, resolved against WorkflowDir, whose
+	// contents are streamed into that field without buffering the whole file in memory. Only
+	// meaningful when BodyType is "multipart".
+	Files map[string]string `yaml:"files,omitempty"`
+	// Retry, if set, makes HttpRunner retry a failed request with exponential backoff instead of
+	// returning after the first attempt. Nil (the default) preserves today's single-attempt
+	// behavior.
+	Retry *HTTPRetryPolicy `yaml:"retry,omitempty"`
+	// Assert, if set, is evaluated against the response HttpRunner receives (after any retries);
+	// a step whose checks don't all hold fails, with each failure recorded in
+	// StepResult.AssertionFailures. Nil (the default) preserves today's behavior of never failing
+	// on a non-2xx status, leaving that up to the caller's own `if`/assertions on status_code.
+	Assert *HTTPAssert `yaml:"assert,omitempty"`
+	// Extract maps a name to a dotted jsonpath expression (e.g. "data.items.0.id") into the
+	// parsed response body; each resolved value is exposed to later steps as
+	// {{ steps.<id>.output.extracted.<name> }}, so they don't need to navigate the raw response
+	// shape themselves. A name whose path doesn't resolve is simply omitted, not an error.
+	Extract map[string]string `yaml:"extract,omitempty"`
+	// SaveTo, if set, streams the response body straight to this path (resolved against
+	// WorkflowDir) instead of buffering it into Output, and records the resolved path in
+	// StepResult.OutputFile. Output then carries only status_code, headers, and body_size, not the
+	// body itself. Use this for large or binary downloads that later steps just want to read off
+	// disk (e.g. a shell step's `cat`) rather than re-serialize through a step output.
+	SaveTo string `yaml:"save_to,omitempty"`
+	// MaxBodyBytes caps how much of the response body HttpRunner will buffer into memory when
+	// SaveTo is empty. A response exceeding this cap fails the step with a clear error rather than
+	// reading the whole thing into Output. Ignored when SaveTo is set, since that path streams to
+	// disk regardless of size. Zero (the default) means no cap.
+	MaxBodyBytes int64 `yaml:"max_body_bytes,omitempty"`
+}
+
+// HTTPAssert declares checks against an http step's response, evaluated once HttpRunner has a
+// final response (after any Retry attempts). Every check must hold for the step to succeed;
+// StatusIn is most often sufficient on its own, with HeaderMatches and JSONPath available for
+// tighter checks without a later step having to template into the raw response.
+type HTTPAssert struct {
+	// StatusIn requires the response status code be one of these values.
+	StatusIn []int `yaml:"status_in,omitempty"`
+	// HeaderMatches requires each named response header equal this exact value (header name
+	// lookup is case-insensitive, matching net/http.Header's own canonicalization).
+	HeaderMatches map[string]string `yaml:"header_matches,omitempty"`
+	// JSONPath checks a dotted path into the parsed response body (e.g. "data.items.0.id")
+	// against Expected (an exact match) or Matches (a regex the stringified value must match).
+	// Exactly one of Expected or Matches should be set per check.
+	JSONPath []HTTPJSONPathCheck `yaml:"jsonpath,omitempty"`
+}
+
+// HTTPJSONPathCheck is one check in HTTPAssert.JSONPath.
+type HTTPJSONPathCheck struct {
+	Path     string `yaml:"path"`
+	Expected any    `yaml:"expected,omitempty"`
+	Matches  string `yaml:"matches,omitempty"`
+}
+
+// HTTPRetryPolicy configures HttpRunner's retry behavior for one HTTPCall. Unset fields fall back
+// to sensible defaults (see runners.defaultRetry* constants) rather than disabling retry entirely;
+// set HTTPCall.Retry to nil to opt out of retrying altogether.
+type HTTPRetryPolicy struct {
+	// MaxAttempts is the total number of attempts (including the first), not the number of
+	// retries. <= 0 falls back to the default.
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+	// InitialBackoff and MaxBackoff are Go duration strings (e.g. "500ms", "30s") bounding the
+	// delay before the 2nd attempt and every attempt thereafter, respectively. Empty falls back to
+	// the default.
+	InitialBackoff string `yaml:"initial_backoff,omitempty"`
+	MaxBackoff     string `yaml:"max_backoff,omitempty"`
+	// Multiplier scales the backoff after each failed attempt (backoff *= Multiplier). <= 0 falls
+	// back to the default.
+	Multiplier float64 `yaml:"multiplier,omitempty"`
+	// Jitter adds up to +/-50% uniform random jitter to each computed backoff, to avoid many
+	// retrying clients synchronizing on the same endpoint.
+	Jitter bool `yaml:"jitter,omitempty"`
+	// RetryOn lists which failure categories are retryable: "5xx" (status 500-599), "429" (status
+	// 429 specifically, since it often wants different handling than a generic 5xx), "network"
+	// (the request never got a response, e.g. connection refused/reset), and "timeout" (the
+	// per-attempt context deadline was exceeded). Empty falls back to retrying all four.
+	RetryOn []string `yaml:"retry_on,omitempty"`
+}
+
+// WorkflowCall declares a `uses: workflow` step's sub-workflow invocation. Path is resolved
+// relative to the parent's WorkflowDir, exactly like CommandBlock.Path; With declares the child's
+// input values, each a template resolved (via core.ResolveValue, like HTTPCall.Body) against the
+// parent's own variables and prior steps' results before the child runs. The child's own declared
+// `outputs:` (see core.Workflow.Outputs) come back as this step's Output map, the same way an http
+// step's Output is its parsed response body.
+type WorkflowCall struct {
+	Path string         `yaml:"path"`
+	With map[string]any `yaml:"with,omitempty"`
 }
 
 // FileToUpload represents a file to be uploaded
@@ -50,5 +463,8 @@ type BrowserConfig struct {
 	OutputSchemaFile  string         `yaml:"output_schema,omitempty"`
 	AllowedDomains    []string       `yaml:"allowed_domains,omitempty"`
 	MaxSteps          *int           `yaml:"max_steps,omitempty"`
+	// Engine selects the browser_agent backend: "python" (default, shells out to a Python venv) or
+	// "native" (runs in-process via playwright-go, no Python required). Falls back to
+	// DROPSTEP_BROWSER_ENGINE, then browseragent.DefaultBackend, when unset.
+	Engine string `yaml:"engine,omitempty"`
 }
-
@@ -4,4 +4,110 @@ package types
 type StepResult struct {
 	Output     any    `json:"output"`
 	OutputFile string `json:"output_file,omitempty"`
+
+	// Outputs holds ad hoc values a step exposed via "::set-output name=X::value" (or its
+	// DROPSTEP_OUTPUT file-based equivalent), addressable by later steps as
+	// {{ steps.<id>.outputs.X }}.
+	Outputs map[string]string `json:"outputs,omitempty"`
+
+	// ActionsRun, LLMTokens, PagesVisited, and Screenshots are populated for browser_agent steps
+	// from the agent's NDJSON event stream (see pkg/steprunner/runners/browseragent), and left
+	// zero-valued for every other step type.
+	ActionsRun   int      `json:"actions_run,omitempty"`
+	LLMTokens    int      `json:"llm_tokens,omitempty"`
+	PagesVisited int      `json:"pages_visited,omitempty"`
+	Screenshots  []string `json:"screenshots,omitempty"`
+
+	// AssertionFailures holds every Assertion from a shell/python step's `assertions:` that didn't
+	// hold (see pkg/assertions); nil when the step declared none, or declared some and they all
+	// passed.
+	AssertionFailures []AssertionFailure `json:"assertion_failures,omitempty"`
+
+	// Artifacts holds one entry per name in this step's `artifacts:`, keyed by ArtifactSpec.Name,
+	// populated once the engine has archived them (see pkg/artifacts). A later step references
+	// one as {{ steps.<id>.artifacts.<name>.path }}.
+	Artifacts map[string]ArtifactResult `json:"artifacts,omitempty"`
+
+	// MatrixResults holds one entry per cell of a step's `matrix:` expansion, in cartesian-product
+	// order (sorted by matrix key, then value index). Output above mirrors this as a plain []any
+	// list of each cell's own Output, so {{ steps.<id>.output }} works without needing to know
+	// about MatrixResults. Nil for a step with no matrix.
+	MatrixResults []MatrixCellResult `json:"matrix_results,omitempty"`
+}
+
+// MatrixCellResult is one cell's outcome from a step that declared a `matrix:`, identified by its
+// coordinates (e.g. {"region": "us", "shard": "0"}).
+type MatrixCellResult struct {
+	Coordinates map[string]string `json:"coordinates"`
+	Result      StepResult        `json:"result"`
+	// Error is the cell's failure message, if it failed; empty otherwise.
+	Error string `json:"error,omitempty"`
+}
+
+// ArtifactResult is what a later step can see about one of an earlier step's captured artifacts.
+type ArtifactResult struct {
+	// Path is the artifact's resolved location on disk in the workflow's own working directory
+	// (not the archived copy under .dropstep/artifacts), so it's directly usable as an input path
+	// to a later step without that step needing an artifact_deps entry first.
+	Path string `json:"path"`
+}
+
+// Assertion declares a declarative check against a shell/python step's result, run after the
+// process exits (see pkg/assertions). A step that declares any assertions fails if any of them
+// don't hold, regardless of its exit code.
+type Assertion struct {
+	// Path selects what to check: "exit_code", "stdout", "stderr", or a dotted path into the
+	// step's structured Output, prefixed with "result." (e.g. "result.items.#.id", where "#"
+	// collects a field across every element of an array, gjson-style).
+	Path string `yaml:"path"`
+	// Should names the check to run against Path's resolved value: ShouldEqual, ShouldNotEqual,
+	// ShouldContain (alias ShouldContainSubstring), ShouldContainKey, ShouldMatch (alias
+	// ShouldMatchRegex), ShouldBeGreaterThan, ShouldBeLessThan, ShouldHaveLength, ShouldBeEmpty,
+	// ShouldBeNil, ShouldBeType, or ShouldBeJSON. See pkg/assertions.IsKnownOperator for the
+	// authoritative list.
+	Should string `yaml:"should"`
+	// Expected is what Should compares the resolved value against. Unused by ShouldBeEmpty,
+	// ShouldBeNil, and ShouldBeJSON.
+	Expected any `yaml:"expected,omitempty"`
+}
+
+// AssertionFailure records one Assertion that didn't hold.
+type AssertionFailure struct {
+	Path     string `json:"path"`
+	Should   string `json:"should"`
+	Expected any    `json:"expected,omitempty"`
+	Actual   any    `json:"actual,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+// AssertCheck declares one check a `uses: assert` step runs (see AssertRunner). Unlike
+// Assertion.Path, which is scoped to one shell/python step's own result/stdout/stderr/exit_code,
+// Expr reaches anywhere `{{ }}` templates already do: inputs.*, steps.<id>.output.*,
+// steps.<id>.status, env.*, as well as pkg/expr's operators and built-in functions.
+type AssertCheck struct {
+	Expr string `yaml:"expr"`
+	// Should and Expected mean the same thing as Assertion's fields of the same name; see
+	// pkg/assertions.IsKnownOperator for the operator list.
+	Should string `yaml:"should"`
+	// Negate inverts the check's result, e.g. `should: ShouldContain, negate: true` for "does not
+	// contain". Unused by ShouldBeEmpty/ShouldBeNil/ShouldBeJSON in the same cases Expected is.
+	Negate bool `yaml:"negate,omitempty"`
+	// Expected is what Should compares Expr's resolved value against.
+	Expected any `yaml:"expected,omitempty"`
+	// Must, when explicitly set to false, makes a failing check log a warning and leave the step
+	// passing instead of failing it ("should:" semantics, as opposed to the default "must:"
+	// semantics a nil or true value gives every other check).
+	Must *bool `yaml:"must,omitempty"`
+}
+
+// AssertCheckResult records one AssertCheck's outcome, for both the step's StepResult.Output (so
+// a later step can branch on {{ steps.<id>.output.checks }}) and its log output.
+type AssertCheckResult struct {
+	Expr     string `json:"expr"`
+	Should   string `json:"should"`
+	Expected any    `json:"expected,omitempty"`
+	Actual   any    `json:"actual,omitempty"`
+	Must     bool   `json:"must"`
+	Passed   bool   `json:"passed"`
+	Reason   string `json:"reason,omitempty"`
 }